@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	alertFlash    = 400 * time.Millisecond // How long the header flashes
+	alertDebounce = time.Second            // Alerts in a burst closer than this are merged
+)
+
+// bellOut is where the terminal bell is rung, swapped out in tests
+var bellOut io.Writer = os.Stdout
+
+// alertBackground is the header color while an alert flashes
+var alertBackground = lipgloss.AdaptiveColor{Light: "160", Dark: "124"}
+
+// alertClearMsg ends the header flash started at the alert it belongs to
+type alertClearMsg struct {
+	until time.Time
+}
+
+// parseAlertLevel parses an --alert-on-level value, a level or "none"
+func parseAlertLevel(value string) (LogLevel, bool, error) {
+	if strings.EqualFold(strings.TrimSpace(value), "none") {
+		return INFO, false, nil
+	}
+	level, ok := levelByName(strings.TrimSpace(value))
+	if !ok {
+		return INFO, false, fmt.Errorf("unknown level %q in --alert-on-level (error, warn, info, debug or none)", value)
+	}
+	return level, true, nil
+}
+
+// alertsOn reports whether a new entry at level calls for an alert, which
+// it only does while tailing: browsing the list, the user is already looking
+func (m *UnifiedModel) alertsOn(level LogLevel) bool {
+	return m.config.AlertOn && m.tailing && level >= m.config.AlertLevel
+}
+
+// alertForEntries alerts if any of the streamed entries calls for it
+func (m *UnifiedModel) alertForEntries(entries []LogEntry) tea.Cmd {
+	for _, entry := range entries {
+		if m.alertsOn(entry.Level) {
+			return m.alert(time.Now())
+		}
+	}
+	return nil
+}
+
+// alertForLines alerts if any indexed line from first on calls for it
+func (m *UnifiedModel) alertForLines(first int) tea.Cmd {
+	if m.indexer == nil || !m.config.AlertOn || !m.tailing {
+		return nil
+	}
+	for i := first; i < m.totalLines; i++ {
+		if level, ok := m.lineLevel(i); ok && m.alertsOn(level) {
+			return m.alert(time.Now())
+		}
+	}
+	return nil
+}
+
+// alert flashes the header and rings the bell unless --no-bell, at most
+// once per alertDebounce so a burst of errors doesn't flash continuously
+func (m *UnifiedModel) alert(now time.Time) tea.Cmd {
+	if now.Sub(m.lastAlert) < alertDebounce {
+		return nil
+	}
+	m.lastAlert = now
+	m.alertUntil = now.Add(alertFlash)
+	if !m.config.NoBell {
+		bellOut.Write([]byte("\a"))
+	}
+
+	until := m.alertUntil
+	return tea.Tick(alertFlash, func(time.Time) tea.Msg {
+		return alertClearMsg{until: until}
+	})
+}
+
+// clearAlert ends the flash, unless a later alert has started its own
+func (m *UnifiedModel) clearAlert(msg alertClearMsg) {
+	if m.alertUntil.Equal(msg.until) {
+		m.alertUntil = time.Time{}
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAlert_FlashesOnNewErrors(t *testing.T) {
+	var bell bytes.Buffer
+	previous := bellOut
+	bellOut = &bell
+	t.Cleanup(func() { bellOut = previous })
+
+	model := NewUnifiedModel(&Config{MaxLines: 100, Timezone: "UTC", Tail: defaultTail, AlertOn: true, AlertLevel: ERROR})
+	receive := func(level LogLevel) bool {
+		model.stream.Push([]LogEntry{{Level: level, Message: "new"}})
+		_, cmd := model.Update(streamReadyMsg{})
+		return cmd != nil
+	}
+
+	if receive(WARN) || bell.Len() != 0 {
+		t.Fatal("Expected no alert below the level")
+	}
+	if !receive(ERROR) || bell.String() != "\a" || !time.Now().Before(model.alertUntil) {
+		t.Fatalf("Expected an ERROR to flash the header and ring the bell, got %q", bell.String())
+	}
+
+	// A burst alerts once
+	if receive(ERROR) || bell.Len() != 1 {
+		t.Error("Expected alerts within a second to be merged")
+	}
+
+	model.Update(alertClearMsg{until: model.alertUntil})
+	if !model.alertUntil.IsZero() {
+		t.Error("Expected the flash cleared")
+	}
+
+	// Not while browsing, and not rung with --no-bell
+	model.lastAlert = time.Time{}
+	model.tailing = false
+	if receive(ERROR) {
+		t.Error("Expected no alert while not tailing")
+	}
+	model.tailing = true
+	model.config.NoBell = true
+	if !receive(ERROR) || bell.Len() != 1 {
+		t.Error("Expected a flash without the bell")
+	}
+}
+
+func TestAlert_NewIndexedLines(t *testing.T) {
+	lines := numberedLines(20)
+	lines[15] = "2023-12-23 15:30:45 ERROR: disk full"
+	model := newIndexedTestModel(t, lines, 120, 30)
+	model.config.AlertOn, model.config.AlertLevel, model.config.NoBell = true, ERROR, true
+
+	if model.alertForLines(16) != nil {
+		t.Error("Expected no alert for INFO lines")
+	}
+	if model.alertForLines(10) == nil {
+		t.Error("Expected an alert for the new ERROR line")
+	}
+}
+
+func TestParseAlertLevel(t *testing.T) {
+	if level, on, err := parseAlertLevel("warn"); err != nil || !on || level != WARN {
+		t.Errorf("Expected WARN, got %v %v %v", level, on, err)
+	}
+	if _, on, err := parseAlertLevel("none"); err != nil || on {
+		t.Errorf("Expected none to turn alerts off, got %v %v", on, err)
+	}
+	if _, _, err := parseAlertLevel("loud"); err == nil || !strings.Contains(err.Error(), "--alert-on-level") {
+		t.Errorf("Expected an error naming the flag, got %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"io"
+	"os"
+	"time"
+)
+
+// tailPollInterval is how often a tailed file is checked for new lines once
+// everything in it was read
+const tailPollInterval = 200 * time.Millisecond
+
+// tailReader reads a file from its end on, like tail -f: at the end it waits
+// for more lines instead of returning io.EOF. A truncated file is read again
+// from the start and a rotated one from the start of the new file
+type tailReader struct {
+	filename string
+	file     *os.File
+	offset   int64
+	interval time.Duration
+}
+
+// newTailReader opens the file positioned at its current end
+func newTailReader(filename string) (*tailReader, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	offset, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &tailReader{filename: filename, file: file, offset: offset, interval: tailPollInterval}, nil
+}
+
+func (t *tailReader) Read(p []byte) (int, error) {
+	for {
+		n, err := t.file.Read(p)
+		t.offset += int64(n)
+		if n > 0 {
+			return n, nil
+		}
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+		time.Sleep(t.interval)
+		t.reopenIfReplaced()
+	}
+}
+
+// reopenIfReplaced starts over when the file was truncated, or when another
+// file took its name
+func (t *tailReader) reopenIfReplaced() {
+	current, err := t.file.Stat()
+	if err != nil {
+		return
+	}
+	if named, err := os.Stat(t.filename); err == nil && !os.SameFile(current, named) {
+		if file, err := os.Open(t.filename); err == nil {
+			t.file.Close()
+			t.file, t.offset = file, 0
+		}
+		return
+	}
+	if current.Size() < t.offset {
+		t.offset, _ = t.file.Seek(0, io.SeekStart)
+	}
+}
+
+// tailInto streams the lines appended to filename into the model, tagged
+// with the file as their source, next to piped input. A named pipe, such as
+// one an app's stderr is redirected to, is read from its writers instead
+func (a *UnifiedApp) tailInto(filename string) {
+	if isNamedPipe(filename) {
+		a.streamFrom(&pipeReader{path: filename, once: a.config.NoFollow}, filename)
+		return
+	}
+	reader, err := newTailReader(filename)
+	if err != nil {
+		a.send(loadFailedMsg{filename: filename, err: err})
+		return
+	}
+	a.streamFrom(reader, filename)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTailReader_FollowsAppendsAndTruncation(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(testFile, []byte("2023-12-23 15:30:45 INFO: before\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	reader, err := newTailReader(testFile)
+	if err != nil {
+		t.Fatalf("Failed to tail file: %v", err)
+	}
+	reader.interval = time.Millisecond
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(reader)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	next := func() string {
+		select {
+		case line := <-lines:
+			return line
+		case <-time.After(2 * time.Second):
+			t.Fatal("Timed out waiting for a tailed line")
+			return ""
+		}
+	}
+
+	// Existing lines are skipped, appended ones read
+	file, err := os.OpenFile(testFile, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open test file: %v", err)
+	}
+	file.WriteString("2023-12-23 15:30:46 ERROR: appended\n")
+	file.Close()
+	if line := next(); line != "2023-12-23 15:30:46 ERROR: appended" {
+		t.Errorf("Expected the appended line, got %q", line)
+	}
+
+	// A truncated file is read again from its start
+	if err := os.WriteFile(testFile, []byte("new\n"), 0644); err != nil {
+		t.Fatalf("Failed to truncate test file: %v", err)
+	}
+	if line := next(); line != "new" {
+		t.Errorf("Expected the line after truncation, got %q", line)
+	}
+}
+
+func TestStreamFrom_TagsEntriesWithTheFile(t *testing.T) {
+	app := NewUnifiedApp(&Config{MaxLines: 50, RefreshRate: 1, Timezone: "UTC", AlsoTail: []string{"app.log"}})
+	app.streamFrom(strings.NewReader("2023-12-23 15:30:45 ERROR: from the file"), "app.log")
+	app.model.Update(streamReadyMsg{})
+
+	if len(app.model.entries) != 1 || app.model.entries[0].Source != "app.log" {
+		t.Fatalf("Expected one entry from app.log, got %+v", app.model.entries)
+	}
+	if !app.model.isSource("app.log") {
+		t.Error("Expected a tailed file to count as a source for filters")
+	}
+}
+
+func TestAlsoTail_StreamedLinesAreRendered(t *testing.T) {
+	app := NewUnifiedApp(&Config{MaxLines: 50, RefreshRate: 1, Timezone: "UTC", AlsoTail: []string{"app.log"}, Tail: defaultTail})
+	app.model.Update(tea.WindowSizeMsg{Width: 160, Height: 30})
+	app.streamFrom(strings.NewReader("2023-12-23 15:30:45 INFO: console says hello"), "stdin")
+	app.streamFrom(strings.NewReader("2023-12-23 15:30:46 ERROR: file says goodbye"), "app.log")
+	app.model.Update(streamReadyMsg{})
+
+	view := app.model.View()
+	for _, want := range []string{"SOURCE", "console says hello", "file says goodbye", "app.log"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in the log stream, got:\n%s", want, view)
+		}
+	}
+
+	// Lines streamed later show up too
+	app.streamFrom(strings.NewReader("2023-12-23 15:30:47 WARN: file again"), "app.log")
+	app.model.Update(streamReadyMsg{})
+	if view := app.model.View(); !strings.Contains(view, "file again") || !strings.Contains(view, "Lines: 3/3") {
+		t.Errorf("Expected the new line and count, got:\n%s", view)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// toggleBookmark bookmarks the selected entry's file line, or removes its
+// bookmark. Bookmarks key on file lines so they outlive filter changes
+func (m *UnifiedModel) toggleBookmark() {
+	line, ok := m.selectedLine()
+	if !ok {
+		return
+	}
+	if m.bookmarks[line] {
+		delete(m.bookmarks, line)
+		m.notice = fmt.Sprintf("Removed bookmark on line %d", line+1)
+		return
+	}
+	if m.bookmarks == nil {
+		m.bookmarks = make(map[int]bool)
+	}
+	m.bookmarks[line] = true
+	m.notice = fmt.Sprintf("Bookmarked line %d", line+1)
+}
+
+// jumpToBookmark selects the nearest shown bookmarked entry after (direction
+// 1) or before (-1) the selection. Bookmarks hidden by the filters are skipped
+func (m *UnifiedModel) jumpToBookmark(direction int) {
+	if len(m.bookmarks) == 0 {
+		m.notice = "No bookmarks, m to add one"
+		return
+	}
+	current, ok := m.selectedLine()
+	if !ok {
+		return
+	}
+
+	lines := make([]int, 0, len(m.bookmarks))
+	for line := range m.bookmarks {
+		lines = append(lines, line)
+	}
+	sort.Ints(lines)
+	if direction < 0 {
+		sort.Sort(sort.Reverse(sort.IntSlice(lines)))
+	}
+
+	for _, line := range lines {
+		if (line-current)*direction <= 0 {
+			continue
+		}
+		pos := m.nearestPosition(line)
+		if m.filteredIndices[pos] == line {
+			m.followMatches = false
+			m.jumpToPosition(pos)
+			return
+		}
+	}
+
+	if direction > 0 {
+		m.notice = "No bookmarks after this one"
+	} else {
+		m.notice = "No bookmarks before this one"
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+// collapseRun is a row standing for consecutive entries with the same message
+type collapseRun struct {
+	count int // Entries in the run, 1 for a message that isn't repeated
+	last  int // File line of the last entry in the run
+}
+
+// toggleCollapse shows runs of consecutive entries with the same message as
+// a single row, or every entry again, keeping the selection on its entry
+func (m *UnifiedModel) toggleCollapse() {
+	selected, ok := m.selectedLine()
+	m.collapse = !m.collapse
+	m.applyFilters()
+	if !ok || m.tailing {
+		return
+	}
+
+	// The selected entry may now be inside a run, shown by its first entry
+	for pos, line := range m.filteredIndices {
+		if line == selected || (pos < len(m.runs) && line < selected && selected <= m.runs[pos].last) {
+			m.jumpToPosition(pos)
+			return
+		}
+	}
+}
+
+// collapseFiltered keeps only the first entry of each run of consecutive
+// entries with the same message in filteredIndices, remembering the runs.
+// It runs after every filter pass, so runs grow as new lines come in
+func (m *UnifiedModel) collapseFiltered() {
+	m.runs = nil
+	m.collapsed = 0
+	if !m.collapse || len(m.filteredIndices) == 0 {
+		return
+	}
+
+	matched := m.matchedLines()
+	kept := m.filteredIndices[:0]
+	runs := make([]collapseRun, 0, len(m.filteredIndices))
+	previous, comparable := "", false
+	for _, line := range m.filteredIndices {
+		entries, err := m.indexer.GetLineRange(line, line+1)
+		readable := err == nil && len(entries) > 0
+		if readable && comparable && entries[0].Message == previous {
+			run := &runs[len(runs)-1]
+			run.count++
+			run.last = line
+			if matched[line] {
+				matched[kept[len(kept)-1]] = true
+			}
+			continue
+		}
+
+		kept = append(kept, line)
+		runs = append(runs, collapseRun{count: 1, last: line})
+		previous, comparable = "", readable
+		if readable {
+			previous = entries[0].Message
+		}
+	}
+
+	m.collapsed = len(m.filteredIndices) - len(kept)
+	m.filteredIndices = kept
+	m.runs = runs
+	m.setMatchedLines(matched)
+}
+
+// runAt returns the run shown at position pos, a single entry when lines
+// aren't collapsed
+func (m *UnifiedModel) runAt(pos int) collapseRun {
+	if pos < 0 || pos >= len(m.runs) {
+		return collapseRun{count: 1}
+	}
+	return m.runs[pos]
+}
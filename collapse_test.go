@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCollapse_FoldsRepeatedMessages(t *testing.T) {
+	retry := "2023-12-23 15:30:45 WARN: retrying connection"
+	lines := []string{
+		"2023-12-23 15:30:44 INFO: starting",
+		retry, retry, retry, retry,
+		"2023-12-23 15:30:46 ERROR: gave up",
+		retry, retry,
+	}
+	model := newIndexedTestModel(t, lines, 120, 30)
+	model.scrollToTop()
+	model.scrollDown()
+	model.scrollDown()
+	model.scrollDown() // The third retry
+	model.tailing = false
+
+	model.Update(keyMsg("D"))
+	if got := len(model.filteredIndices); got != 4 {
+		t.Fatalf("Expected 4 rows, got %d", got)
+	}
+	if run := model.runAt(1); run.count != 4 || run.last != 4 {
+		t.Errorf("Expected a run of 4 ending on line 4, got %+v", run)
+	}
+	if line, _ := model.selectedLine(); line != 1 {
+		t.Errorf("Expected the run holding the selection selected, got line %d", line)
+	}
+	if view := model.View(); !strings.Contains(view, "(×4) ") || !strings.Contains(view, "4 repeats collapsed") {
+		t.Error("Expected the row annotated and the repeats counted in the header")
+	}
+
+	model.Update(keyMsg("enter"))
+	if !strings.Contains(model.View(), "×4 in a row, the last on line 5") {
+		t.Error("Expected the detail view to point at the last occurrence")
+	}
+	model.Update(keyMsg("esc"))
+
+	// New lines extend the last run
+	path := model.loadingFile
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(retry + "\n" + retry + "\n")
+	f.Close()
+	model.Update(fileChangedMsg{filename: path})
+	if run := model.runAt(3); run.count != 4 || run.last != 9 {
+		t.Errorf("Expected the last run to grow to 4, got %+v", run)
+	}
+
+	model.Update(keyMsg("D"))
+	if len(model.filteredIndices) != 10 || model.runAt(1).count != 1 {
+		t.Errorf("Expected every line back, got %d", len(model.filteredIndices))
+	}
+}
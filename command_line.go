@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// shellSafeRegex matches words that need no quoting in a shell
+var shellSafeRegex = regexp.MustCompile(`^[A-Za-z0-9_./:,=@%+-]+$`)
+
+// parseLevelList parses a --levels value like "error,warn", or "none"
+func parseLevelList(value string) ([]LogLevel, error) {
+	levels := []LogLevel{}
+	if strings.EqualFold(strings.TrimSpace(value), "none") {
+		return levels, nil
+	}
+	for _, name := range strings.Split(value, ",") {
+		level, ok := levelByName(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("unknown level %q in --levels (error, warn, info, debug or none)", name)
+		}
+		levels = append(levels, level)
+	}
+	return levels, nil
+}
+
+// applyFilterFlags overrides a filter state with the filters given on the
+// command line
+func (c *Config) applyFilterFlags(state FilterState) FilterState {
+	if c.Include != "" {
+		state.Include = c.Include
+	}
+	if c.Exclude != "" {
+		state.Exclude = c.Exclude
+	}
+	if c.SourceFilter != "" {
+		state.Source = c.SourceFilter
+	}
+	// Regex and fuzzy matching exclude each other, the flag wins
+	state.UseRegex = state.UseRegex && !c.Fuzzy || c.UseRegex
+	state.Fuzzy = state.Fuzzy && !c.UseRegex || c.Fuzzy
+	state.CaseSensitive = state.CaseSensitive || c.CaseSensitive
+	state.MatchAll = state.MatchAll || c.MatchAll
+	state.MatchOnly = state.MatchOnly || c.MatchOnly
+	if c.Levels != nil {
+		state.ShowError, state.ShowWarn, state.ShowInfo, state.ShowDebug = false, false, false, false
+		for _, level := range c.Levels {
+			switch level {
+			case ERROR:
+				state.ShowError = true
+			case WARN:
+				state.ShowWarn = true
+			case INFO:
+				state.ShowInfo = true
+			case DEBUG:
+				state.ShowDebug = true
+			}
+		}
+	}
+	return state
+}
+
+// buildCommandLine returns the panam command reproducing the current sources
+// and filters
+func (m *UnifiedModel) buildCommandLine() string {
+	args := []string{"panam"}
+	flag := func(name, value string) {
+		if value != "" {
+			args = append(args, name, shellQuote(value))
+		}
+	}
+
+	flag("-i", m.includeInput.Value())
+	flag("-x", m.excludeInput.Value())
+	flag("--source-filter", m.sourceInput.Value())
+	if m.useRegex {
+		args = append(args, "--regex")
+	}
+	if m.fuzzy {
+		args = append(args, "--fuzzy")
+	}
+	if m.caseSensitive {
+		args = append(args, "--case-sensitive")
+	}
+	if m.matchAll {
+		args = append(args, "--match-all")
+	}
+	if m.matchOnly {
+		args = append(args, "--match-only")
+	}
+
+	var levels []string
+	for _, level := range []LogLevel{ERROR, WARN, INFO, DEBUG} {
+		if m.shouldShowLevel(level) {
+			levels = append(levels, strings.ToLower(level.String()))
+		}
+	}
+	if len(levels) == 0 {
+		levels = []string{"none"}
+	}
+	if len(levels) < 4 {
+		flag("--levels", strings.Join(levels, ","))
+	}
+
+	flag("--since", m.sinceInput.Value())
+	flag("--until", m.untilInput.Value())
+	if m.config.Format != ParserAuto {
+		flag("--format", m.config.Format.String())
+	}
+
+	// Sources last, a followed directory standing for the files in it
+	switch {
+	case m.config.JournalUnit != "":
+		flag("--journal-unit", m.config.JournalUnit)
+	case len(m.config.FollowDirs) > 0:
+		args = append(args, "--follow", shellQuote(m.config.FollowDirs[0]))
+	case len(m.config.Files) == 1:
+		args = append(args, shellQuote(m.config.Files[0]))
+	case len(m.config.Files) > 1:
+		if m.config.Follow {
+			args = append(args, "--follow")
+		} else if m.config.Merge {
+			args = append(args, "--merge")
+		}
+		flag("-e", strings.Join(m.config.Files, ","))
+	}
+	return strings.Join(args, " ")
+}
+
+// copyCommandLine copies the command reproducing the current filters
+func (m *UnifiedModel) copyCommandLine() {
+	command := m.buildCommandLine()
+	writeClipboard(command)
+	m.notice = "Copied " + command
+}
+
+// shellQuote single-quotes a word unless it's safe as it is
+func shellQuote(word string) string {
+	if shellSafeRegex.MatchString(word) {
+		return word
+	}
+	return "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+}
@@ -0,0 +1,57 @@
+package main
+
+import "testing"
+
+func TestBuildCommandLine(t *testing.T) {
+	model := NewUnifiedModel(&Config{
+		Timezone: "UTC",
+		Files:    []string{"/var/log/app.log"},
+		Include:  "timeout",
+		Exclude:  "it's healthy",
+	})
+	model.useRegex = true
+	model.showInfo = false
+	model.showDebug = false
+	model.sinceInput.SetValue("-10m")
+
+	expected := `panam -i timeout -x 'it'\''s healthy' --regex --levels error,warn --since -10m /var/log/app.log`
+	if command := model.buildCommandLine(); command != expected {
+		t.Errorf("Expected %s, got %s", expected, command)
+	}
+
+	var copied string
+	defer func(orig func(string)) { writeClipboard = orig }(writeClipboard)
+	writeClipboard = func(text string) { copied = text }
+	model.Update(keyMsg("Y"))
+	if copied != expected {
+		t.Errorf("Expected Y to copy the command, got %q", copied)
+	}
+}
+
+func TestBuildCommandLine_RoundTrip(t *testing.T) {
+	shown, err := parseLevelList("error, WARN")
+	if err != nil {
+		t.Fatalf("Failed to parse levels: %v", err)
+	}
+	config := &Config{
+		Timezone:      "UTC",
+		Files:         []string{"a.log", "b.log"},
+		Merge:         true,
+		CaseSensitive: true,
+		MatchAll:      true,
+		Levels:        shown,
+	}
+	model := NewUnifiedModel(config)
+	if model.showInfo || model.showDebug || !model.showError || !model.caseSensitive || !model.matchAll {
+		t.Errorf("Expected the command line filters applied to the model")
+	}
+
+	expected := "panam --case-sensitive --match-all --levels error,warn --merge -e a.log,b.log"
+	if command := model.buildCommandLine(); command != expected {
+		t.Errorf("Expected %s, got %s", expected, command)
+	}
+
+	if _, err := parseLevelList("error,fatal"); err == nil {
+		t.Error("Expected an error for an unknown level")
+	}
+}
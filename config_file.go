@@ -0,0 +1,94 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FilterState is the filter setup remembered between sessions
+type FilterState struct {
+	Include       string `yaml:"include"`
+	Exclude       string `yaml:"exclude"`
+	Source        string `yaml:"source,omitempty"`
+	UseRegex      bool   `yaml:"use_regex"`
+	Fuzzy         bool   `yaml:"fuzzy,omitempty"`
+	CaseSensitive bool   `yaml:"case_sensitive"`
+	MatchAll      bool   `yaml:"match_all"`
+	MatchOnly     bool   `yaml:"match_only,omitempty"`
+	ShowDebug     bool   `yaml:"show_debug"`
+	ShowInfo      bool   `yaml:"show_info"`
+	ShowWarn      bool   `yaml:"show_warn"`
+	ShowError     bool   `yaml:"show_error"`
+
+	// JournalCursors is the last journal entry read per --journal-unit
+	JournalCursors map[string]string `yaml:"journal_cursors,omitempty"`
+
+	// SourceLabels are the names given to sources, keyed by their path
+	SourceLabels map[string]string `yaml:"source_labels,omitempty"`
+
+	// IncludeHistory and ExcludeHistory are the patterns applied before,
+	// oldest first, recalled with up and down while editing
+	IncludeHistory []string `yaml:"include_history,omitempty"`
+	ExcludeHistory []string `yaml:"exclude_history,omitempty"`
+
+	// LeftWidth is the filters panel width chosen with < and > (0 = automatic)
+	LeftWidth int `yaml:"left_width,omitempty"`
+}
+
+// DefaultFilterState shows every level with no patterns
+func DefaultFilterState() FilterState {
+	return FilterState{
+		ShowDebug: true,
+		ShowInfo:  true,
+		ShowWarn:  true,
+		ShowError: true,
+	}
+}
+
+// DefaultConfigPath returns ~/.config/panam/config.yaml, honouring XDG_CONFIG_HOME
+func DefaultConfigPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "panam", "config.yaml")
+}
+
+// LoadConfig reads the saved filter state. A missing or corrupt file is not
+// an error, the defaults are used instead
+func LoadConfig(path string) FilterState {
+	state := DefaultFilterState()
+	if path == "" {
+		return state
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return state
+	}
+
+	loaded := DefaultFilterState()
+	if err := yaml.Unmarshal(data, &loaded); err != nil {
+		return state
+	}
+	return loaded
+}
+
+// SaveConfig writes the filter state, creating the config directory if needed
+func SaveConfig(path string, state FilterState) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestConfigFile_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "panam", "config.yaml")
+
+	state := FilterState{
+		Include:       "timeout, refused",
+		Exclude:       "healthcheck",
+		UseRegex:      true,
+		CaseSensitive: true,
+		ShowDebug:     false,
+		ShowInfo:      true,
+		ShowWarn:      true,
+		ShowError:     true,
+
+		JournalCursors: map[string]string{"nginx.service": "s=abc;i=1"},
+		IncludeHistory: []string{"timeout", "timeout, refused"},
+		ExcludeHistory: []string{"healthcheck"},
+	}
+	if err := SaveConfig(path, state); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	if loaded := LoadConfig(path); !reflect.DeepEqual(loaded, state) {
+		t.Errorf("Expected %+v, got %+v", state, loaded)
+	}
+}
+
+func TestConfigFile_MissingOrCorruptUsesDefaults(t *testing.T) {
+	dir := t.TempDir()
+	if loaded := LoadConfig(filepath.Join(dir, "missing.yaml")); !reflect.DeepEqual(loaded, DefaultFilterState()) {
+		t.Errorf("Expected defaults for a missing file, got %+v", loaded)
+	}
+
+	corrupt := filepath.Join(dir, "corrupt.yaml")
+	if err := os.WriteFile(corrupt, []byte("include: [unterminated"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	if loaded := LoadConfig(corrupt); !reflect.DeepEqual(loaded, DefaultFilterState()) {
+		t.Errorf("Expected defaults for a corrupt file, got %+v", loaded)
+	}
+
+	// Keys missing from the file keep their defaults
+	partial := filepath.Join(dir, "partial.yaml")
+	if err := os.WriteFile(partial, []byte("show_debug: false\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	loaded := LoadConfig(partial)
+	if loaded.ShowDebug || !loaded.ShowError {
+		t.Errorf("Expected only DEBUG to be hidden, got %+v", loaded)
+	}
+}
+
+func TestConfigFile_DefaultPathHonoursXDG(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/tmp/xdg")
+	if path := DefaultConfigPath(); path != "/tmp/xdg/panam/config.yaml" {
+		t.Errorf("Unexpected config path %q", path)
+	}
+}
+
+func TestConfigFile_SavedOnQuitAndFlagsOverride(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := SaveConfig(path, FilterState{Include: "saved", Exclude: "noise", UseRegex: true, ShowError: true}); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	app := NewUnifiedApp(&Config{Timezone: "UTC", Include: "from-flag", StatePath: path})
+	model := app.model
+	if model.includeInput.Value() != "from-flag" {
+		t.Errorf("Expected --include to override the saved pattern, got %q", model.includeInput.Value())
+	}
+	if model.excludeInput.Value() != "noise" || !model.useRegex || model.showInfo {
+		t.Errorf("Expected saved state to be restored, got %+v", model.filterState())
+	}
+
+	model.Update(keyMsg("q"))
+	if saved := LoadConfig(path); saved.Include != "from-flag" || saved.ShowInfo {
+		t.Errorf("Expected current filters to be saved on quit, got %+v", saved)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+)
+
+// copyOption is one entry of the yc copy menu
+type copyOption struct {
+	label string
+	value string
+}
+
+// writeClipboard copies text to the system clipboard, falling back to an
+// OSC 52 escape when no clipboard tool is available (e.g. over ssh)
+var writeClipboard = func(text string) {
+	if err := clipboard.WriteAll(text); err != nil {
+		termenv.Copy(text)
+	}
+}
+
+// copyOptionsFor lists the columns and metadata fields of an entry, then the
+// whole entry as key=value pairs. Values are redacted like the display
+func (m *UnifiedModel) copyOptionsFor(entry LogEntry) []copyOption {
+	fields := [][2]string{
+		{"time", m.displayTimestamp(entry)},
+		{"level", entry.Level.String()},
+		{"msg", m.redact(entry.Message)},
+	}
+	if entry.Source != "" {
+		fields = append(fields, [2]string{"source", m.sourceLabel(entry.Source)})
+	}
+	if entry.Component != "" {
+		fields = append(fields, [2]string{"component", entry.Component})
+	}
+
+	keys := make([]string, 0, len(entry.Metadata))
+	for key := range entry.Metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fields = append(fields, [2]string{key, m.redact(fmt.Sprint(entry.Metadata[key]))})
+	}
+
+	options := make([]copyOption, 0, len(fields)+1)
+	pairs := make([]string, 0, len(fields))
+	for _, field := range fields {
+		options = append(options, copyOption{label: field[0], value: field[1]})
+		pairs = append(pairs, field[0]+"="+quoteValue(field[1]))
+	}
+	return append(options, copyOption{label: "all as key=value", value: strings.Join(pairs, " ")})
+}
+
+// quoteValue quotes a key=value value when it wouldn't survive splitting on spaces
+func quoteValue(value string) string {
+	if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+// openCopyMenu shows the copy menu for the selected entry
+func (m *UnifiedModel) openCopyMenu() {
+	if m.selectedIdx < 0 || m.selectedIdx >= len(m.visibleEntries) {
+		return
+	}
+	m.copyOptions = m.copyOptionsFor(m.visibleEntries[m.selectedIdx])
+	m.copyIdx = 0
+	m.viewMode = CopyView
+}
+
+// copySelected copies the highlighted menu option and closes the menu
+func (m *UnifiedModel) copySelected() {
+	option := m.copyOptions[m.copyIdx]
+	writeClipboard(option.value)
+	m.notice = fmt.Sprintf("Copied %s", option.label)
+	m.viewMode = LogStreamView
+}
+
+// renderCopyPanel renders the copy menu in place of the log stream
+func (m *UnifiedModel) renderCopyPanel() string {
+	var content strings.Builder
+
+	content.WriteString("📋 COPY\n")
+	content.WriteString("   (j/k to choose, Enter to copy, ESC to cancel)\n")
+	content.WriteString("───────────────────────────────────────────\n\n")
+
+	valueWidth := max(10, m.rightWidth-24)
+	for i, option := range m.copyOptions {
+		value := ansi.Truncate(strings.ReplaceAll(option.value, "\n", " "), valueWidth, "...")
+		line := fmt.Sprintf("%-16s %s", option.label, value)
+		if i == len(m.copyOptions)-1 {
+			line = option.label
+		}
+		if i == m.copyIdx {
+			content.WriteString("▶ " + m.selectedStyle.Render(line) + "\n")
+		} else {
+			content.WriteString("  " + line + "\n")
+		}
+	}
+
+	return m.focusedStyle.Width(m.rightWidth).Height(m.height - 2).Render(content.String())
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+)
+
+// defaultErrorCodePattern matches codes like ERR_1042 or DB_TIMEOUT_7
+const defaultErrorCodePattern = `\b[A-Z][A-Z0-9_]*_\d+\b`
+
+// ErrorCatalog maps error codes detected in entries to human descriptions
+type ErrorCatalog struct {
+	pattern      *regexp.Regexp
+	descriptions map[string]string
+}
+
+// ErrorCode is a code found in an entry, with its description if the catalog knows it
+type ErrorCode struct {
+	Code        string
+	Description string
+}
+
+// LoadErrorCatalog reads a JSON object of code -> description from path.
+// An empty pattern falls back to defaultErrorCodePattern
+func LoadErrorCatalog(path, pattern string) (*ErrorCatalog, error) {
+	if pattern == "" {
+		pattern = defaultErrorCodePattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid error code pattern: %w", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read error codes: %w", err)
+	}
+
+	descriptions := make(map[string]string)
+	if err := json.Unmarshal(data, &descriptions); err != nil {
+		return nil, fmt.Errorf("failed to parse error codes %s: %w", path, err)
+	}
+
+	return &ErrorCatalog{
+		pattern:      re,
+		descriptions: descriptions,
+	}, nil
+}
+
+// Lookup returns the distinct codes found in the message and metadata values,
+// in order of appearance. Unknown codes have an empty description
+func (c *ErrorCatalog) Lookup(entry LogEntry) []ErrorCode {
+	if c == nil {
+		return nil
+	}
+
+	texts := []string{entry.Message}
+	keys := make([]string, 0, len(entry.Metadata))
+	for k := range entry.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		texts = append(texts, fmt.Sprintf("%v", entry.Metadata[k]))
+	}
+
+	var codes []ErrorCode
+	seen := make(map[string]bool)
+	for _, text := range texts {
+		for _, code := range c.pattern.FindAllString(text, -1) {
+			if seen[code] {
+				continue
+			}
+			seen[code] = true
+			codes = append(codes, ErrorCode{Code: code, Description: c.descriptions[code]})
+		}
+	}
+	return codes
+}
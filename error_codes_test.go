@@ -0,0 +1,69 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeErrorCodes(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "codes.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write error codes: %v", err)
+	}
+	return path
+}
+
+func TestErrorCatalog_Lookup(t *testing.T) {
+	path := writeErrorCodes(t, `{"ERR_1042": "Connection pool exhausted", "ERR_7": "Disk full"}`)
+	catalog, err := LoadErrorCatalog(path, "")
+	if err != nil {
+		t.Fatalf("Failed to load error codes: %v", err)
+	}
+
+	entry := LogEntry{
+		Message:  "request failed with ERR_1042, retry got ERR_9999 and ERR_1042",
+		Metadata: map[string]interface{}{"cause": "ERR_7"},
+	}
+	codes := catalog.Lookup(entry)
+
+	expected := []ErrorCode{
+		{"ERR_1042", "Connection pool exhausted"},
+		{"ERR_9999", ""},
+		{"ERR_7", "Disk full"},
+	}
+	if len(codes) != len(expected) {
+		t.Fatalf("Expected %d codes, got %d: %v", len(expected), len(codes), codes)
+	}
+	for i := range expected {
+		if codes[i] != expected[i] {
+			t.Errorf("Code %d: expected %v, got %v", i, expected[i], codes[i])
+		}
+	}
+}
+
+func TestErrorCatalog_CustomPattern(t *testing.T) {
+	path := writeErrorCodes(t, `{"E42": "Answer not found"}`)
+	catalog, err := LoadErrorCatalog(path, `E\d+`)
+	if err != nil {
+		t.Fatalf("Failed to load error codes: %v", err)
+	}
+
+	codes := catalog.Lookup(LogEntry{Message: "lookup failed: E42"})
+	if len(codes) != 1 || codes[0].Description != "Answer not found" {
+		t.Errorf("Expected E42 to be described, got %v", codes)
+	}
+}
+
+func TestLoadErrorCatalog_Errors(t *testing.T) {
+	if _, err := LoadErrorCatalog(writeErrorCodes(t, `not json`), ""); err == nil {
+		t.Error("Expected error for malformed JSON")
+	}
+	if _, err := LoadErrorCatalog(writeErrorCodes(t, `{}`), "("); err == nil {
+		t.Error("Expected error for invalid pattern")
+	}
+	if _, err := LoadErrorCatalog(filepath.Join(t.TempDir(), "missing.json"), ""); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
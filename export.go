@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// selectedLine returns the file line number of the selected entry
+func (m *UnifiedModel) selectedLine() (int, bool) {
+	pos := m.viewportStart + m.selectedIdx
+	if pos < 0 || pos >= len(m.filteredIndices) {
+		return 0, false
+	}
+	return m.filteredIndices[pos], true
+}
+
+// toggleMark starts or clears a visual selection anchored at the selected entry
+func (m *UnifiedModel) toggleMark() {
+	if m.markLine >= 0 {
+		m.markLine = -1
+		return
+	}
+	if line, ok := m.selectedLine(); ok {
+		m.markLine = line
+	}
+}
+
+// inMarkedRange reports whether a file line lies inside the visual selection
+func (m *UnifiedModel) inMarkedRange(line int) bool {
+	current, ok := m.selectedLine()
+	if m.markLine < 0 || !ok {
+		return false
+	}
+	return line >= min(m.markLine, current) && line <= max(m.markLine, current)
+}
+
+// exportRange copies the original bytes of the visual selection, or of the
+// since/until window when nothing is marked, to a new file next to the
+// working directory
+func (m *UnifiedModel) exportRange() {
+	if m.indexer == nil || m.indexing {
+		return
+	}
+
+	var first, last int
+	if current, ok := m.selectedLine(); ok && m.markLine >= 0 {
+		first, last = min(m.markLine, current), max(m.markLine, current)
+	} else if window := m.timeWindow(); !window.isOpen() {
+		var found bool
+		if first, last, found = m.windowLines(window); !found {
+			m.notice = "Export: no entries in time range"
+			return
+		}
+	} else {
+		m.notice = "Export: mark a range with V or set a time range"
+		return
+	}
+
+	path, size, err := m.exportLines(first, last)
+	if err != nil {
+		m.notice = fmt.Sprintf("Export failed: %v", err)
+		return
+	}
+	m.notice = fmt.Sprintf("Exported %d bytes to %s", size, path)
+	m.markLine = -1
+}
+
+// windowLines finds the first and last lines whose timestamp falls inside the
+// window. Lines without a parseable timestamp can't anchor the range. When
+// the file is in time order, as most are, both ends are found by binary
+// search reading only a few lines; otherwise every line is checked
+func (m *UnifiedModel) windowLines(window timeRange) (first, last int, found bool) {
+	if !m.fileTimesOrdered() {
+		return m.scanWindowLines(window)
+	}
+
+	first = sort.Search(m.totalLines, func(i int) bool {
+		t, ok := m.fileTimeAt(i)
+		return ok && (window.since.IsZero() || !t.Before(window.since))
+	})
+	end := m.totalLines
+	if !window.until.IsZero() {
+		end = sort.Search(m.totalLines, func(i int) bool {
+			t, ok := m.fileTimeAt(i)
+			return ok && t.After(window.until)
+		})
+	}
+	for last = end - 1; last >= first; last-- {
+		if _, ok := m.lineTime(last); ok {
+			return first, last, true
+		}
+	}
+	return 0, 0, false
+}
+
+// scanWindowLines is windowLines for a file out of time order, reading it a
+// chunk at a time
+func (m *UnifiedModel) scanWindowLines(window timeRange) (first, last int, found bool) {
+	const chunk = 4096
+	for start := 0; start < m.totalLines; start += chunk {
+		entries, err := m.indexer.GetLineRange(start, start+chunk)
+		if err != nil {
+			break
+		}
+		for i, entry := range entries {
+			if entry.Time.IsZero() || !window.contains(entry.Time) {
+				continue
+			}
+			if !found {
+				first = start + i
+			}
+			last = start + i
+			found = true
+		}
+	}
+	return first, last, found
+}
+
+// fileTimeAt returns the time of file line i, or of the last line before it
+// with one, as a stack trace belongs to the entry above
+func (m *UnifiedModel) fileTimeAt(i int) (time.Time, bool) {
+	for ; i >= 0; i-- {
+		if t, ok := m.lineTime(i); ok {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// fileTimesOrdered reports whether the file's lines are in time order, like
+// timesOrdered for every line rather than the shown ones
+func (m *UnifiedModel) fileTimesOrdered() bool {
+	var last time.Time
+	for i := 0; i < m.totalLines; i++ {
+		t, known := m.indexer.LineTime(i)
+		if !known {
+			continue
+		}
+		if t.Before(last) {
+			return false
+		}
+		last = t
+	}
+	return true
+}
+
+// exportLines writes lines first through last, byte for byte, to a file named
+// after the source and the byte range, e.g. app.1024-2048.log
+func (m *UnifiedModel) exportLines(first, last int) (string, int64, error) {
+	start, err := m.indexer.LineSpan(first)
+	if err != nil {
+		return "", 0, err
+	}
+	end, err := m.indexer.LineSpan(last)
+	if err != nil {
+		return "", 0, err
+	}
+
+	// Named after the source's label, which may be a pod/container pair
+	ext := filepath.Ext(m.loadingFile)
+	name := strings.ReplaceAll(strings.TrimSuffix(m.sourceLabel(m.loadingFile), ext), "/", "_")
+	path := fmt.Sprintf("%s.%d-%d%s", name, start.Offset, end.Offset+int64(end.Length), ext)
+
+	out, err := os.Create(path)
+	if err != nil {
+		return "", 0, err
+	}
+	size, err := m.indexer.CopyLines(out, first, last)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", 0, err
+	}
+	return path, size, nil
+}
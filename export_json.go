@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// jsonExportEntry is how an entry is written by the JSON export
+type jsonExportEntry struct {
+	Timestamp string                 `json:"timestamp"`
+	Level     string                 `json:"level"`
+	Message   string                 `json:"message"`
+	Source    string                 `json:"source,omitempty"`
+	Component string                 `json:"component,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// exportJSONView writes the filtered entries to a JSON array named after the
+// source, e.g. app.filtered.json
+func (m *UnifiedModel) exportJSONView() {
+	if m.indexing {
+		return
+	}
+
+	path := m.exportName() + ".filtered.json"
+	entries, err := m.ExportJSON(path)
+	if err != nil {
+		m.notice = fmt.Sprintf("Export failed: %v", err)
+		return
+	}
+	m.notice = fmt.Sprintf("Exported %d entries to %s", entries, path)
+}
+
+// ExportJSON writes the entries passing the filters to path as a JSON array
+// and returns how many it wrote
+func (m *UnifiedModel) ExportJSON(path string) (int, error) {
+	entries := m.filteredLogEntries()
+	if err := m.writeJSONFile(path, entries); err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+// writeJSONFile writes the entries to path as a JSON array, or to stdout
+// when path is -
+func (m *UnifiedModel) writeJSONFile(path string, entries []LogEntry) error {
+	if path == "-" {
+		return m.writeJSONEntries(os.Stdout, entries)
+	}
+
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(out)
+	err = m.writeJSONEntries(w, entries)
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+	}
+	return err
+}
+
+// writeJSONEntries writes the entries as an indented JSON array, with their
+// messages and metadata strings redacted like the display. Metadata keeps
+// its parsed shape, so nested objects stay nested
+func (m *UnifiedModel) writeJSONEntries(w io.Writer, entries []LogEntry) error {
+	exported := make([]jsonExportEntry, len(entries))
+	for i, entry := range entries {
+		exported[i] = jsonExportEntry{
+			Timestamp: entry.Timestamp,
+			Level:     entry.Level.String(),
+			Message:   m.redact(entry.Message),
+			Source:    entry.Source,
+			Component: entry.Component,
+			Metadata:  m.redactMetadata(entry.Metadata),
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(exported)
+}
+
+// redactMetadata returns a copy of metadata with every string value redacted,
+// however deeply nested
+func (m *UnifiedModel) redactMetadata(metadata map[string]interface{}) map[string]interface{} {
+	if metadata == nil {
+		return nil
+	}
+	redacted := make(map[string]interface{}, len(metadata))
+	for key, value := range metadata {
+		redacted[key] = m.redactValue(value)
+	}
+	return redacted
+}
+
+// redactValue redacts a string, or the strings in a map or list
+func (m *UnifiedModel) redactValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case string:
+		return m.redact(value)
+	case map[string]interface{}:
+		return m.redactMetadata(value)
+	case []interface{}:
+		redacted := make([]interface{}, len(value))
+		for i, item := range value {
+			redacted[i] = m.redactValue(item)
+		}
+		return redacted
+	}
+	return value
+}
+
+// exportMatchesJSON runs the filters without the UI like printMatches and
+// writes the matching entries to path as a JSON array, returning how many
+func exportMatchesJSON(config *Config, stdin io.Reader, path string) (int, error) {
+	model := NewUnifiedModel(config)
+
+	var entries []LogEntry
+	err := model.eachMatch(stdin, func(_ string, entry LogEntry) {
+		entries = append(entries, entry)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(entries), model.writeJSONFile(path, entries)
+}
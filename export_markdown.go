@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// markdownMessageLimit caps messages in a Markdown export, so one stack
+// trace doesn't swamp the table
+const markdownMessageLimit = 300
+
+// exportMarkdownView writes the filtered entries to a Markdown table named
+// after the source, e.g. app.filtered.md
+func (m *UnifiedModel) exportMarkdownView() {
+	if m.indexing {
+		return
+	}
+
+	path := m.exportName() + ".filtered.md"
+
+	rows, err := m.ExportMarkdown(path)
+	if err != nil {
+		m.notice = fmt.Sprintf("Export failed: %v", err)
+		return
+	}
+	m.notice = fmt.Sprintf("Exported %d entries to %s", rows, path)
+}
+
+// exportName is the name exports of the filtered view are given, without
+// extension: the source's label without its extension, or stdin
+func (m *UnifiedModel) exportName() string {
+	if m.loadingFile == "" {
+		return "stdin"
+	}
+	ext := filepath.Ext(m.loadingFile)
+	return strings.ReplaceAll(strings.TrimSuffix(m.sourceLabel(m.loadingFile), ext), "/", "_")
+}
+
+// ExportMarkdown writes the entries passing the filters as a Markdown table
+// of TIME, LEVEL and MESSAGE, with SOURCE when files are merged, and returns
+// how many rows it wrote. Messages are redacted like the display and capped
+// at markdownMessageLimit, with a note under the table when any were cut
+func (m *UnifiedModel) ExportMarkdown(path string) (int, error) {
+	entries := m.filteredLogEntries()
+	source := m.showSource()
+
+	out, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	w := bufio.NewWriter(out)
+
+	if source {
+		w.WriteString("| TIME | LEVEL | SOURCE | MESSAGE |\n|---|---|---|---|\n")
+	} else {
+		w.WriteString("| TIME | LEVEL | MESSAGE |\n|---|---|---|\n")
+	}
+
+	truncated := 0
+	for _, entry := range entries {
+		message := m.displayMessage(entry)
+		if runes := []rune(message); len(runes) > markdownMessageLimit {
+			message = string(runes[:markdownMessageLimit]) + "…"
+			truncated++
+		}
+
+		cells := []string{m.displayTimestamp(entry), entry.Level.String()}
+		if source {
+			cells = append(cells, m.sourceLabel(entry.Source))
+		}
+		cells = append(cells, message)
+		for i, cell := range cells {
+			cells[i] = markdownCell(cell)
+		}
+		w.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+
+	if truncated > 0 {
+		fmt.Fprintf(w, "\n_%d of %d messages were cut to %d characters._\n", truncated, len(entries), markdownMessageLimit)
+	}
+
+	err = w.Flush()
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+// markdownCell escapes text for a table cell: pipes are escaped and line
+// breaks become <br>
+func markdownCell(text string) string {
+	text = strings.ReplaceAll(text, `\`, `\\`)
+	text = strings.ReplaceAll(text, "|", `\|`)
+	text = strings.ReplaceAll(text, "\r\n", "<br>")
+	return strings.ReplaceAll(text, "\n", "<br>")
+}
@@ -2,17 +2,50 @@ package main
 
 import (
 	"bufio"
-	"bytes"
+	"errors"
+	"fmt"
 	"io"
+	"math"
 	"os"
+	"runtime"
+	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
+	"unsafe"
 )
 
-// FastLineIndex stores just the offset - no parsing at all
+// ErrIndexCancelled is returned when indexing is stopped through Cancel
+var ErrIndexCancelled = errors.New("indexing cancelled")
+
+// scanBufferSize is how much of the file each read while indexing takes
+const scanBufferSize = 256 * 1024
+
+// defaultReadTimeout is how long a single read may block before indexing
+// gives up, so a stalled network mount doesn't freeze the UI
+const defaultReadTimeout = 30 * time.Second
+
+// FastLineIndex stores just the offset - no parsing at all. Length and
+// Level share a word, so an entry takes 24 bytes
 type FastLineIndex struct {
-	Offset int64  // Byte offset in file
-	Length int    // Line length in bytes
+	Offset int64 // Byte offset in file
+	Length int32 // Line length in bytes
+	Level  uint8 // Level found by quickDetectLevel, see indexLevel
+	Time   int64 // Unix nanoseconds found by quickDetectTime, 0 if it needs a parse
+}
+
+// indexEntrySize is the memory cost of one FastLineIndex, which the memory
+// limit counts entries in
+const indexEntrySize = int64(unsafe.Sizeof(FastLineIndex{}))
+
+// indexLevel packs level into a FastLineIndex, levelUnknown included
+func indexLevel(level LogLevel) uint8 {
+	return uint8(level)
+}
+
+// level returns the level packed by indexLevel
+func (idx FastLineIndex) level() LogLevel {
+	return LogLevel(int8(idx.Level))
 }
 
 // FastIndexer does absolutely minimal work during indexing
@@ -24,12 +57,37 @@ type FastIndexer struct {
 	indexed     bool
 	indexMutex  sync.RWMutex
 	
+	// Memory guard: only every stride-th line is indexed once the full
+	// index would need more than maxEntries entries
+	stride      int
+	maxEntries  int
+	
+	// Progress and cancellation for slow filesystems
+	fileSize    int64
+	bytesRead   int64 // Use atomic, read by the UI while indexing
+	tailOffset  int64
+	readTimeout time.Duration
+	
+	// Where the last scan stopped, so appended bytes can be indexed by Extend
+	scanEnd       int64 // Offset just past the last newline
+	completeLines int32 // Lines ending in a newline
+	partialLine   bool  // A trailing line without newline was indexed
+	skipPending   bool  // A tail scan hasn't reached its first newline yet
+	
+	// records is set when the file is a JSON array or a stream of multi-line
+	// JSON objects, and each index entry is one record instead of one line
+	records     bool
+	cancelCh    chan struct{}
+	cancelMutex sync.Mutex
+	
 	// Cache for parsed entries - larger for better performance
 	cache       map[int]LogEntry
 	cacheMutex  sync.RWMutex
 	cacheSize   int
+	lineAt      lineAtOffset // Line of the record last asked for by FileLine
 	
 	parser      *LogParser
+	zone        *time.Location // Zone of timestamps without an offset in this file
 }
 
 func NewFastIndexer(filename string, parser *LogParser) (*FastIndexer, error) {
@@ -43,15 +101,192 @@ func NewFastIndexer(filename string, parser *LogParser) (*FastIndexer, error) {
 	estimatedLines := int(stat.Size() / 100) // Estimate ~100 bytes per line
 	
 	return &FastIndexer{
-		filename:  filename,
-		file:      file,
-		indices:   make([]FastLineIndex, 0, estimatedLines),
-		cache:     make(map[int]LogEntry),
-		cacheSize: 5000, // Larger cache for better performance
-		parser:    parser,
+		filename:    filename,
+		file:        file,
+		indices:     make([]FastLineIndex, 0, estimatedLines),
+		cache:       make(map[int]LogEntry),
+		cacheSize:   5000, // Larger cache for better performance
+		parser:      parser,
+		zone:        parser.zoneFor(filename),
+		stride:      1,
+		fileSize:    stat.Size(),
+		readTimeout: defaultReadTimeout,
+		cancelCh:    make(chan struct{}),
 	}, nil
 }
 
+// SetReadTimeout bounds how long a single read may block while indexing
+func (fi *FastIndexer) SetReadTimeout(timeout time.Duration) {
+	fi.readTimeout = timeout
+}
+
+// Cancel stops an in-progress IndexFileUltraFast or IndexTail, which then
+// return ErrIndexCancelled. Safe to call from another goroutine
+func (fi *FastIndexer) Cancel() {
+	fi.cancelMutex.Lock()
+	defer fi.cancelMutex.Unlock()
+	
+	select {
+	case <-fi.cancelCh:
+	default:
+		close(fi.cancelCh)
+	}
+}
+
+// cancelled returns the channel closed by the next Cancel
+func (fi *FastIndexer) cancelled() <-chan struct{} {
+	fi.cancelMutex.Lock()
+	defer fi.cancelMutex.Unlock()
+	return fi.cancelCh
+}
+
+// Progress returns the bytes scanned so far and the file size
+func (fi *FastIndexer) Progress() (read, total int64) {
+	return atomic.LoadInt64(&fi.bytesRead), fi.fileSize
+}
+
+// TailOffset returns where a tail-only index starts (0 for a full index)
+func (fi *FastIndexer) TailOffset() int64 {
+	fi.indexMutex.RLock()
+	defer fi.indexMutex.RUnlock()
+	return fi.tailOffset
+}
+
+// SetMemoryLimit bounds the memory used by the line index. When the estimated
+// index for this file would exceed it, only every Kth line is indexed and the
+// lines in between are found by scanning forward. Zero disables the limit
+func (fi *FastIndexer) SetMemoryLimit(limit int64) {
+	fi.indexMutex.Lock()
+	defer fi.indexMutex.Unlock()
+	
+	if limit <= 0 {
+		fi.maxEntries = 0
+		return
+	}
+	fi.maxEntries = max(1, int(limit/indexEntrySize))
+	
+	// Start with a stride that fits the estimate so a huge file never
+	// allocates the full index first
+	estimatedLines := cap(fi.indices)
+	for estimatedLines/fi.stride > fi.maxEntries {
+		fi.stride *= 2
+	}
+	fi.indices = make([]FastLineIndex, 0, min(estimatedLines/fi.stride, fi.maxEntries)+1)
+}
+
+// IndexStride returns how many lines each index entry covers (1 for a full index)
+func (fi *FastIndexer) IndexStride() int {
+	fi.indexMutex.RLock()
+	defer fi.indexMutex.RUnlock()
+	return fi.stride
+}
+
+// addLine records a line in the index, coarsening the index whenever it
+// grows past the memory limit
+func (fi *FastIndexer) addLine(offset int64, length int, level LogLevel, t int64, lineNum int32) {
+	if int(lineNum)%fi.stride != 0 {
+		return
+	}
+	fi.indices = append(fi.indices, FastLineIndex{
+		Offset: offset,
+		Length: int32(length),
+		Level:  indexLevel(level),
+		Time:   t,
+	})
+	if fi.maxEntries > 0 && len(fi.indices) > fi.maxEntries {
+		fi.coarsen()
+	}
+}
+
+// coarsen halves the index by dropping every other entry and doubling the stride
+func (fi *FastIndexer) coarsen() {
+	kept := fi.indices[:0]
+	for i := 0; i < len(fi.indices); i += 2 {
+		kept = append(kept, fi.indices[i])
+	}
+	fi.indices = kept
+	fi.stride *= 2
+}
+
+// readLine returns a single line without its trailing newline, and where it
+// lies in the file. With a coarse index it scans forward from the closest
+// indexed line
+func (fi *FastIndexer) readLine(idx int) (string, FastLineIndex, error) {
+	block := idx / fi.stride
+	if block >= len(fi.indices) {
+		return "", FastLineIndex{}, io.EOF
+	}
+	index := fi.indices[block]
+	
+	if fi.stride == 1 {
+		buffer := make([]byte, index.Length)
+		if _, err := fi.file.ReadAt(buffer, index.Offset); err != nil && err != io.EOF {
+			return "", index, err
+		}
+		return strings.TrimSuffix(string(buffer), "\n"), index, nil
+	}
+	
+	reader := bufio.NewReader(io.NewSectionReader(fi.file, index.Offset, math.MaxInt64-index.Offset))
+	offset := index.Offset
+	var line string
+	for i := 0; i <= idx%fi.stride; i++ {
+		if i > 0 {
+			offset += int64(len(line))
+		}
+		var err error
+		line, err = reader.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return "", FastLineIndex{}, err
+		}
+	}
+	return strings.TrimSuffix(line, "\n"), FastLineIndex{Offset: offset, Length: int32(len(line))}, nil
+}
+
+// LineSpan returns where line idx lies in the file
+func (fi *FastIndexer) LineSpan(idx int) (FastLineIndex, error) {
+	fi.indexMutex.RLock()
+	defer fi.indexMutex.RUnlock()
+	
+	if fi.stride == 1 {
+		if idx < 0 || idx >= len(fi.indices) {
+			return FastLineIndex{}, io.EOF
+		}
+		return fi.indices[idx], nil
+	}
+	_, span, err := fi.readLine(idx)
+	return span, err
+}
+
+// RawLine reads line idx from the file, whatever its length. Entries keep
+// only the start of very long messages, see --max-line-bytes
+func (fi *FastIndexer) RawLine(idx int) (string, error) {
+	fi.indexMutex.RLock()
+	defer fi.indexMutex.RUnlock()
+	
+	line, _, err := fi.readLine(idx)
+	return strings.TrimSuffix(line, "\r"), err
+}
+
+// CopyLines writes the original bytes of lines first through last to w,
+// straight from the file using the index offsets. Nothing is parsed or
+// re-encoded, so ANSI codes and line endings are kept as they are
+func (fi *FastIndexer) CopyLines(w io.Writer, first, last int) (int64, error) {
+	if first > last {
+		first, last = last, first
+	}
+	start, err := fi.LineSpan(first)
+	if err != nil {
+		return 0, fmt.Errorf("line %d: %w", first, err)
+	}
+	end, err := fi.LineSpan(last)
+	if err != nil {
+		return 0, fmt.Errorf("line %d: %w", last, err)
+	}
+	
+	size := end.Offset + int64(end.Length) - start.Offset
+	return io.Copy(w, io.NewSectionReader(fi.file, start.Offset, size))
+}
+
 // IndexFileUltraFast scans the file with minimal overhead
 func (fi *FastIndexer) IndexFileUltraFast() error {
 	fi.indexMutex.Lock()
@@ -61,39 +296,139 @@ func (fi *FastIndexer) IndexFileUltraFast() error {
 		return nil
 	}
 	
+	if array, ok := fi.detectRecords(); ok {
+		return fi.scanRecords(array)
+	}
+	if workers := runtime.GOMAXPROCS(0); workers > 1 && fi.fileSize >= parallelIndexMinSize && fi.stride == 1 {
+		return fi.scanParallel(workers)
+	}
+	return fi.scan(io.NewSectionReader(fi.file, 0, math.MaxInt64), 0, false, 0)
+}
+
+// IndexTail indexes only the last maxBytes of the file, starting at the first
+// complete line. Used as a fallback when a full scan is too slow
+func (fi *FastIndexer) IndexTail(maxBytes int64) error {
+	fi.indexMutex.Lock()
+	defer fi.indexMutex.Unlock()
+	
+	if fi.records {
+		return fmt.Errorf("%w: a JSON record file can't be shown from the tail", ErrIndexCancelled)
+	}
+	
+	// Start over, a cancelled full scan leaves a partial index behind
+	fi.indices = fi.indices[:0]
+	fi.stride = 1
+	fi.indexed = false
+	atomic.StoreInt32(&fi.totalLines, 0)
+	fi.cacheMutex.Lock()
+	fi.cache = make(map[int]LogEntry)
+	fi.cacheMutex.Unlock()
+	fi.cancelMutex.Lock()
+	fi.cancelCh = make(chan struct{})
+	fi.cancelMutex.Unlock()
+	
+	start := fi.fileSize - maxBytes
+	if start < 0 {
+		start = 0
+	}
+	fi.tailOffset = start
+	if start == 0 {
+		return fi.scan(io.NewSectionReader(fi.file, 0, math.MaxInt64), 0, false, 0)
+	}
+	
+	// Read from the byte before start so a line beginning exactly at start is kept
+	return fi.scan(io.NewSectionReader(fi.file, start-1, math.MaxInt64), start-1, true, 0)
+}
+
+// Extend indexes lines appended since the last scan. It reports false when
+// the file shrank, in which case it has to be indexed again from scratch.
+// JSON record files always need a full reindex
+func (fi *FastIndexer) Extend() (bool, error) {
+	fi.indexMutex.Lock()
+	defer fi.indexMutex.Unlock()
+	
+	if fi.records {
+		return false, nil
+	}
+	
+	stat, err := fi.file.Stat()
+	if err != nil {
+		return false, err
+	}
+	scanned := atomic.LoadInt64(&fi.bytesRead)
+	if stat.Size() < scanned {
+		return false, nil
+	}
+	if stat.Size() == scanned {
+		return true, nil
+	}
+	fi.fileSize = stat.Size()
+	
+	// The trailing partial line is scanned again together with its new bytes
+	if fi.partialLine {
+		if int(fi.completeLines)%fi.stride == 0 {
+			fi.indices = fi.indices[:len(fi.indices)-1]
+		}
+		fi.cacheMutex.Lock()
+		delete(fi.cache, int(fi.completeLines))
+		fi.cacheMutex.Unlock()
+	}
+	
+	return true, fi.scan(io.NewSectionReader(fi.file, fi.scanEnd, math.MaxInt64), fi.scanEnd, fi.skipPending, fi.completeLines)
+}
+
+// scan adds the lines read from r to the index. r begins at byte offset start
+// of the file and its first line is number lineCount. With skipPartial,
+// everything up to the first newline is skipped
+func (fi *FastIndexer) scan(r io.Reader, start int64, skipPartial bool, lineCount int32) error {
+	return fi.scanFrom(r, start, start, skipPartial, lineCount)
+}
+
+// scanFrom is scan for a reader beginning partway through line lineCount,
+// which starts at lineStart
+func (fi *FastIndexer) scanFrom(r io.Reader, start, lineStart int64, skipPartial bool, lineCount int32) error {
 	// Use larger buffer for better I/O performance
-	const bufferSize = 256 * 1024 // 256KB buffer
-	buffer := make([]byte, bufferSize)
+	buffer := make([]byte, scanBufferSize)
 	
-	var offset int64 = 0
-	var lineStart int64 = 0
-	var lineCount int32 = 0
+	offset := start
+	chunkStart := start // Where the bytes in buffer begin
+	skipping := skipPartial
+	atomic.StoreInt64(&fi.bytesRead, start)
+	cancel := fi.cancelled()
 	
 	for {
-		n, err := fi.file.Read(buffer)
+		n, err := fi.readChunk(r, buffer, cancel)
 		if n > 0 {
+			chunkStart = offset
+			
 			// Find all newlines in the buffer
 			for i := 0; i < n; i++ {
 				if buffer[i] == '\n' {
-					lineLen := int(offset + int64(i) - lineStart + 1)
-					fi.indices = append(fi.indices, FastLineIndex{
-						Offset: lineStart,
-						Length: lineLen,
-					})
+					if skipping {
+						skipping = false
+					} else {
+						lineLen := int(offset + int64(i) - lineStart + 1)
+						level := fi.parser.chunkLevel(buffer, chunkStart, lineStart, offset+int64(i))
+						fi.addLine(lineStart, lineLen, level, fi.chunkTime(buffer, chunkStart, lineStart, offset+int64(i), level), lineCount)
+						lineCount++
+					}
 					lineStart = offset + int64(i) + 1
-					lineCount++
 				}
 			}
 			offset += int64(n)
+			atomic.StoreInt64(&fi.bytesRead, offset)
 		}
 		
 		if err == io.EOF {
+			fi.scanEnd = lineStart
+			fi.completeLines = lineCount
+			fi.skipPending = skipping
+			
 			// Handle last line if no trailing newline
-			if lineStart < offset {
-				fi.indices = append(fi.indices, FastLineIndex{
-					Offset: lineStart,
-					Length: int(offset - lineStart),
-				})
+			fi.partialLine = lineStart < offset && !skipping
+			if fi.partialLine {
+				level := fi.parser.chunkLevel(buffer, chunkStart, lineStart, offset)
+				fi.addLine(lineStart, int(offset-lineStart), level, fi.chunkTime(buffer, chunkStart, lineStart, offset, level), lineCount)
 				lineCount++
 			}
 			break
@@ -105,12 +440,59 @@ func (fi *FastIndexer) IndexFileUltraFast() error {
 	atomic.StoreInt32(&fi.totalLines, lineCount)
 	fi.indexed = true
 	
-	// Reset file position for reading
-	fi.file.Seek(0, 0)
-	
 	return nil
 }
 
+// readBuffers are the buffers readChunk reads into before copying to the
+// caller's, so an abandoned read never writes into a buffer in use
+var readBuffers sync.Pool
+
+// readChunk reads into buffer, giving up when cancel is closed or when the
+// read blocks for longer than the read timeout. A read given up on can't be
+// interrupted on a regular file; it finishes into its own buffer, which is
+// then dropped, and the scan that started it stops
+func (fi *FastIndexer) readChunk(r io.Reader, buffer []byte, cancel <-chan struct{}) (int, error) {
+	select {
+	case <-cancel:
+		return 0, ErrIndexCancelled
+	default:
+	}
+	
+	private, _ := readBuffers.Get().([]byte)
+	if cap(private) < len(buffer) {
+		private = make([]byte, len(buffer))
+	}
+	private = private[:len(buffer)]
+	
+	type result struct {
+		n   int
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		n, err := r.Read(private)
+		done <- result{n, err}
+	}()
+	
+	var timeout <-chan time.Time
+	if fi.readTimeout > 0 {
+		timer := time.NewTimer(fi.readTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	
+	select {
+	case res := <-done:
+		copy(buffer, private[:res.n])
+		readBuffers.Put(private)
+		return res.n, res.err
+	case <-cancel:
+		return 0, ErrIndexCancelled
+	case <-timeout:
+		return 0, fmt.Errorf("reading %s: no data for %v", fi.filename, fi.readTimeout)
+	}
+}
+
 // GetLineRange retrieves multiple lines efficiently in a single read
 func (fi *FastIndexer) GetLineRange(start, end int) ([]LogEntry, error) {
 	if start < 0 {
@@ -149,12 +531,27 @@ func (fi *FastIndexer) GetLineRange(start, end int) ([]LogEntry, error) {
 	fi.indexMutex.RLock()
 	defer fi.indexMutex.RUnlock()
 	
+	// A coarse index has no per-line offsets, so scan each line individually
+	if fi.stride > 1 {
+		for _, idx := range uncachedRanges {
+			line, span, err := fi.readLine(idx)
+			if err != nil {
+				continue
+			}
+			entry := fi.parser.ParseLogLine(line, fi.filename)
+			entry.Offset, entry.Length = span.Offset, int(span.Length)
+			entries = append(entries, entry)
+			fi.cacheEntry(idx, entry)
+		}
+		return entries, nil
+	}
+	
 	if len(uncachedRanges) > 0 && len(fi.indices) > 0 {
 		// Calculate total buffer size needed
 		totalSize := 0
 		for _, idx := range uncachedRanges {
 			if idx < len(fi.indices) {
-				totalSize += fi.indices[idx].Length
+				totalSize += int(fi.indices[idx].Length)
 			}
 		}
 		
@@ -175,23 +572,21 @@ func (fi *FastIndexer) GetLineRange(start, end int) ([]LogEntry, error) {
 					return entries, err
 				}
 				
-				// Parse each line from the buffer
+				// Parse each line from the buffer, cut at the index offsets so
+				// multi-line JSON records stay whole
 				newEntries := make([]LogEntry, 0, len(uncachedRanges))
-				reader := bytes.NewReader(buffer)
-				scanner := bufio.NewScanner(reader)
-				
-				for i, lineIdx := range uncachedRanges {
-					if scanner.Scan() {
-						line := scanner.Text()
-						entry := fi.parser.ParseLogLine(line, fi.filename)
-						newEntries = append(newEntries, entry)
-						
-						// Update cache
-						fi.cacheMutex.Lock()
-						fi.cache[lineIdx] = entry
-						fi.cacheMutex.Unlock()
-					}
-					_ = i // Use i to avoid warning
+				for _, lineIdx := range uncachedRanges {
+					index := fi.indices[lineIdx]
+					from := index.Offset - startOffset
+					line := strings.TrimSuffix(string(buffer[from:from+int64(index.Length)]), "\n")
+					entry := fi.parser.ParseLogLine(strings.TrimSuffix(line, "\r"), fi.filename)
+					entry.Offset, entry.Length = index.Offset, int(index.Length)
+					newEntries = append(newEntries, entry)
+					
+					// Update cache
+					fi.cacheMutex.Lock()
+					fi.cache[lineIdx] = entry
+					fi.cacheMutex.Unlock()
 				}
 				
 				entries = append(entries, newEntries...)
@@ -213,27 +608,9 @@ func (fi *FastIndexer) GetLineRange(start, end int) ([]LogEntry, error) {
 					}
 					
 					entry := fi.parser.ParseLogLine(line, fi.filename)
+					entry.Offset, entry.Length = index.Offset, int(index.Length)
 					entries = append(entries, entry)
-					
-					// Update cache
-					fi.cacheMutex.Lock()
-					fi.cache[idx] = entry
-					
-					// Simple cache eviction if too large
-					if len(fi.cache) > fi.cacheSize {
-						// Remove some old entries
-						removed := 0
-						for k := range fi.cache {
-							if k < idx-fi.cacheSize/2 || k > idx+fi.cacheSize/2 {
-								delete(fi.cache, k)
-								removed++
-								if removed > fi.cacheSize/4 {
-									break
-								}
-							}
-						}
-					}
-					fi.cacheMutex.Unlock()
+					fi.cacheEntry(idx, entry)
 				}
 			}
 		}
@@ -242,6 +619,29 @@ func (fi *FastIndexer) GetLineRange(start, end int) ([]LogEntry, error) {
 	return entries, nil
 }
 
+// cacheEntry stores a parsed entry, evicting entries far from idx when the cache is full
+func (fi *FastIndexer) cacheEntry(idx int, entry LogEntry) {
+	fi.cacheMutex.Lock()
+	defer fi.cacheMutex.Unlock()
+	
+	fi.cache[idx] = entry
+	
+	// Simple cache eviction if too large
+	if len(fi.cache) > fi.cacheSize {
+		// Remove some old entries
+		removed := 0
+		for k := range fi.cache {
+			if k < idx-fi.cacheSize/2 || k > idx+fi.cacheSize/2 {
+				delete(fi.cache, k)
+				removed++
+				if removed > fi.cacheSize/4 {
+					break
+				}
+			}
+		}
+	}
+}
+
 // GetLineCount returns total indexed lines
 func (fi *FastIndexer) GetLineCount() int {
 	return int(atomic.LoadInt32(&fi.totalLines))
@@ -259,6 +659,17 @@ func (fi *FastIndexer) GetLines(start, count int) []string {
 	
 	lines := make([]string, 0, count)
 	end := start + count
+	
+	if fi.stride > 1 {
+		end = min(end, int(atomic.LoadInt32(&fi.totalLines)))
+		for i := start; i < end; i++ {
+			if line, _, err := fi.readLine(i); err == nil {
+				lines = append(lines, line)
+			}
+		}
+		return lines
+	}
+	
 	if end > len(fi.indices) {
 		end = len(fi.indices)
 	}
@@ -277,7 +688,35 @@ func (fi *FastIndexer) GetLines(start, count int) []string {
 	return lines
 }
 
+// LineLevel returns the level recorded for a line while indexing. It reports
+// false when the line has to be parsed to know, e.g. with a sparse index
+func (fi *FastIndexer) LineLevel(idx int) (LogLevel, bool) {
+	fi.indexMutex.RLock()
+	defer fi.indexMutex.RUnlock()
+	
+	if fi.stride != 1 || idx < 0 || idx >= len(fi.indices) || fi.indices[idx].level() == levelUnknown {
+		return levelUnknown, false
+	}
+	return fi.indices[idx].level(), true
+}
+
+// LineTime returns the timestamp recorded for a line while indexing. It
+// reports false when the line has to be parsed to know
+func (fi *FastIndexer) LineTime(idx int) (time.Time, bool) {
+	fi.indexMutex.RLock()
+	defer fi.indexMutex.RUnlock()
+	
+	if fi.stride != 1 || idx < 0 || idx >= len(fi.indices) || fi.indices[idx].Time == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(0, fi.indices[idx].Time).UTC(), true
+}
+
 // Close releases resources
 func (fi *FastIndexer) Close() error {
+	// Nothing cached may be served once the file is gone
+	fi.cacheMutex.Lock()
+	fi.cache = make(map[int]LogEntry)
+	fi.cacheMutex.Unlock()
 	return fi.file.Close()
 }
\ No newline at end of file
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"io"
+	"math"
+	"sync"
+	"sync/atomic"
+)
+
+// parallelIndexMinSize is the file size from which IndexFileUltraFast splits
+// the scan over several goroutines. Smaller files are scanned faster than
+// the goroutines start
+const parallelIndexMinSize = 64 << 20
+
+// chunkLines are the newlines found in one chunk of the file, with the level
+// of the line each ends. The first level assumes the line starts at the
+// chunk, which the merge corrects when it doesn't
+type chunkLines struct {
+	start  int64
+	end    int64
+	ends   []int64
+	levels []LogLevel
+	times  []int64
+	err    error
+}
+
+// scanParallel indexes the file like scan, with the part before the last
+// full read split into chunks scanned by workers goroutines at once. Chunks
+// are whole reads, so lines crossing a read boundary are left for a parse
+// exactly like in a serial scan and both give the same index. The last full
+// read is left to scanFrom, which then holds it like scan does when it
+// reaches a trailing line without newline
+func (fi *FastIndexer) scanParallel(workers int) error {
+	reads := fi.fileSize/scanBufferSize - 1
+	if reads < 2 {
+		return fi.scan(io.NewSectionReader(fi.file, 0, math.MaxInt64), 0, false, 0)
+	}
+	workers = min(workers, int(reads))
+	chunkSize := (reads + int64(workers) - 1) / int64(workers) * scanBufferSize
+	end := reads * scanBufferSize
+	atomic.StoreInt64(&fi.bytesRead, 0)
+
+	chunks := make([]chunkLines, 0, workers)
+	for start := int64(0); start < end; start += chunkSize {
+		chunks = append(chunks, chunkLines{start: start, end: start + chunkSize})
+	}
+	chunks[len(chunks)-1].end = end
+	cancel := fi.cancelled()
+	var wg sync.WaitGroup
+	for i := range chunks {
+		wg.Add(1)
+		go func(chunk *chunkLines) {
+			defer wg.Done()
+			fi.scanChunk(chunk, cancel)
+		}(&chunks[i])
+	}
+	wg.Wait()
+
+	// Lines are numbered in file order, so they're added chunk by chunk
+	lineStart := int64(0)
+	lineCount := int32(0)
+	for _, chunk := range chunks {
+		if chunk.err != nil {
+			return chunk.err
+		}
+		for i, lineEnd := range chunk.ends {
+			level, t := chunk.levels[i], chunk.times[i]
+			if i == 0 && lineStart != chunk.start {
+				level, t = levelUnknown, 0 // Began in an earlier chunk
+			}
+			fi.addLine(lineStart, int(lineEnd-lineStart+1), level, t, lineCount)
+			lineCount++
+			lineStart = lineEnd + 1
+		}
+	}
+
+	// The rest, and anything appended meanwhile, is read like scan would
+	return fi.scanFrom(io.NewSectionReader(fi.file, end, math.MaxInt64), end, lineStart, false, lineCount)
+}
+
+// scanChunk finds the newlines between chunk.start and chunk.end
+func (fi *FastIndexer) scanChunk(chunk *chunkLines, cancel <-chan struct{}) {
+	r := io.NewSectionReader(fi.file, chunk.start, chunk.end-chunk.start)
+	buffer := make([]byte, scanBufferSize)
+	offset := chunk.start
+	lineStart := chunk.start
+
+	for {
+		n, err := fi.readChunk(r, buffer, cancel)
+		for i := 0; i < n; i++ {
+			if buffer[i] == '\n' {
+				lineEnd := offset + int64(i)
+				chunk.ends = append(chunk.ends, lineEnd)
+				level := fi.parser.chunkLevel(buffer, offset, lineStart, lineEnd)
+				chunk.levels = append(chunk.levels, level)
+				chunk.times = append(chunk.times, fi.chunkTime(buffer, offset, lineStart, lineEnd, level))
+				lineStart = lineEnd + 1
+			}
+		}
+		offset += int64(n)
+		atomic.AddInt64(&fi.bytesRead, int64(n))
+
+		if err == io.EOF {
+			return
+		} else if err != nil {
+			chunk.err = err
+			return
+		}
+	}
+}
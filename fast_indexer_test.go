@@ -0,0 +1,552 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeTestLog writes the lines to a temporary file and returns its path
+func writeTestLog(t *testing.T, lines []string) string {
+	t.Helper()
+
+	testFile := filepath.Join(t.TempDir(), "test.log")
+	if err := os.WriteFile(testFile, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	return testFile
+}
+
+func TestFastIndexer_MemoryLimitUsesSparseIndex(t *testing.T) {
+	lines := make([]string, 1000)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("2023-12-23 15:30:45 INFO: line %d", i)
+	}
+	testFile := writeTestLog(t, lines)
+
+	indexer, err := NewFastIndexer(testFile, NewLogParser("UTC"))
+	if err != nil {
+		t.Fatalf("Failed to create indexer: %v", err)
+	}
+	defer indexer.Close()
+
+	if indexEntrySize != 24 {
+		t.Errorf("Expected 24 byte index entries, got %d", indexEntrySize)
+	}
+
+	// Room for 100 entries forces the index to coarsen while scanning
+	indexer.SetMemoryLimit(100 * indexEntrySize)
+	if err := indexer.IndexFileUltraFast(); err != nil {
+		t.Fatalf("Indexing failed: %v", err)
+	}
+
+	if indexer.IndexStride() <= 1 {
+		t.Fatalf("Expected a sparse index, got stride %d", indexer.IndexStride())
+	}
+	if len(indexer.indices) > 100 {
+		t.Errorf("Expected at most 100 index entries, got %d", len(indexer.indices))
+	}
+	if indexer.GetLineCount() != len(lines) {
+		t.Errorf("Expected %d lines, got %d", len(lines), indexer.GetLineCount())
+	}
+
+	for _, i := range []int{0, 1, 7, 8, 499, 998, 999} {
+		entries, err := indexer.GetLineRange(i, i+1)
+		if err != nil || len(entries) != 1 {
+			t.Fatalf("Failed to read line %d: %v", i, err)
+		}
+		if entries[0].Message != lines[i] {
+			t.Errorf("Line %d: expected %q, got %q", i, lines[i], entries[0].Message)
+		}
+	}
+
+	raw := indexer.GetLines(995, 10)
+	if len(raw) != 5 || raw[4] != lines[999] {
+		t.Errorf("Expected last 5 raw lines, got %v", raw)
+	}
+}
+
+// blockingReader never returns, like a read from a stalled network mount
+type blockingReader struct{}
+
+func (blockingReader) Read(p []byte) (int, error) {
+	select {}
+}
+
+func TestFastIndexer_CancelStopsBlockedRead(t *testing.T) {
+	indexer, err := NewFastIndexer(writeTestLog(t, []string{"line"}), NewLogParser("UTC"))
+	if err != nil {
+		t.Fatalf("Failed to create indexer: %v", err)
+	}
+	defer indexer.Close()
+
+	done := make(chan error, 1)
+	go func() { done <- indexer.scan(blockingReader{}, 0, false, 0) }()
+	indexer.Cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrIndexCancelled) {
+			t.Errorf("Expected ErrIndexCancelled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Cancel did not stop the blocked read")
+	}
+}
+
+func TestFastIndexer_ReadTimeout(t *testing.T) {
+	indexer, err := NewFastIndexer(writeTestLog(t, []string{"line"}), NewLogParser("UTC"))
+	if err != nil {
+		t.Fatalf("Failed to create indexer: %v", err)
+	}
+	defer indexer.Close()
+
+	indexer.SetReadTimeout(20 * time.Millisecond)
+	err = indexer.scan(blockingReader{}, 0, false, 0)
+	if err == nil || !strings.Contains(err.Error(), "no data for") {
+		t.Errorf("Expected a read timeout error, got %v", err)
+	}
+}
+
+// lateReader fills p only after release is closed, like a stalled read that
+// finally completes
+type lateReader struct{ release chan struct{} }
+
+func (r lateReader) Read(p []byte) (int, error) {
+	<-r.release
+	for i := range p {
+		p[i] = 'x'
+	}
+	return len(p), nil
+}
+
+func TestFastIndexer_AbandonedReadLeavesBufferAlone(t *testing.T) {
+	indexer, err := NewFastIndexer(writeTestLog(t, []string{"line"}), NewLogParser("UTC"))
+	if err != nil {
+		t.Fatalf("Failed to create indexer: %v", err)
+	}
+	defer indexer.Close()
+
+	indexer.SetReadTimeout(10 * time.Millisecond)
+	reader := lateReader{release: make(chan struct{})}
+	buffer := make([]byte, 16)
+	if _, err := indexer.readChunk(reader, buffer, nil); err == nil {
+		t.Fatal("Expected a read timeout error")
+	}
+
+	// The read completing later writes into its own buffer, not the caller's
+	close(reader.release)
+	time.Sleep(20 * time.Millisecond)
+	for i, b := range buffer {
+		if b != 0 {
+			t.Fatalf("Expected the caller's buffer untouched, byte %d is %q", i, b)
+		}
+	}
+}
+
+func TestFastIndexer_IndexTail(t *testing.T) {
+	lines := make([]string, 100)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("2023-12-23 15:30:45 INFO: line %02d", i)
+	}
+	testFile := writeTestLog(t, lines)
+	lineSize := int64(len(lines[0]) + 1)
+
+	indexer, err := NewFastIndexer(testFile, NewLogParser("UTC"))
+	if err != nil {
+		t.Fatalf("Failed to create indexer: %v", err)
+	}
+	defer indexer.Close()
+
+	// Cancelled before starting, then fall back to the last 10.5 lines
+	indexer.Cancel()
+	if err := indexer.IndexFileUltraFast(); !errors.Is(err, ErrIndexCancelled) {
+		t.Fatalf("Expected ErrIndexCancelled, got %v", err)
+	}
+	if err := indexer.IndexTail(10*lineSize + lineSize/2); err != nil {
+		t.Fatalf("Tail indexing failed: %v", err)
+	}
+
+	if indexer.GetLineCount() != 10 {
+		t.Fatalf("Expected the 10 complete trailing lines, got %d", indexer.GetLineCount())
+	}
+	if indexer.TailOffset() == 0 {
+		t.Error("Expected a non-zero tail offset")
+	}
+	raw := indexer.GetLines(0, 10)
+	if strings.TrimSuffix(raw[0], "\n") != lines[90] || strings.TrimSuffix(raw[9], "\n") != lines[99] {
+		t.Errorf("Unexpected tail lines: %q ... %q", raw[0], raw[9])
+	}
+
+	// A tail starting exactly on a line boundary keeps that line
+	if err := indexer.IndexTail(5 * lineSize); err != nil {
+		t.Fatalf("Tail indexing failed: %v", err)
+	}
+	if indexer.GetLineCount() != 5 {
+		t.Errorf("Expected 5 lines, got %d", indexer.GetLineCount())
+	}
+	read, total := indexer.Progress()
+	if read != total {
+		t.Errorf("Expected progress to reach the file size, got %d/%d", read, total)
+	}
+}
+
+func TestFastIndexer_ExtendIndexesAppendedLines(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "test.log")
+	if err := os.WriteFile(testFile, []byte("line 0\nline 1\npart"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	indexer, err := NewFastIndexer(testFile, NewLogParser("UTC"))
+	if err != nil {
+		t.Fatalf("Failed to create indexer: %v", err)
+	}
+	defer indexer.Close()
+	if err := indexer.IndexFileUltraFast(); err != nil {
+		t.Fatalf("Indexing failed: %v", err)
+	}
+	if indexer.GetLineCount() != 3 {
+		t.Fatalf("Expected 3 lines, got %d", indexer.GetLineCount())
+	}
+	indexer.GetLineRange(0, 3) // Cache the partial line
+
+	// Finish the partial line and add another
+	f, err := os.OpenFile(testFile, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open test file: %v", err)
+	}
+	f.WriteString("ial 2\nline 3\n")
+	f.Close()
+
+	grown, err := indexer.Extend()
+	if err != nil || !grown {
+		t.Fatalf("Expected Extend to succeed, got %v, %v", grown, err)
+	}
+	raw := indexer.GetLines(0, 10)
+	expected := []string{"line 0\n", "line 1\n", "partial 2\n", "line 3\n"}
+	if len(raw) != len(expected) {
+		t.Fatalf("Expected %d lines, got %q", len(expected), raw)
+	}
+	for i := range expected {
+		if raw[i] != expected[i] {
+			t.Errorf("Line %d: expected %q, got %q", i, expected[i], raw[i])
+		}
+	}
+	if entries, _ := indexer.GetLineRange(2, 3); len(entries) != 1 || entries[0].Message != "partial 2" {
+		t.Errorf("Expected the re-read partial line, got %v", entries)
+	}
+
+	// Truncation can't be extended
+	if err := os.WriteFile(testFile, []byte("new\n"), 0644); err != nil {
+		t.Fatalf("Failed to truncate test file: %v", err)
+	}
+	if grown, err := indexer.Extend(); err != nil || grown {
+		t.Errorf("Expected Extend to report truncation, got %v, %v", grown, err)
+	}
+}
+
+func TestFastIndexer_CopyLinesKeepsOriginalBytes(t *testing.T) {
+	content := "2023-12-23 09:39:00 INFO start\r\n\x1b[31m2023-12-23 09:40:00 ERROR red\x1b[0m\r\n2023-12-23 09:45:00 INFO end\r\n2023-12-23 09:50:00 INFO after\n"
+	testFile := filepath.Join(t.TempDir(), "test.log")
+	if err := os.WriteFile(testFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	for _, limit := range []int64{0, 2 * indexEntrySize} {
+		indexer, err := NewFastIndexer(testFile, NewLogParser("UTC"))
+		if err != nil {
+			t.Fatalf("Failed to create indexer: %v", err)
+		}
+		indexer.SetMemoryLimit(limit)
+		if err := indexer.IndexFileUltraFast(); err != nil {
+			t.Fatalf("Indexing failed: %v", err)
+		}
+
+		var out strings.Builder
+		if _, err := indexer.CopyLines(&out, 1, 2); err != nil {
+			t.Fatalf("CopyLines failed: %v", err)
+		}
+		expected := "\x1b[31m2023-12-23 09:40:00 ERROR red\x1b[0m\r\n2023-12-23 09:45:00 INFO end\r\n"
+		if out.String() != expected {
+			t.Errorf("Stride %d: expected %q, got %q", indexer.IndexStride(), expected, out.String())
+		}
+
+		entries, _ := indexer.GetLineRange(2, 3)
+		if len(entries) != 1 || entries[0].Offset != int64(strings.Index(content, "2023-12-23 09:45")) || entries[0].Length != 30 {
+			t.Errorf("Stride %d: unexpected entry span %+v", indexer.IndexStride(), entries)
+		}
+		indexer.Close()
+	}
+}
+
+func TestFastIndexer_JSONRecords(t *testing.T) {
+	array := "[\n  {\n    \"severityText\": \"INFO\",\n    \"body\": \"first\"\n  },\n  {\n    \"severityText\": \"ERROR\",\n    \"body\": \"second\"\n  }\n]\n"
+	stream := "{\n  \"severityText\": \"INFO\",\n  \"body\": \"first\"\n}\n{\n  \"severityText\": \"ERROR\",\n  \"body\": \"second\"\n}\n"
+
+	for name, content := range map[string]string{"array": array, "stream": stream} {
+		testFile := filepath.Join(t.TempDir(), "test.json")
+		if err := os.WriteFile(testFile, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+		indexer, err := NewFastIndexer(testFile, NewLogParser("UTC"))
+		if err != nil {
+			t.Fatalf("Failed to create indexer: %v", err)
+		}
+		if err := indexer.IndexFileUltraFast(); err != nil {
+			t.Fatalf("%s: indexing failed: %v", name, err)
+		}
+
+		if indexer.GetLineCount() != 2 {
+			t.Fatalf("%s: expected 2 records, got %d", name, indexer.GetLineCount())
+		}
+		entries, err := indexer.GetLineRange(0, 2)
+		if err != nil || len(entries) != 2 {
+			t.Fatalf("%s: expected 2 entries, got %d (%v)", name, len(entries), err)
+		}
+		if entries[0].Message != "first" || entries[1].Message != "second" || entries[1].Level != ERROR {
+			t.Errorf("%s: unexpected entries %+v", name, entries)
+		}
+
+		// Each record keeps exactly its own bytes
+		second := content[strings.LastIndex(content, "{"):]
+		second = second[:strings.Index(second, "}")+1]
+		var out strings.Builder
+		if _, err := indexer.CopyLines(&out, 1, 1); err != nil {
+			t.Fatalf("%s: CopyLines failed: %v", name, err)
+		}
+		if out.String() != second {
+			t.Errorf("%s: expected record %q, got %q", name, second, out.String())
+		}
+		if ok, _ := indexer.Extend(); ok {
+			t.Errorf("%s: expected record files to need a full reindex", name)
+		}
+
+		// Records are referenced by the file line they start on
+		expectedLine := strings.Count(content[:strings.LastIndex(content, "{")], "\n") + 1
+		if _, line, ok := indexer.FileLine(1); !ok || line != expectedLine {
+			t.Errorf("%s: expected the second record on line %d, got %d", name, expectedLine, line)
+		}
+		indexer.Close()
+	}
+
+	// JSON lines stay indexed line by line
+	testFile := writeTestLog(t, []string{`{"body": "one"}`, `{"body": "two"}`, `{"body": "three"}`})
+	indexer, err := NewFastIndexer(testFile, NewLogParser("UTC"))
+	if err != nil {
+		t.Fatalf("Failed to create indexer: %v", err)
+	}
+	defer indexer.Close()
+	if err := indexer.IndexFileUltraFast(); err != nil {
+		t.Fatalf("Indexing failed: %v", err)
+	}
+	if indexer.GetLineCount() != 3 {
+		t.Errorf("Expected 3 JSON lines, got %d", indexer.GetLineCount())
+	}
+}
+
+func TestQuickDetectLevel_AgreesWithParser(t *testing.T) {
+	parser := NewLogParser("UTC")
+	lines := []string{
+		"2023-12-23 15:30:45 ERROR: disk full",
+		"2023-12-23 15:30:45 fatal: out of memory",
+		"2023-12-23 15:30:45 Warning: slow query",
+		"2023-12-23 15:30:45 trace: entering handler",
+		"2023-12-23 15:30:45 request served",
+		`{"severityNumber": 17, "body": "structured"}`,
+		"<34>1 2003-10-11T22:14:15.003Z host su - ID47 - failed",
+		`127.0.0.1 - - [23/Dec/2023:15:30:45 +0000] "GET / HTTP/1.1" 503 12`,
+		"  (0.3ms)  SELECT 1",
+		"\x1b[31mERROR\x1b[0m colored",
+		"2009/11/10 23:00:00 error.go:12: all good",
+		`time="2023-12-23T15:30:45Z" level=info msg="error budget ok"`,
+		"E1223 15:30:45.1 12 foo.cc:12] boom",
+	}
+
+	for _, line := range lines {
+		level := quickDetectLevel([]byte(line + "\n"))
+		if level == levelUnknown {
+			continue
+		}
+		if parsed := parser.ParseLogLine(line, "").Level; level != parsed {
+			t.Errorf("Quick level %v differs from parsed level %v for %q", level, parsed, line)
+		}
+	}
+	if quickDetectLevel([]byte(lines[5])) != levelUnknown || quickDetectLevel([]byte(lines[7])) != levelUnknown || quickDetectLevel([]byte(lines[11])) != levelUnknown || quickDetectLevel([]byte(lines[12])) != levelUnknown {
+		t.Error("Expected structured lines to be left to the parser")
+	}
+}
+
+func TestFastIndexer_RecordsLineLevels(t *testing.T) {
+	testFile := writeTestLog(t, []string{
+		"2023-12-23 15:30:45 INFO: start",
+		"2023-12-23 15:30:46 ERROR: failed",
+		`{"severityText": "WARN", "body": "structured"}`,
+		"2023-12-23 15:30:47 DEBUG: " + strings.Repeat("x", 300*1024), // spans two chunks
+	})
+	indexer, err := NewFastIndexer(testFile, NewLogParser("UTC"))
+	if err != nil {
+		t.Fatalf("Failed to create indexer: %v", err)
+	}
+	defer indexer.Close()
+	if err := indexer.IndexFileUltraFast(); err != nil {
+		t.Fatalf("Indexing failed: %v", err)
+	}
+
+	expected := []struct {
+		level LogLevel
+		known bool
+	}{{INFO, true}, {ERROR, true}, {levelUnknown, false}, {levelUnknown, false}}
+	for i, want := range expected {
+		if level, ok := indexer.LineLevel(i); level != want.level || ok != want.known {
+			t.Errorf("Line %d: expected level %v (%v), got %v (%v)", i, want.level, want.known, level, ok)
+		}
+	}
+}
+
+func TestApplyFilters_LevelOnlyReadsNoLines(t *testing.T) {
+	model := newIndexedTestModel(t, []string{
+		"2023-12-23 15:30:45 INFO: start",
+		"2023-12-23 15:30:46 ERROR: failed",
+		`{"severityText": "ERROR", "body": "structured"}`,
+		"2023-12-23 15:30:47 DEBUG: details",
+	}, 120, 40)
+	model.viewportHeight = 0 // Keep loadVisibleLines from reading
+	indexer := model.indexer.(*FastIndexer)
+	indexer.cache = make(map[int]LogEntry)
+
+	model.showInfo = false
+	model.showDebug = false
+	model.applyFilters()
+
+	if len(model.filteredIndices) != 2 || model.filteredIndices[0] != 1 || model.filteredIndices[1] != 2 {
+		t.Errorf("Expected the two errors, got %v", model.filteredIndices)
+	}
+	if _, read := indexer.cache[1]; read || len(indexer.cache) != 1 {
+		t.Errorf("Expected only the JSON line to be parsed, got %d cached lines", len(indexer.cache))
+	}
+}
+
+// parallelTestLog returns about size bytes of mixed log lines, with one line
+// longer than a whole chunk of the parallel scan
+func parallelTestLog(size int) string {
+	var content strings.Builder
+	for i := 0; content.Len() < size; i++ {
+		switch {
+		case i == 5000:
+			content.WriteString("2023-12-23 15:30:45 WARN: " + strings.Repeat("x", 700*1024) + "\n")
+		case i%7 == 0:
+			content.WriteString(fmt.Sprintf(`{"level":"error","msg":"request %d failed"}`+"\n", i))
+		default:
+			content.WriteString(fmt.Sprintf("2023-12-23 15:30:45 INFO: request %d served%s\n", i, strings.Repeat(".", i%50)))
+		}
+	}
+	return content.String()
+}
+
+func TestFastIndexer_ParallelScanMatchesSerial(t *testing.T) {
+	content := parallelTestLog(3 << 20)
+	testCases := map[string]string{
+		"trailing newline":    content,
+		"partial last line":   content + "2023-12-23 15:30:45 ERROR: cut off",
+		"exact multiple size": content[:12*scanBufferSize],
+	}
+
+	for name, data := range testCases {
+		testFile := filepath.Join(t.TempDir(), "test.log")
+		if err := os.WriteFile(testFile, []byte(data), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+
+		serial, err := NewFastIndexer(testFile, NewLogParser("UTC"))
+		if err != nil {
+			t.Fatalf("Failed to create indexer: %v", err)
+		}
+		defer serial.Close()
+		if err := serial.IndexFileUltraFast(); err != nil {
+			t.Fatalf("%s: serial indexing failed: %v", name, err)
+		}
+
+		parallel, err := NewFastIndexer(testFile, NewLogParser("UTC"))
+		if err != nil {
+			t.Fatalf("Failed to create indexer: %v", err)
+		}
+		defer parallel.Close()
+		if err := parallel.scanParallel(4); err != nil {
+			t.Fatalf("%s: parallel indexing failed: %v", name, err)
+		}
+
+		if len(parallel.indices) != len(serial.indices) {
+			t.Fatalf("%s: expected %d lines, got %d", name, len(serial.indices), len(parallel.indices))
+		}
+		for i := range serial.indices {
+			if parallel.indices[i] != serial.indices[i] {
+				t.Fatalf("%s: line %d differs: serial %+v, parallel %+v", name, i, serial.indices[i], parallel.indices[i])
+			}
+		}
+		if parallel.GetLineCount() != serial.GetLineCount() || parallel.scanEnd != serial.scanEnd ||
+			parallel.completeLines != serial.completeLines || parallel.partialLine != serial.partialLine {
+			t.Errorf("%s: expected the scan to end in the same state", name)
+		}
+		if read, total := parallel.Progress(); read != total {
+			t.Errorf("%s: expected progress %d, got %d", name, total, read)
+		}
+	}
+}
+
+// benchmarkIndexFile indexes a 128MB file, split over workers goroutines
+// when workers is above 1
+func benchmarkIndexFile(b *testing.B, workers int) {
+	testFile := filepath.Join(b.TempDir(), "bench.log")
+	if err := os.WriteFile(testFile, []byte(parallelTestLog(128<<20)), 0644); err != nil {
+		b.Fatalf("Failed to create test file: %v", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		indexer, err := NewFastIndexer(testFile, NewLogParser("UTC"))
+		if err != nil {
+			b.Fatalf("Failed to create indexer: %v", err)
+		}
+		if workers > 1 {
+			err = indexer.scanParallel(workers)
+		} else {
+			err = indexer.scan(io.NewSectionReader(indexer.file, 0, math.MaxInt64), 0, false, 0)
+		}
+		if err != nil {
+			b.Fatalf("Indexing failed: %v", err)
+		}
+		indexer.Close()
+	}
+}
+
+func BenchmarkIndexFile_Serial(b *testing.B)   { benchmarkIndexFile(b, 1) }
+func BenchmarkIndexFile_Parallel(b *testing.B) { benchmarkIndexFile(b, runtime.GOMAXPROCS(0)) }
+
+func BenchmarkApplyFilters_LevelOnly(b *testing.B) {
+	lines := make([]string, 200000)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("2023-12-23 15:30:45 INFO: request %d served", i)
+		if i%100 == 0 {
+			lines[i] = fmt.Sprintf("2023-12-23 15:30:45 ERROR: request %d failed", i)
+		}
+	}
+	testFile := filepath.Join(b.TempDir(), "bench.log")
+	if err := os.WriteFile(testFile, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		b.Fatalf("Failed to create test file: %v", err)
+	}
+	app := NewUnifiedApp(&Config{MaxLines: 100, Files: []string{testFile}, RefreshRate: 1, Timezone: "UTC"})
+	app.indexFile(testFile)
+	app.model.showInfo = false
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		app.model.applyFilters()
+	}
+}
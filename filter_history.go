@@ -0,0 +1,72 @@
+package main
+
+import "github.com/charmbracelet/bubbles/textinput"
+
+// filterHistoryLimit is how many include and exclude patterns are remembered
+const filterHistoryLimit = 50
+
+// historyFor returns the history behind an input, or nil if it keeps none
+func (m *UnifiedModel) historyFor(input *textinput.Model) *[]string {
+	switch input {
+	case &m.includeInput:
+		return &m.includeHistory
+	case &m.excludeInput:
+		return &m.excludeHistory
+	}
+	return nil
+}
+
+// rememberFilter adds the pattern just applied to its input's history,
+// newest last. A pattern used again moves to the end instead of repeating
+func (m *UnifiedModel) rememberFilter() {
+	history := m.historyFor(m.activeInput)
+	if history == nil {
+		return
+	}
+	value := m.activeInput.Value()
+	if value == "" {
+		return
+	}
+
+	kept := make([]string, 0, len(*history)+1)
+	for _, previous := range *history {
+		if previous != value {
+			kept = append(kept, previous)
+		}
+	}
+	kept = append(kept, value)
+	if len(kept) > filterHistoryLimit {
+		kept = kept[len(kept)-filterHistoryLimit:]
+	}
+	*history = kept
+}
+
+// recallFilter steps through the history of the input being edited, older
+// with step -1 (up) and newer with 1 (down), like a shell. Stepping past the
+// newest pattern brings back what was typed before browsing
+func (m *UnifiedModel) recallFilter(step int) {
+	history := m.historyFor(m.activeInput)
+	if history == nil || len(*history) == 0 {
+		return
+	}
+
+	pos := m.historyPos
+	if pos < 0 {
+		if step > 0 {
+			return
+		}
+		m.historyDraft = m.activeInput.Value()
+		pos = len(*history)
+	}
+	pos = max(0, pos+step)
+
+	if pos >= len(*history) {
+		m.historyPos = -1
+		m.activeInput.SetValue(m.historyDraft)
+	} else {
+		m.historyPos = pos
+		m.activeInput.SetValue((*history)[pos])
+	}
+	m.activeInput.CursorEnd()
+	m.applyFilters()
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// levelCounts counts entries by level
+type levelCounts [ERROR + 1]int
+
+// filterStats counts how many entries each stage of the last filter pass
+// removed, in the order the stages run
+type filterStats struct {
+	total      int
+	level      int
+	source     int
+	time       int
+	include    int
+	exclude    int
+	suppressed int         // Hidden with x
+	levels     levelCounts // Entries at each level, before any filter
+	shown      levelCounts // Entries at each level that passed every filter
+
+	// counting keeps levels and shown, off with --no-stats
+	counting bool
+}
+
+// filterStage is the filter stage that removed an entry, or stageShown for
+// one that passed every filter
+type filterStage int
+
+const (
+	stageShown filterStage = iota
+	stageLevel
+	stageSource
+	stageTime
+	stageInclude
+	stageExclude
+	stageSuppressed
+)
+
+// count counts n entries at level that stopped at stage, negative n for
+// entries that are gone, e.g. streamed ones past MaxLines
+func (s *filterStats) count(stage filterStage, level LogLevel, n int) {
+	if s.counting {
+		s.levels[level] += n
+		if stage == stageShown {
+			s.shown[level] += n
+		}
+	}
+	switch stage {
+	case stageLevel:
+		s.level += n
+	case stageSource:
+		s.source += n
+	case stageTime:
+		s.time += n
+	case stageInclude:
+		s.include += n
+	case stageExclude:
+		s.exclude += n
+	case stageSuppressed:
+		s.suppressed += n
+	}
+}
+
+// diagnostic explains which stage emptied the list, or "" when something
+// is left or there was nothing to filter
+func (s filterStats) diagnostic(include, exclude, source string) string {
+	if s.total == 0 || s.level+s.source+s.time+s.include+s.exclude+s.suppressed < s.total {
+		return ""
+	}
+
+	// Earlier stages left entries, so the last one to remove any emptied the list
+	switch {
+	case s.suppressed > 0:
+		return fmt.Sprintf("0 results: the lines hidden with x removed all %s entries, X to show them", formatCount(s.suppressed))
+	case s.exclude > 0:
+		if s.include > 0 || include != "" {
+			return fmt.Sprintf("0 results: exclude '%s' removed all %s entries matched by include", exclude, formatCount(s.exclude))
+		}
+		return fmt.Sprintf("0 results: exclude '%s' removed all %s entries", exclude, formatCount(s.exclude))
+	case s.include > 0:
+		return fmt.Sprintf("0 results: include '%s' matched none of %s entries", include, formatCount(s.include))
+	case s.time > 0:
+		return fmt.Sprintf("0 results: the time range removed all %s entries", formatCount(s.time))
+	case s.source > 0:
+		return fmt.Sprintf("0 results: source '%s' matched none of %s entries", source, formatCount(s.source))
+	default:
+		return fmt.Sprintf("0 results: the level toggles hide all %s entries", formatCount(s.level))
+	}
+}
+
+// formatCount formats n with thousands separators, e.g. 1,204
+func formatCount(n int) string {
+	if n < 0 {
+		return "-" + formatCount(-n)
+	}
+	digits := strconv.Itoa(n)
+	for i := len(digits) - 3; i > 0; i -= 3 {
+		digits = digits[:i] + "," + digits[i:]
+	}
+	return digits
+}
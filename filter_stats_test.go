@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFilterStats_Diagnostic(t *testing.T) {
+	testCases := []struct {
+		stats    filterStats
+		expected string
+	}{
+		{filterStats{total: 1204, exclude: 1204}, "0 results: exclude 'error' removed all 1,204 entries matched by include"},
+		{filterStats{total: 10, level: 4, include: 6}, "0 results: include 'error' matched none of 6 entries"},
+		{filterStats{total: 10, level: 4, time: 6}, "0 results: the time range removed all 6 entries"},
+		{filterStats{total: 10, level: 4, source: 6}, "0 results: source 'api' matched none of 6 entries"},
+		{filterStats{total: 10, level: 10}, "0 results: the level toggles hide all 10 entries"},
+		{filterStats{total: 10, include: 4, suppressed: 6}, "0 results: the lines hidden with x removed all 6 entries, X to show them"},
+		{filterStats{total: 10, include: 9}, ""},
+		{filterStats{}, ""},
+	}
+
+	for _, tc := range testCases {
+		if got := tc.stats.diagnostic("error", "error", "api"); got != tc.expected {
+			t.Errorf("%+v: expected %q, got %q", tc.stats, tc.expected, got)
+		}
+	}
+}
+
+func TestFormatCount(t *testing.T) {
+	for n, expected := range map[int]string{0: "0", 999: "999", 1000: "1,000", 1234567: "1,234,567", -1204: "-1,204"} {
+		if got := formatCount(n); got != expected {
+			t.Errorf("formatCount(%d): expected %q, got %q", n, expected, got)
+		}
+	}
+}
+
+func TestApplyFilters_ExplainsEmptyResult(t *testing.T) {
+	lines := make([]string, 5)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("2023-12-23 15:30:45 ERROR: request %d failed", i)
+	}
+	model := newIndexedTestModel(t, lines, 160, 40)
+
+	// Include and exclude cancel each other out
+	model.includeInput.SetValue("failed")
+	model.excludeInput.SetValue("request")
+	model.applyFilters()
+
+	if len(model.filteredIndices) != 0 {
+		t.Fatalf("Expected no results, got %d", len(model.filteredIndices))
+	}
+	if view := model.renderLogStream(); !strings.Contains(view, "0 results: exclude 'request' removed all 5 entries matched by include") {
+		t.Errorf("Expected a diagnostic in the log stream, got:\n%s", view)
+	}
+
+	// Nothing to explain once entries show again
+	model.excludeInput.SetValue("")
+	model.applyFilters()
+	if view := model.renderLogStream(); strings.Contains(view, "0 results") {
+		t.Errorf("Expected no diagnostic, got:\n%s", view)
+	}
+}
+
+func TestLevelCounts_ShownBeforeFilters(t *testing.T) {
+	lines := []string{
+		"2023-12-23 15:30:45 ERROR: disk full",
+		"2023-12-23 15:30:45 ERROR: disk still full",
+		"2023-12-23 15:30:45 WARN: slow query",
+		`{"level":"debug","msg":"parsed to know its level"}`,
+		"2023-12-23 15:30:45 request served",
+	}
+	model := newIndexedTestModel(t, lines, 160, 60)
+
+	// Hidden levels and the include filter don't change the counts
+	model.showError = false
+	model.includeInput.SetValue("served")
+	model.applyFilters()
+
+	panel := model.renderLeftPanel()
+	for _, expected := range []string{"ERROR (2)", "WARN (1)", "INFO (1)", "DEBUG (1)"} {
+		if !strings.Contains(panel, expected) {
+			t.Errorf("Expected %q in the level section, got:\n%s", expected, panel)
+		}
+	}
+}
+
+func TestLevelCounts_KeptForStreams(t *testing.T) {
+	model := NewUnifiedModel(&Config{Timezone: "UTC", MaxLines: 3})
+	if _, counted := model.levelTotals(); counted {
+		t.Error("Expected no counts before anything was read")
+	}
+
+	model.AddLogBatch([]LogEntry{{Level: ERROR}, {Level: WARN}, {Level: INFO}})
+	model.AddLogBatch([]LogEntry{{Level: INFO}, {Level: INFO}})
+
+	// The oldest entries were dropped past MaxLines
+	counts, counted := model.levelTotals()
+	if !counted || counts[ERROR] != 0 || counts[WARN] != 0 || counts[INFO] != 3 {
+		t.Errorf("Expected only the 3 kept INFO entries counted, got %v", counts)
+	}
+}
+
+func TestLevelCounts_SkippedWithNoStats(t *testing.T) {
+	lines := []string{
+		"2023-12-23 15:30:45 ERROR: disk full",
+		"2023-12-23 15:30:45 request served",
+	}
+	model := newIndexedTestModel(t, lines, 160, 60)
+	model.config.NoStats = true
+	model.applyFilters()
+
+	if model.filterStats.levels != (levelCounts{}) || model.filterStats.shown != (levelCounts{}) {
+		t.Errorf("Expected no level counts, got %v and %v", model.filterStats.levels, model.filterStats.shown)
+	}
+	model.Update(keyMsg("s"))
+	panel := model.renderLeftPanel()
+	if strings.Contains(panel, "ERROR (") || strings.Contains(panel, "Summary:") {
+		t.Errorf("Expected no counts or summary, got:\n%s", panel)
+	}
+
+	// Diagnostics still explain an empty list
+	model.includeInput.SetValue("nothing")
+	model.applyFilters()
+	if !strings.Contains(model.renderLogStream(), "matched none of 2 entries") {
+		t.Errorf("Expected the empty result explained, got:\n%s", model.renderLogStream())
+	}
+
+	stream := NewUnifiedModel(&Config{Timezone: "UTC", NoStats: true})
+	stream.AddLogBatch([]LogEntry{{Level: ERROR}, {Level: INFO}})
+	if stream.streamLevels != (levelCounts{}) || stream.streamShown != (levelCounts{}) {
+		t.Errorf("Expected no stream counts, got %v and %v", stream.streamLevels, stream.streamShown)
+	}
+}
+
+func TestLevelSummary_ChartsFilteredEntries(t *testing.T) {
+	lines := []string{
+		"2023-12-23 15:30:45 ERROR: disk full",
+		"2023-12-23 15:30:45 ERROR: disk still full",
+		"2023-12-23 15:30:45 WARN: disk slow",
+		"2023-12-23 15:30:45 WARN: slow query",
+		"2023-12-23 15:30:45 request served",
+	}
+	model := newIndexedTestModel(t, lines, 160, 60)
+
+	if strings.Contains(model.renderLeftPanel(), "Summary:") {
+		t.Fatal("Expected no summary until s is pressed")
+	}
+	model.Update(keyMsg("s"))
+
+	// Only entries passing the filters are charted, bars share the width
+	model.includeInput.SetValue("disk")
+	model.applyFilters()
+	summary := model.renderLevelSummary(36)
+	for _, expected := range []string{
+		"ERROR " + strings.Repeat("█", 18) + " 2\n",
+		"WARN  " + strings.Repeat("█", 9) + " 1\n",
+		"INFO   0\n",
+	} {
+		if !strings.Contains(summary, expected) {
+			t.Errorf("Expected %q in the summary, got:\n%s", expected, summary)
+		}
+	}
+	if panel := model.renderLeftPanel(); !strings.Contains(panel, "Summary:") {
+		t.Errorf("Expected the summary in the left panel, got:\n%s", panel)
+	}
+}
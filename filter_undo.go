@@ -0,0 +1,67 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// filterPatterns are the include and exclude patterns at one point in time
+type filterPatterns struct {
+	include string
+	exclude string
+}
+
+// startEdit focuses an input for typing, remembering the patterns as they
+// were so clearing one can be undone
+func (m *UnifiedModel) startEdit(input *textinput.Model) tea.Cmd {
+	m.editMode = true
+	m.activeInput = input
+	m.editStart = filterPatterns{include: m.includeInput.Value(), exclude: m.excludeInput.Value()}
+	m.historyPos = -1
+	input.Focus()
+	return textinput.Blink
+}
+
+// rememberClearedPattern keeps the patterns from before the edit ending now
+// when it emptied the include or exclude pattern, for u to restore
+func (m *UnifiedModel) rememberClearedPattern() {
+	var before string
+	switch m.activeInput {
+	case &m.includeInput:
+		before = m.editStart.include
+	case &m.excludeInput:
+		before = m.editStart.exclude
+	default:
+		return
+	}
+	if before != "" && m.activeInput.Value() == "" {
+		cleared := m.editStart
+		m.clearedPatterns = &cleared
+		m.notice = "Pattern cleared, u to undo"
+	}
+}
+
+// clearPatterns empties the include and exclude patterns, keeping them for u
+// to restore
+func (m *UnifiedModel) clearPatterns() {
+	if m.includeInput.Value() == "" && m.excludeInput.Value() == "" {
+		return
+	}
+	m.clearedPatterns = &filterPatterns{include: m.includeInput.Value(), exclude: m.excludeInput.Value()}
+	m.includeInput.SetValue("")
+	m.excludeInput.SetValue("")
+	m.notice = "Filters cleared, u to undo"
+	m.applyFilters()
+}
+
+// undoClear restores the patterns from before the last pattern was cleared
+func (m *UnifiedModel) undoClear() {
+	if m.clearedPatterns == nil {
+		return
+	}
+	m.includeInput.SetValue(m.clearedPatterns.include)
+	m.excludeInput.SetValue(m.clearedPatterns.exclude)
+	m.clearedPatterns = nil
+	m.notice = "Patterns restored"
+	m.applyFilters()
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
+)
+
+// rotatedLogRegex matches names logrotate gives old logs, e.g. app.log.1,
+// app.log.2.gz or app.log-20231223
+var rotatedLogRegex = regexp.MustCompile(`\.(\d+|gz|bz2|xz|zst)$|-\d{8}(\d{2})?$`)
+
+// fileChangedMsg reports a change to a followed file
+type fileChangedMsg struct {
+	filename string
+	replaced bool // A new file took its place, e.g. after log rotation
+}
+
+// followFile watches the file's directory, so rotation (rename + create) is
+// seen as well as appends and truncation
+func (a *UnifiedApp) followFile(filename string) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	if err := watcher.Add(filepath.Dir(filename)); err != nil {
+		watcher.Close()
+		return err
+	}
+
+	a.watcher = watcher
+	go a.watch(watcher, filename)
+	return nil
+}
+
+// watch forwards events for filename to the model until the watcher is closed
+func (a *UnifiedApp) watch(watcher *fsnotify.Watcher, filename string) {
+	target := filepath.Clean(filename)
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) != target {
+				continue
+			}
+			// Remove and rename are ignored, the replacement shows up as Create
+			if event.Has(fsnotify.Create) {
+				a.program.Send(fileChangedMsg{filename: filename, replaced: true})
+			} else if event.Has(fsnotify.Write) {
+				a.program.Send(fileChangedMsg{filename: filename})
+			}
+		case _, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// followDirs watches the directories of the merged files, and those given
+// with --follow, so lines appended to any file, and new files, are merged live
+func (a *UnifiedApp) followDirs() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	dirs := make(map[string]bool)
+	for _, file := range a.config.Files {
+		dirs[filepath.Dir(file)] = true
+	}
+	for _, dir := range a.config.FollowDirs {
+		dirs[filepath.Clean(dir)] = true
+	}
+	for dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			watcher.Close()
+			return err
+		}
+	}
+
+	a.watcher = watcher
+	go a.watchDirs(watcher)
+	return nil
+}
+
+// watchDirs forwards events for every file in the watched directories to
+// the model until the watcher is closed
+func (a *UnifiedApp) watchDirs(watcher *fsnotify.Watcher) {
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err != nil || info.IsDir() {
+					continue
+				}
+				a.program.Send(fileChangedMsg{filename: event.Name, replaced: true})
+			} else if event.Has(fsnotify.Write) {
+				a.program.Send(fileChangedMsg{filename: event.Name})
+			}
+		case _, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// applyFileChange indexes appended lines, or returns the command indexing
+// the file again when it was truncated or replaced
+func (m *UnifiedModel) applyFileChange(msg fileChangedMsg) tea.Cmd {
+	if m.indexer == nil || m.indexing {
+		return nil
+	}
+	if merged, ok := m.indexer.(*MergedIndexer); ok {
+		return m.followMerged(merged, msg)
+	}
+
+	if !msg.replaced {
+		first := m.retractLastLine()
+		grown, err := m.indexer.Extend()
+		if err == nil && grown {
+			m.linesChanged(first)
+			return nil
+		}
+	}
+
+	return m.reindexFile(msg.filename)
+}
+
+// followsDir reports whether new files in dir join the merged timeline
+func (m *UnifiedModel) followsDir(dir string) bool {
+	for _, followed := range m.config.FollowDirs {
+		if filepath.Clean(followed) == filepath.Clean(dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// linesChanged shows the lines the indexer gained, filtering only those from
+// line first on, and keeps the tail or the newest match in view
+func (m *UnifiedModel) linesChanged(first int) {
+	lines := m.indexer.GetLineCount()
+	if lines == m.totalLines && first == lines {
+		return
+	}
+	if m.paused && lines > m.totalLines {
+		m.pausedLines += lines - m.totalLines
+	}
+	m.lineRate.add(time.Now(), lines-m.totalLines)
+	shrank := lines < m.totalLines
+	m.totalLines = lines
+
+	// Lines dropped from a merged file move the ones after them, and the
+	// file under a stream grows in front of the streamed lines
+	if _, streamed := m.indexer.(*StreamIndexer); streamed || shrank {
+		m.applyFilters()
+	} else {
+		m.showNewLines(first)
+	}
+	if m.tailing {
+		m.scrollToBottom()
+	} else if m.followMatches {
+		m.followNewMatch(first)
+	}
+}
+
+// followMerged adds the lines appended to a file to the end of the merged
+// timeline. New files in a followed directory are merged from their first
+// line, and so is a file replaced by rotation, whose old lines stay. A
+// rotated name showing up, like app.log.1, holds lines already merged and
+// is skipped. Whole files are indexed off the event loop, the returned
+// command hands them back once they're done
+func (m *UnifiedModel) followMerged(merged *MergedIndexer, msg fileChangedMsg) tea.Cmd {
+	if m.followIndexing[msg.filename] {
+		return nil // Its lines so far join with it
+	}
+	if merged.Has(msg.filename) && !msg.replaced {
+		first := m.retractLastLine()
+		grown, err := merged.ExtendFile(msg.filename)
+		if err != nil {
+			m.notice = fmt.Sprintf("Follow %s: %v", m.sourceLabel(msg.filename), err)
+		}
+		if err != nil || grown {
+			m.linesChanged(first)
+			return nil
+		}
+		// Truncated in place, so its old lines can't be read anymore
+		merged.Drop(msg.filename)
+		m.linesChanged(first)
+	} else if !merged.Has(msg.filename) && (!m.followsDir(filepath.Dir(msg.filename)) || rotatedLogRegex.MatchString(msg.filename)) {
+		return nil
+	}
+
+	if m.followIndexing == nil {
+		m.followIndexing = make(map[string]bool)
+	}
+	m.followIndexing[msg.filename] = true
+	filename, parser, limit := msg.filename, m.parser, m.config.MaxIndexMemory
+	return func() tea.Msg {
+		indexer, err := NewFastIndexer(filename, parser)
+		if err != nil {
+			return followIndexedMsg{merged: merged, filename: filename, err: err}
+		}
+		indexer.SetMemoryLimit(limit)
+		if err := indexer.IndexFileUltraFast(); err != nil {
+			indexer.Close()
+			return followIndexedMsg{merged: merged, filename: filename, err: err}
+		}
+		return followIndexedMsg{merged: merged, indexer: indexer, filename: filename}
+	}
+}
+
+// addFollowed merges a file indexed by followMerged, with the lines written
+// to it meanwhile, unless the timeline it was indexed for was replaced
+func (m *UnifiedModel) addFollowed(msg followIndexedMsg) {
+	delete(m.followIndexing, msg.filename)
+	if msg.err != nil {
+		m.notice = fmt.Sprintf("Follow %s: %v", m.sourceLabel(msg.filename), msg.err)
+		return
+	}
+	if m.indexer != msg.merged {
+		msg.indexer.Close()
+		return
+	}
+
+	first := m.totalLines
+	msg.merged.Add(msg.indexer)
+	if _, err := msg.merged.ExtendFile(msg.filename); err != nil {
+		m.notice = fmt.Sprintf("Follow %s: %v", m.sourceLabel(msg.filename), err)
+	}
+	m.linesChanged(first)
+}
+
+// toggleFollowMatches switches between staying on the newest matching line
+// and the plain bottom-follow of tailing, which it replaces while active
+func (m *UnifiedModel) toggleFollowMatches() {
+	m.followMatches = !m.followMatches
+	if m.followMatches {
+		m.tailing = false
+	}
+}
+
+// followNewMatch selects the newest line from the first new line on that
+// matches the search, or the include patterns when nothing is searched.
+// Lines that don't match leave the selection where it is
+func (m *UnifiedModel) followNewMatch(firstNew int) {
+	patterns := m.parseFilterPatterns(m.includeInput.Value())
+	for pos := len(m.filteredIndices) - 1; pos >= 0 && m.filteredIndices[pos] >= firstNew; pos-- {
+		entries, err := m.indexer.GetLineRange(m.filteredIndices[pos], m.filteredIndices[pos]+1)
+		if err != nil || len(entries) == 0 {
+			continue
+		}
+
+		matched := false
+		if m.searchQuery != "" {
+			matched = m.matchesPattern(entries[0].Message, m.searchQuery)
+		} else {
+			_, matched = m.includes(entries[0], patterns)
+		}
+		if matched {
+			m.jumpToPosition(pos)
+			return
+		}
+	}
+}
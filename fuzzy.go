@@ -0,0 +1,61 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// fuzzyMatch finds the runes of pattern in text in order, not necessarily
+// next to each other, and returns the byte offsets of the matched runes or
+// nil. Each rune takes its first match after the previous one, so checking
+// a line is a single pass
+func fuzzyMatch(text, pattern string, caseSensitive bool) []int {
+	if pattern == "" {
+		return []int{}
+	}
+	if !caseSensitive {
+		pattern = strings.ToLower(pattern)
+	}
+
+	wanted, size := utf8.DecodeRuneInString(pattern)
+	var matched []int
+	for offset, r := range text {
+		if !caseSensitive {
+			r = unicode.ToLower(r)
+		}
+		if r != wanted {
+			continue
+		}
+		matched = append(matched, offset)
+		pattern = pattern[size:]
+		if pattern == "" {
+			return matched
+		}
+		wanted, size = utf8.DecodeRuneInString(pattern)
+	}
+	return nil
+}
+
+// highlightFuzzy highlights the runes of message at the offsets matched by
+// fuzzyMatch, consecutive ones together
+func highlightFuzzy(message string, offsets []int, style lipgloss.Style) string {
+	var b strings.Builder
+	last := 0
+	for i := 0; i < len(offsets); {
+		start := offsets[i]
+		_, size := utf8.DecodeRuneInString(message[start:])
+		end := start + size
+		for i++; i < len(offsets) && offsets[i] == end; i++ {
+			_, size = utf8.DecodeRuneInString(message[end:])
+			end += size
+		}
+		b.WriteString(message[last:start])
+		b.WriteString(style.Render(message[start:end]))
+		last = end
+	}
+	b.WriteString(message[last:])
+	return b.String()
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		text, pattern string
+		caseSensitive bool
+		expected      []int
+	}{
+		{"user session timeout", "usrtmout", false, []int{0, 1, 3, 13, 15, 17, 18, 19}},
+		{"user session timeout", "", false, []int{}},
+		{"User Timeout", "ut", false, []int{0, 5}},
+		{"User Timeout", "UT", true, []int{0, 5}},
+		{"User Timeout", "uT", true, nil},
+		{"timeout", "tiemout", false, nil},
+		{"héllo wörld", "hwd", false, []int{0, 7, 12}},
+	}
+	for _, tt := range tests {
+		if got := fuzzyMatch(tt.text, tt.pattern, tt.caseSensitive); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("fuzzyMatch(%q, %q) = %v, expected %v", tt.text, tt.pattern, got, tt.expected)
+		}
+	}
+}
+
+func TestFuzzy_FiltersAndHighlights(t *testing.T) {
+	model := newIndexedTestModel(t, []string{
+		"ERROR: user session timeout",
+		"INFO: served request",
+		"WARN: upstream timeout",
+	}, 120, 30)
+
+	model.leftPanelItem = regexItem
+	model.updateLeftPanel(keyMsg("enter"))
+	model.leftPanelItem = fuzzyItem
+	model.updateLeftPanel(keyMsg("enter"))
+	if !model.fuzzy || model.useRegex {
+		t.Fatalf("Expected fuzzy to replace regex, got fuzzy %v regex %v", model.fuzzy, model.useRegex)
+	}
+
+	model.includeInput.SetValue("sesstmout")
+	model.applyFilters()
+	if !reflect.DeepEqual(model.filteredIndices, []int{0}) {
+		t.Errorf("Expected only the session timeout, got %v", model.filteredIndices)
+	}
+
+	useANSIColors(t)
+	highlighted := model.highlightMatches("ERROR: user session timeout", "")
+	if ansi.Strip(highlighted) != "ERROR: user session timeout" || !strings.Contains(highlighted, "mout\x1b[0m") {
+		t.Errorf("Expected the matched characters highlighted, got %q", highlighted)
+	}
+	if state := model.filterState(); !state.Fuzzy || state.UseRegex {
+		t.Errorf("Expected fuzzy matching saved, got %+v", state)
+	}
+	if !strings.Contains(model.buildCommandLine(), "--fuzzy") {
+		t.Errorf("Expected --fuzzy in the command line, got %s", model.buildCommandLine())
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// gotoFlash is how long the row jumped to stays highlighted
+const gotoFlash = 1500 * time.Millisecond
+
+// startGotoLine opens the line number prompt over the log stream
+func (m *UnifiedModel) startGotoLine() tea.Cmd {
+	m.gotoOpen = true
+	m.gotoTime = false
+	m.gotoInput.Placeholder = "line"
+	m.gotoInput.CharLimit = 12
+	m.gotoInput.SetValue("")
+	m.gotoInput.Focus()
+	return textinput.Blink
+}
+
+// updateGotoLine handles keys while the line number prompt, or the @ time
+// prompt, is open. Only digits are typed for a line, Enter jumps and Esc
+// closes the prompt
+func (m *UnifiedModel) updateGotoLine(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.gotoOpen = false
+		m.gotoInput.Blur()
+		return m, nil
+	case "enter":
+		m.gotoOpen = false
+		m.gotoInput.Blur()
+		if m.gotoTime {
+			m.gotoTimeValue(m.gotoInput.Value())
+		} else if line, err := strconv.Atoi(m.gotoInput.Value()); err == nil {
+			m.gotoLine(line)
+		}
+		return m, nil
+	}
+
+	if msg.Type == tea.KeyRunes && !m.gotoTime {
+		for _, r := range msg.Runes {
+			if r < '0' || r > '9' {
+				return m, nil
+			}
+		}
+	}
+	var cmd tea.Cmd
+	m.gotoInput, cmd = m.gotoInput.Update(msg)
+	return m, cmd
+}
+
+// gotoLine selects and centers file line n, counted from 1. Numbers past the
+// end go to the last line, and a line hidden by the filters to the next one
+// shown
+func (m *UnifiedModel) gotoLine(n int) {
+	if m.indexer == nil || m.totalLines == 0 {
+		m.notice = "Go to line: no indexed file"
+		return
+	}
+	if len(m.filteredIndices) == 0 {
+		m.notice = "Go to line: no lines shown"
+		return
+	}
+	target := min(max(n, 1), m.totalLines) - 1
+
+	pos := m.nearestPosition(target)
+	if line := m.filteredIndices[pos]; line != target {
+		m.notice = fmt.Sprintf("Line %d is filtered out, showing line %d", target+1, line+1)
+	} else {
+		m.notice = ""
+	}
+
+	m.flashPosition(pos)
+}
+
+// flashPosition selects and centers position pos, highlighting it for a
+// moment
+func (m *UnifiedModel) flashPosition(pos int) {
+	m.followMatches = false
+	m.jumpToPosition(pos)
+	m.flashLine = m.filteredIndices[pos]
+	m.flashUntil = time.Now().Add(gotoFlash)
+}
+
+// selectionStyle highlights the selected row, more strongly for a moment
+// right after jumping to it
+func (m *UnifiedModel) selectionStyle() lipgloss.Style {
+	if line, ok := m.selectedLine(); ok && line == m.flashLine && time.Now().Before(m.flashUntil) {
+		return m.flashStyle
+	}
+	return m.selectedStyle
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"sort"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Layouts of a time of day typed at the @ prompt, on the selected entry's day
+var timeOfDayLayouts = []string{"15:04:05", "15:04"}
+
+// startGotoTime opens the prompt for @, asking for a time to jump to
+func (m *UnifiedModel) startGotoTime() tea.Cmd {
+	cmd := m.startGotoLine()
+	m.gotoTime = true
+	m.gotoInput.Placeholder = "14:00, 2006-01-02 15:04:05 or -1h"
+	m.gotoInput.CharLimit = 32
+	return cmd
+}
+
+// gotoTimeValue selects the first shown entry at or after the time typed at
+// the @ prompt. When the shown entries are in time order, as in most files,
+// it's found by binary search over the times recorded while indexing.
+// Otherwise every entry is checked, with a warning
+func (m *UnifiedModel) gotoTimeValue(value string) {
+	if m.indexer == nil || m.totalLines == 0 {
+		m.notice = "Go to time: no indexed file"
+		return
+	}
+	if len(m.filteredIndices) == 0 {
+		m.notice = "Go to time: no lines shown"
+		return
+	}
+	target, err := m.parseJumpTime(value, time.Now())
+	if err != nil {
+		m.notice = "Go to time: " + err.Error()
+		return
+	}
+
+	var pos int
+	if m.timesOrdered() {
+		pos = sort.Search(len(m.filteredIndices), func(p int) bool {
+			t, ok := m.timeAt(p)
+			return ok && !t.Before(target)
+		})
+		m.notice = ""
+	} else {
+		pos = len(m.filteredIndices)
+		for p, line := range m.filteredIndices {
+			if t, ok := m.lineTime(line); ok && !t.Before(target) {
+				pos = p
+				break
+			}
+		}
+		m.notice = "Timestamps are out of order, checked every line"
+	}
+	if pos == len(m.filteredIndices) {
+		pos--
+		m.notice = "Nothing at or after " + target.In(m.displayZone).Format(quickTimeLayout) + ", showing the last line"
+	}
+
+	m.flashPosition(pos)
+}
+
+// parseJumpTime reads a time of day, on the day of the selected entry in the
+// display zone, or an absolute or relative time like --since
+func (m *UnifiedModel) parseJumpTime(value string, now time.Time) (time.Time, error) {
+	for _, layout := range timeOfDayLayouts {
+		clock, err := time.Parse(layout, value)
+		if err != nil {
+			continue
+		}
+		day := now
+		if line, ok := m.selectedLine(); ok {
+			if t, ok := m.lineTime(line); ok {
+				day = t
+			}
+		}
+		day = day.In(m.displayZone)
+		return time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, m.displayZone), nil
+	}
+	return parseTimeBound(value, m.displayZone, now)
+}
+
+// lineTime returns the time of file line n, from the index when it was found
+// while indexing. Lines without a timestamp report false
+func (m *UnifiedModel) lineTime(n int) (time.Time, bool) {
+	if t, known := m.indexer.LineTime(n); known {
+		return t, true
+	}
+	entries, err := m.indexer.GetLineRange(n, n+1)
+	if err != nil || len(entries) == 0 || entries[0].Time.IsZero() {
+		return time.Time{}, false
+	}
+	return entries[0].Time, true
+}
+
+// timeAt returns the time of the entry shown at position pos, or of the last
+// one before it with a timestamp, as a stack trace belongs to the entry above
+func (m *UnifiedModel) timeAt(pos int) (time.Time, bool) {
+	for ; pos >= 0; pos-- {
+		if t, ok := m.lineTime(m.filteredIndices[pos]); ok {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// timesOrdered reports whether the shown entries are in time order, judged
+// by the times recorded while indexing so nothing has to be read. A sorted
+// view isn't in file order, and newest first for a time sort
+func (m *UnifiedModel) timesOrdered() bool {
+	if m.sortMode != sortNone {
+		return false
+	}
+	var last time.Time
+	for _, line := range m.filteredIndices {
+		t, known := m.indexer.LineTime(line)
+		if !known {
+			continue
+		}
+		if t.Before(last) {
+			return false
+		}
+		last = t
+	}
+	return true
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQuickDetectTime(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	tests := []struct {
+		line     string
+		zone     *time.Location
+		expected time.Time
+	}{
+		{"2023-12-23 15:30:45 ERROR: boom", time.UTC, time.Date(2023, 12, 23, 15, 30, 45, 0, time.UTC)},
+		{"  2023-12-23 15:30:45.250 INFO: ok", time.UTC, time.Date(2023, 12, 23, 15, 30, 45, 250000000, time.UTC)},
+		{"2023-12-23 15:30:45. INFO: no fraction", time.UTC, time.Date(2023, 12, 23, 15, 30, 45, 0, time.UTC)},
+		{"2023-12-23 15:30:45 INFO: local", berlin, time.Date(2023, 12, 23, 15, 30, 45, 0, berlin)},
+		{"INFO: 2023-12-23 15:30:45 later in the line", time.UTC, time.Time{}},
+		{"2023-12-23T15:30:45Z INFO: ISO", time.UTC, time.Time{}},
+		{"2023-13-23 15:30:45 INFO: bad month", time.UTC, time.Time{}},
+	}
+	for _, tt := range tests {
+		got := quickDetectTime([]byte(tt.line), tt.zone)
+		expected := int64(0)
+		if !tt.expected.IsZero() {
+			expected = tt.expected.UnixNano()
+		}
+		if got != expected {
+			t.Errorf("quickDetectTime(%q) = %d, expected %d", tt.line, got, expected)
+		}
+
+		// The index must agree with the parser
+		if entry := NewLogParser(tt.zone.String()).ParseLogLine(tt.line, "app.log"); got != 0 && entry.Time.UnixNano() != got {
+			t.Errorf("Expected %q indexed at its parsed time %v, got %v", tt.line, entry.Time, time.Unix(0, got))
+		}
+	}
+}
+
+func TestGotoTime_JumpsToFirstEntryAtOrAfter(t *testing.T) {
+	start := time.Date(2023, 12, 23, 13, 0, 0, 0, time.UTC)
+	var lines []string
+	for i := 0; i < 120; i++ {
+		lines = append(lines, fmt.Sprintf("%s INFO: request %d", start.Add(time.Duration(i)*time.Minute).Format(quickTimeLayout), i))
+		if i == 59 {
+			lines = append(lines, "    at a stack frame without a time")
+		}
+	}
+	model := newIndexedTestModel(t, lines, 160, 30)
+	model.tailing = false
+	if _, known := model.indexer.LineTime(0); !known {
+		t.Fatal("Expected the leading timestamp recorded while indexing")
+	}
+	gotoTime := func(value string) int {
+		model.Update(keyMsg("@"))
+		for _, r := range value {
+			model.Update(keyMsg(string(r)))
+		}
+		model.Update(keyMsg("enter"))
+		line, _ := model.selectedLine()
+		return line
+	}
+
+	model.Update(keyMsg("@"))
+	if view := model.renderLogStream(); !strings.Contains(view, "Go to time:") {
+		t.Errorf("Expected the time prompt, got:\n%s", view)
+	}
+	model.Update(keyMsg("esc"))
+
+	// 14:00 is on the selected entry's day, after the line without a time
+	if line := gotoTime("14:00"); line != 61 || model.notice != "" {
+		t.Errorf("Expected line 62 at 14:00, got line %d (%q)", line+1, model.notice)
+	}
+	if line := gotoTime("2023-12-23 13:30:30"); line != 31 {
+		t.Errorf("Expected the first entry after 13:30:30, got line %d", line+1)
+	}
+	if line := gotoTime("16:00"); line != len(lines)-1 || !strings.Contains(model.notice, "showing the last line") {
+		t.Errorf("Expected the last line past the end, got line %d (%q)", line+1, model.notice)
+	}
+	if gotoTime("noon"); !strings.HasPrefix(model.notice, "Go to time: invalid time") {
+		t.Errorf("Expected an invalid time notice, got %q", model.notice)
+	}
+}
+
+func TestGotoTime_OutOfOrderChecksEveryLine(t *testing.T) {
+	model := newIndexedTestModel(t, []string{
+		"2023-12-23 14:10:00 INFO: late",
+		"2023-12-23 13:00:00 INFO: early",
+		"2023-12-23 14:00:00 INFO: on time",
+		"2023-12-23 13:30:00 INFO: early again",
+	}, 160, 30)
+	model.tailing = false
+
+	model.gotoTimeValue("2023-12-23 13:45:00")
+	if line, _ := model.selectedLine(); line != 0 || !strings.Contains(model.notice, "out of order") {
+		t.Errorf("Expected the first line at or after 13:45 in file order with a warning, got line %d (%q)", line+1, model.notice)
+	}
+}
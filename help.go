@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// keyBinding documents one shortcut for the help overlay
+type keyBinding struct {
+	context string
+	keys    string
+	help    string
+}
+
+// keyBindings lists every shortcut, grouped by where it works. It's the one
+// place they're documented in the UI, so add new keys here with their handler
+var keyBindings = []keyBinding{
+	{"Global", "h", "Show or hide this help"},
+	{"Global", "q / ctrl+c", "Quit, saving the filters"},
+	{"Global", "tab", "Switch between panels"},
+	{"Global", "/", "Edit the include filter"},
+	{"Global", "\\", "Edit the exclude filter"},
+	{"Global", "u", "Undo the last filter clear"},
+	{"Global", "f", "Collapse or show the filters panel"},
+	{"Global", "< / >", "Narrow or widen the filters panel"},
+	{"Global", "v", "Toggle the preview split"},
+	{"Global", "+ / -", "Raise or lower the minimum level"},
+	{"Global", "wheel", "Scroll the focused panel"},
+
+	{"Left panel", "j / k", "Move between items"},
+	{"Left panel", "i", "Edit the selected filter"},
+	{"Left panel", "space / enter", "Toggle the selected option"},
+	{"Left panel", "c", "Clear include and exclude"},
+	{"Left panel", "r", "Rename the source"},
+	{"Left panel", "click", "Select an item, toggling checkboxes"},
+	{"Left panel", "enter / esc", "Apply or leave a filter being edited"},
+	{"Left panel", "up / down", "Recall earlier patterns while editing"},
+
+	{"Detail view", "j / k", "Scroll the message"},
+	{"Detail view", "esc / q", "Back to the log list"},
+
+	{"Menus", "j / k", "Move or scroll"},
+	{"Menus", "enter", "Copy the chosen value"},
+	{"Menus", "esc / q", "Close"},
+
+	{"Log list", "j / k", "Move the selection"},
+	{"Log list", "ctrl+d / ctrl+u", "Half a page down or up"},
+	{"Log list", "gg / G", "First or last entry"},
+	{"Log list", "enter", "Open the detail view"},
+	{"Log list", "click / double-click", "Select or open an entry"},
+	{"Log list", "?", "Search without hiding rows"},
+	{"Log list", "n / N", "Next or previous match"},
+	{"Log list", ":", "Go to a line number"},
+	{"Log list", "@", "Go to a time, e.g. 14:00"},
+	{"Log list", "t", "Toggle tailing"},
+	{"Log list", "p / space", "Pause or resume"},
+	{"Log list", "F", "Follow matching lines"},
+	{"Log list", "] / [", "Next or previous level change"},
+	{"Log list", "m", "Toggle a bookmark"},
+	{"Log list", "b / B", "Next or previous bookmark"},
+	{"Log list", "x / dd", "Hide lines with this message"},
+	{"Log list", "X", "Show the hidden lines again"},
+	{"Log list", "r", "Re-index a rewritten file"},
+	{"Log list", "c", "Clear include and exclude"},
+	{"Log list", "V", "Start or clear a range selection"},
+	{"Log list", "E", "Export the range or time window"},
+	{"Log list", "yc", "Open the copy menu"},
+	{"Log list", "yl", "Copy the line as file:line"},
+	{"Log list", "Y", "Copy the command line"},
+	{"Log list", "J / M", "Export as JSON or Markdown"},
+	{"Log list", "a", "Rank the most frequent messages"},
+	{"Log list", "s", "Show or hide the level summary"},
+	{"Log list", "T", "Cycle the time column"},
+	{"Log list", "Z", "Cycle the display timezone"},
+	{"Log list", "C", "Show or hide the component column"},
+	{"Log list", "O", "Show or hide the duration column"},
+	{"Log list", "D", "Collapse repeated consecutive lines"},
+	{"Log list", "o", "Sort by time, duration, level or not"},
+	{"Log list", "W", "Wrap long messages"},
+	{"Log list", "R", "Show or hide redacted text"},
+}
+
+var helpKeyStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.AdaptiveColor{Light: "25", Dark: "69"})
+
+// renderHelp renders the key bindings in a box centered over the screen,
+// their groups spread over two columns
+func (m *UnifiedModel) renderHelp() string {
+	var groups []string
+	var group strings.Builder
+	for i, binding := range keyBindings {
+		if i == 0 || binding.context != keyBindings[i-1].context {
+			if group.Len() > 0 {
+				groups = append(groups, group.String())
+				group.Reset()
+			}
+			group.WriteString(lipgloss.NewStyle().Bold(true).Render(binding.context) + "\n")
+		}
+		group.WriteString(fmt.Sprintf("%s %s\n", helpKeyStyle.Render(fmt.Sprintf("%-16s", binding.keys)), binding.help))
+	}
+	groups = append(groups, group.String())
+
+	// Split the groups where the taller column is shortest
+	split, best := 0, -1
+	for i := 1; i < len(groups); i++ {
+		left := lipgloss.Height(strings.Join(groups[:i], "\n"))
+		right := lipgloss.Height(strings.Join(groups[i:], "\n"))
+		if best < 0 || max(left, right) < best {
+			split, best = i, max(left, right)
+		}
+	}
+	columns := lipgloss.JoinHorizontal(lipgloss.Top,
+		strings.Join(groups[:split], "\n"), "    ", strings.Join(groups[split:], "\n"))
+
+	box := m.focusedStyle.Padding(0, 2).Render("⌨  KEYS   (h or ESC to close)\n\n" + columns)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
@@ -1,10 +1,22 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
 	"os"
+	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync/atomic"
+	"syscall"
 	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 func TestIntegration_FileInput(t *testing.T) {
@@ -133,6 +145,44 @@ func TestIntegration_Filtering(t *testing.T) {
 	}
 }
 
+func TestIntegration_IncludeMatchAll(t *testing.T) {
+	lines := []string{
+		"2023-12-23 15:30:45 ERROR: user_id=42 timeout",
+		"2023-12-23 15:30:46 ERROR: user_id=7 timeout",
+		"2023-12-23 15:30:47 INFO: user_id=42 logged in",
+		"2023-12-23 15:30:48 INFO: healthy",
+	}
+	model := newIndexedTestModel(t, lines, 120, 40)
+	model.includeInput.SetValue("user_id=42, timeout")
+
+	// Any pattern matching is the default
+	model.applyFilters()
+	if len(model.filteredIndices) != 3 {
+		t.Errorf("Expected 3 lines matching either pattern, got %v", model.filteredIndices)
+	}
+
+	model.leftPanelItem = matchAllItem
+	model.focus = LeftPanel
+	model.Update(keyMsg(" "))
+	if !model.matchAll {
+		t.Fatal("Expected space to enable Match All")
+	}
+	if len(model.filteredIndices) != 1 || model.filteredIndices[0] != 0 {
+		t.Errorf("Expected only the line matching both patterns, got %v", model.filteredIndices)
+	}
+	if len(model.matchedIndices) != 1 {
+		t.Errorf("Expected the line counted as a match, got %v", model.matchedIndices)
+	}
+
+	// Streamed entries are filtered the same way
+	for _, line := range lines {
+		model.AddLogEntry(model.parser.ParseLogLine(line, "stdin"))
+	}
+	if len(model.filteredEntries) != 1 || !strings.Contains(model.filteredEntries[0].Message, "user_id=42 timeout") {
+		t.Errorf("Expected one streamed entry matching both patterns, got %v", model.filteredEntries)
+	}
+}
+
 func TestIntegration_LogLevelFiltering(t *testing.T) {
 	config := &Config{
 		MaxLines:    100,
@@ -236,4 +286,169 @@ func TestIntegration_RealLogFile(t *testing.T) {
 	} else {
 		t.Logf("Detected %d Rails logs with timing information", railsLogCount)
 	}
-}
\ No newline at end of file
+}
+
+func TestIntegration_SignalShutsDownCleanly(t *testing.T) {
+	// The app runs in a child process that gets a real SIGTERM, as a stuck
+	// shutdown exits the whole process
+	if statePath := os.Getenv("PANAM_SIGNAL_CHILD"); statePath != "" {
+		runSignalChild(statePath)
+		return
+	}
+	if runtime.GOOS == "windows" {
+		t.Skip("No SIGTERM on Windows")
+	}
+
+	statePath := filepath.Join(t.TempDir(), "config.yaml")
+	cmd := exec.Command(os.Args[0], "-test.run=^TestIntegration_SignalShutsDownCleanly$")
+	cmd.Env = append(os.Environ(), "PANAM_SIGNAL_CHILD="+statePath)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatalf("Failed to read the child's output: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Failed to start the child: %v", err)
+	}
+	
+	// Wait until the child handles signals itself
+	ready := make(chan bool, 1)
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			if scanner.Text() == "ready" {
+				ready <- true
+				break
+			}
+		}
+		io.Copy(io.Discard, stdout)
+	}()
+	select {
+	case <-ready:
+	case <-time.After(5 * time.Second):
+		cmd.Process.Kill()
+		t.Fatal("Child never got ready")
+	}
+	time.Sleep(100 * time.Millisecond)
+	if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		t.Fatalf("Failed to signal the child: %v", err)
+	}
+
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+	select {
+	case err := <-exited:
+		if err != nil {
+			t.Fatalf("Expected a clean exit after SIGTERM, got %v", err)
+		}
+	case <-time.After(2 * shutdownTimeout):
+		cmd.Process.Kill()
+		t.Fatal("Child did not shut down after SIGTERM")
+	}
+
+	// The graceful path saves filter state like pressing q does
+	if state := LoadConfig(statePath); state.Include != "timeout" {
+		t.Errorf("Expected filters to be saved on shutdown, got %+v", state)
+	}
+}
+
+// runSignalChild runs the app headless until a signal shuts it down
+func runSignalChild(statePath string) {
+	app := NewUnifiedApp(&Config{MaxLines: 100, RefreshRate: 1, Include: "timeout", Timezone: "UTC", StatePath: statePath})
+	app.program = tea.NewProgram(app.model, tea.WithInput(nil), tea.WithOutput(&bytes.Buffer{}), tea.WithoutSignalHandler())
+	
+	// A signal arriving before runProgram handles it must not kill the child
+	early := make(chan os.Signal, 1)
+	signal.Notify(early, syscall.SIGTERM)
+	fmt.Println("ready")
+	if err := app.runProgram(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func TestIntegration_FollowAppendAndRotate(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(testFile, []byte("2023-12-23 15:30:45 INFO: first\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	// The model is only read on the event loop, through the filter
+	var lines atomic.Int64
+	var indexing atomic.Bool
+	observe := func(model tea.Model, msg tea.Msg) tea.Msg {
+		lines.Store(int64(model.(*UnifiedModel).totalLines))
+		indexing.Store(model.(*UnifiedModel).indexing)
+		return msg
+	}
+	app := NewUnifiedApp(&Config{MaxLines: 100, Files: []string{testFile}, RefreshRate: 1, Timezone: "UTC"})
+	app.program = tea.NewProgram(app.model, tea.WithInput(nil), tea.WithOutput(&bytes.Buffer{}), tea.WithoutSignalHandler(), tea.WithFilter(observe))
+	go app.program.Run()
+	defer app.program.Kill()
+
+	app.indexFile(testFile)
+	if err := app.followFile(testFile); err != nil {
+		t.Fatalf("Failed to follow file: %v", err)
+	}
+	defer app.watcher.Close()
+
+	waitForLines := func(expected int) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			if lines.Load() == int64(expected) && !indexing.Load() {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("Expected %d lines, got %d", expected, lines.Load())
+	}
+
+	// Append
+	f, err := os.OpenFile(testFile, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open test file: %v", err)
+	}
+	f.WriteString("2023-12-23 15:30:46 INFO: second\n2023-12-23 15:30:47 INFO: third\n")
+	f.Close()
+	waitForLines(3)
+
+	// Rotate: move the file away and create a fresh one in its place
+	if err := os.Rename(testFile, testFile+".1"); err != nil {
+		t.Fatalf("Failed to rotate test file: %v", err)
+	}
+	if err := os.WriteFile(testFile, []byte("2023-12-23 15:31:00 INFO: rotated\n"), 0644); err != nil {
+		t.Fatalf("Failed to create rotated file: %v", err)
+	}
+	waitForLines(1)
+}
+
+func TestReload_ReindexesRewrittenFile(t *testing.T) {
+	model := newIndexedTestModel(t, numberedLines(20), 120, 30)
+	model.jumpToPosition(15)
+	filename := model.loadingFile
+
+	rewritten := "2023-12-23 15:30:45 WARN: rewritten 1\n2023-12-23 15:30:46 WARN: rewritten 2\n"
+	if err := os.WriteFile(filename, []byte(rewritten), 0644); err != nil {
+		t.Fatalf("Failed to rewrite test file: %v", err)
+	}
+
+	_, reindex := model.Update(keyMsg("r"))
+	if !strings.Contains(model.renderHeader(), "Re-indexing") || reindex == nil {
+		t.Fatal("Expected the header to show the file is re-indexed")
+	}
+	model.Update(reindex())
+
+	if model.totalLines != 2 || model.viewportStart != 0 || model.selectedIdx != 0 {
+		t.Fatalf("Expected the rewritten 2 lines from the top, got %d lines at %d+%d", model.totalLines, model.viewportStart, model.selectedIdx)
+	}
+	if len(model.visibleEntries) != 2 || !strings.HasSuffix(model.visibleEntries[0].Message, "rewritten 1") {
+		t.Errorf("Expected the rewritten lines, got %+v", model.visibleEntries)
+	}
+
+	// A missing file keeps what was loaded
+	os.Remove(filename)
+	model.Update(keyMsg("r"))
+	if model.indexing || model.totalLines != 2 || !strings.Contains(model.notice, "Reload") {
+		t.Errorf("Expected the loaded lines to stay with an error, got %d lines and notice %q", model.totalLines, model.notice)
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"strconv"
+	"time"
+)
+
+// Journal fields mapped onto LogEntry
+const (
+	journalFieldMessage  = "MESSAGE"
+	journalFieldPriority = "PRIORITY"
+	journalFieldUnit     = "_SYSTEMD_UNIT"
+)
+
+// journalMetadataFields are kept as metadata when present
+var journalMetadataFields = map[string]string{
+	"SYSLOG_IDENTIFIER": "identifier",
+	"_PID":              "pid",
+	"_HOSTNAME":         "hostname",
+}
+
+// parseJournalEntry maps a journal entry onto a LogEntry. PRIORITY uses the
+// syslog severities and the unit becomes the source. The cursor is kept in
+// the metadata so the next session can resume after it
+func (p *LogParser) parseJournalEntry(fields map[string]string, realtimeUsec uint64, cursor string) LogEntry {
+	entry := LogEntry{
+		Level:     INFO,
+		Message:   fields[journalFieldMessage],
+		Source:    fields[journalFieldUnit],
+		Component: fields["SYSLOG_IDENTIFIER"],
+		Raw:       fields[journalFieldMessage],
+		Metadata: map[string]interface{}{
+			"cursor": cursor,
+		},
+	}
+
+	setEntryTime(&entry, time.UnixMicro(int64(realtimeUsec)))
+
+	if priority, err := strconv.Atoi(fields[journalFieldPriority]); err == nil {
+		entry.Level = syslogSeverityToLevel(priority)
+		entry.Metadata["priority"] = priority
+	}
+	for field, key := range journalMetadataFields {
+		if value, ok := fields[field]; ok {
+			entry.Metadata[key] = value
+		}
+	}
+
+	return entry
+}
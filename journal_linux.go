@@ -0,0 +1,89 @@
+//go:build linux && cgo && journald
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/coreos/go-systemd/v22/sdjournal"
+)
+
+// journalWait bounds each wait for new journal entries while following
+const journalWait = time.Second
+
+// streamJournal reads a unit's journal into the stream buffer, starting at
+// --since, after the cursor saved by the last session, or at the oldest
+// entry, and keeps following it unless --no-follow is set
+func (a *UnifiedApp) streamJournal(unit, cursor string) error {
+	journal, err := sdjournal.NewJournal()
+	if err != nil {
+		return fmt.Errorf("opening journal: %w", err)
+	}
+	defer journal.Close()
+
+	if err := journal.AddMatch(journalFieldUnit + "=" + unit); err != nil {
+		return fmt.Errorf("matching unit %s: %w", unit, err)
+	}
+	if err := a.seekJournal(journal, cursor); err != nil {
+		return err
+	}
+
+	batch := make([]LogEntry, 0, 100)
+	for {
+		n, err := journal.Next()
+		if err != nil {
+			return fmt.Errorf("reading journal: %w", err)
+		}
+
+		// Caught up: show what we have, then wait for more
+		if n == 0 {
+			a.sendBatch(batch)
+			batch = batch[:0]
+			if a.config.NoFollow {
+				return nil
+			}
+			journal.Wait(journalWait)
+			continue
+		}
+
+		entry, err := journal.GetEntry()
+		if err != nil {
+			return fmt.Errorf("reading journal: %w", err)
+		}
+		batch = append(batch, a.model.parser.parseJournalEntry(entry.Fields, entry.RealtimeTimestamp, entry.Cursor))
+		if len(batch) >= 100 {
+			a.sendBatch(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+// seekJournal positions the journal before the first entry to read
+func (a *UnifiedApp) seekJournal(journal *sdjournal.Journal, cursor string) error {
+	if a.config.Since != "" {
+		since, err := parseTimeBound(a.config.Since, a.model.displayZone, time.Now())
+		if err != nil {
+			return err
+		}
+		return journal.SeekRealtimeUsec(uint64(since.UnixMicro()))
+	}
+
+	if cursor == "" {
+		return journal.SeekHead()
+	}
+	if err := journal.SeekCursor(cursor); err != nil {
+		return err
+	}
+
+	// The cursor's entry was shown last session. If it has been vacuumed,
+	// Next lands on a later entry, so step back to still read that one
+	if _, err := journal.Next(); err != nil {
+		return fmt.Errorf("reading journal: %w", err)
+	}
+	if journal.TestCursor(cursor) != nil {
+		_, err := journal.Previous()
+		return err
+	}
+	return nil
+}
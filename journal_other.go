@@ -0,0 +1,11 @@
+//go:build !linux || !cgo || !journald
+
+package main
+
+import "errors"
+
+// streamJournal is only available in Linux builds made with cgo and the
+// journald tag, which need the libsystemd headers
+func (a *UnifiedApp) streamJournal(unit, cursor string) error {
+	return errors.New("this build can't read the journal; rebuild on Linux with -tags journald")
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestParseJournalEntry(t *testing.T) {
+	parser := NewLogParser("UTC")
+	fields := map[string]string{
+		"MESSAGE":           "upstream timed out",
+		"PRIORITY":          "3",
+		"_SYSTEMD_UNIT":     "nginx.service",
+		"SYSLOG_IDENTIFIER": "nginx",
+		"_PID":              "812",
+	}
+
+	entry := parser.parseJournalEntry(fields, 1703345445000000, "s=abc;i=2a")
+	if entry.Level != ERROR || entry.Message != "upstream timed out" || entry.Source != "nginx.service" {
+		t.Errorf("Unexpected entry %+v", entry)
+	}
+	if entry.Timestamp != "2023-12-23T15:30:45Z" {
+		t.Errorf("Expected the realtime timestamp, got %q", entry.Timestamp)
+	}
+	if entry.Metadata["cursor"] != "s=abc;i=2a" || entry.Metadata["pid"] != "812" || entry.Metadata["identifier"] != "nginx" {
+		t.Errorf("Unexpected metadata %v", entry.Metadata)
+	}
+
+	// Entries without a priority default to INFO
+	if entry := parser.parseJournalEntry(map[string]string{"MESSAGE": "hi"}, 0, ""); entry.Level != INFO {
+		t.Errorf("Expected INFO, got %v", entry.Level)
+	}
+}
+
+func TestJournalCursor_SavedAndRestored(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "config.yaml")
+	config := &Config{MaxLines: 100, RefreshRate: 1, Timezone: "UTC", JournalUnit: "nginx.service", StatePath: statePath}
+
+	app := NewUnifiedApp(config)
+	if app.journalCursor != "" {
+		t.Fatalf("Expected no cursor on first run, got %q", app.journalCursor)
+	}
+
+	parser := NewLogParser("UTC")
+	app.model.AddLogBatch([]LogEntry{
+		parser.parseJournalEntry(map[string]string{"MESSAGE": "one"}, 0, "s=abc;i=1"),
+		parser.parseJournalEntry(map[string]string{"MESSAGE": "two"}, 0, "s=abc;i=2"),
+	})
+	app.model.quit()
+
+	// The next session resumes after the last entry read
+	if cursor := NewUnifiedApp(config).journalCursor; cursor != "s=abc;i=2" {
+		t.Errorf("Expected the saved cursor, got %q", cursor)
+	}
+}
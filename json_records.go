@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sync/atomic"
+)
+
+// recordSniffSize is how much of the file is read to detect JSON records
+const recordSniffSize = 64 * 1024
+
+// detectRecords reports whether the file holds JSON records rather than lines:
+// either one top-level array, or objects whose first line doesn't parse on
+// its own because they are pretty-printed over many lines
+func (fi *FastIndexer) detectRecords() (array bool, ok bool) {
+	buffer := make([]byte, recordSniffSize)
+	n, err := fi.file.ReadAt(buffer, 0)
+	if err != nil && err != io.EOF {
+		return false, false
+	}
+	head := bytes.TrimLeft(buffer[:n], " \t\r\n\ufeff")
+	if len(head) == 0 {
+		return false, false
+	}
+
+	switch head[0] {
+	case '[':
+		return true, true
+	case '{':
+		line, _, _ := bytes.Cut(head, []byte("\n"))
+		return false, !json.Valid(line)
+	}
+	return false, false
+}
+
+// scanRecords indexes every element of a top-level JSON array, or every
+// top-level object, as one entry spanning its original bytes. The decoder
+// holds one record at a time, so huge arrays are never loaded whole. Records
+// are always indexed densely, the memory limit doesn't apply
+func (fi *FastIndexer) scanRecords(array bool) error {
+	fi.records = true
+	fi.stride = 1
+	fi.indices = fi.indices[:0]
+	atomic.StoreInt64(&fi.bytesRead, 0)
+
+	decoder := json.NewDecoder(&chunkReader{
+		fi:     fi,
+		r:      io.NewSectionReader(fi.file, 0, math.MaxInt64),
+		cancel: fi.cancelled(),
+	})
+	if array {
+		if _, err := decoder.Token(); err != nil {
+			return fmt.Errorf("reading JSON array in %s: %w", fi.filename, err)
+		}
+	}
+
+	var count int32
+	for decoder.More() {
+		var record json.RawMessage
+		if err := decoder.Decode(&record); err != nil {
+			// A file still being written ends mid-record; keep what's complete
+			if errors.Is(err, io.ErrUnexpectedEOF) {
+				break
+			}
+			return fmt.Errorf("reading JSON record %d in %s: %w", count+1, fi.filename, err)
+		}
+
+		end := decoder.InputOffset()
+		fi.indices = append(fi.indices, FastLineIndex{
+			Offset: end - int64(len(record)),
+			Length: int32(len(record)),
+			Level:  indexLevel(levelUnknown),
+		})
+		count++
+	}
+
+	atomic.StoreInt32(&fi.totalLines, count)
+	fi.indexed = true
+
+	return nil
+}
+
+// chunkReader reads through readChunk so record scans honour Cancel and the
+// read timeout, and report their progress
+type chunkReader struct {
+	fi     *FastIndexer
+	r      io.Reader
+	cancel <-chan struct{}
+	offset int64
+}
+
+func (c *chunkReader) Read(p []byte) (int, error) {
+	n, err := c.fi.readChunk(c.r, p, c.cancel)
+	c.offset += int64(n)
+	atomic.StoreInt64(&c.fi.bytesRead, c.offset)
+	return n, err
+}
@@ -0,0 +1,45 @@
+package main
+
+import "fmt"
+
+// lineLevel returns the level of file line n, from the index when it was
+// found while indexing
+func (m *UnifiedModel) lineLevel(n int) (LogLevel, bool) {
+	if level, known := m.indexer.LineLevel(n); known {
+		return level, true
+	}
+	entries, err := m.indexer.GetLineRange(n, n+1)
+	if err != nil || len(entries) == 0 {
+		return INFO, false
+	}
+	return entries[0].Level, true
+}
+
+// jumpToLevelChange selects the nearest shown entry after (direction 1) or
+// before (-1) the selection whose level differs from the selected entry's,
+// where a run of one level ends
+func (m *UnifiedModel) jumpToLevelChange(direction int) {
+	pos := m.viewportStart + m.selectedIdx
+	if m.indexer == nil || pos < 0 || pos >= len(m.filteredIndices) {
+		return
+	}
+	current, ok := m.lineLevel(m.filteredIndices[pos])
+	if !ok {
+		return
+	}
+
+	for p := pos + direction; p >= 0 && p < len(m.filteredIndices); p += direction {
+		if level, ok := m.lineLevel(m.filteredIndices[p]); ok && level != current {
+			m.followMatches = false
+			m.jumpToPosition(p)
+			m.notice = fmt.Sprintf("%s → %s", current, level)
+			return
+		}
+	}
+
+	if direction > 0 {
+		m.notice = fmt.Sprintf("Only %s entries after this one", current)
+	} else {
+		m.notice = fmt.Sprintf("Only %s entries before this one", current)
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// levelKeyword is a word that marks a line's level when it starts the line
+type levelKeyword struct {
+	level LogLevel
+	word  string
+}
+
+// parseLevelKeywords parses a --level-keywords value such as
+// "ERROR=E|ERR,WARN=W,INFO=I,DEBUG=D"
+func parseLevelKeywords(value string) ([]levelKeyword, error) {
+	var keywords []levelKeyword
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, words, found := strings.Cut(part, "=")
+		if !found {
+			return nil, fmt.Errorf("level keywords %q: expected LEVEL=word|word", part)
+		}
+		level, ok := levelByName(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("level keywords %q: unknown level %q (ERROR, WARN, INFO or DEBUG)", part, name)
+		}
+		for _, word := range strings.Split(words, "|") {
+			if word = strings.TrimSpace(word); word == "" {
+				return nil, fmt.Errorf("level keywords %q: empty keyword", part)
+			}
+			keywords = append(keywords, levelKeyword{level: level, word: word})
+		}
+	}
+	return keywords, nil
+}
+
+// levelByName returns the level named name, ignoring case
+func levelByName(name string) (LogLevel, bool) {
+	for _, level := range []LogLevel{ERROR, WARN, INFO, DEBUG} {
+		if strings.EqualFold(name, level.String()) {
+			return level, true
+		}
+	}
+	return INFO, false
+}
+
+// keywordLevel returns the level of the configured keyword starting the
+// line, ignoring case and an opening bracket as in "[E]". The keyword must
+// end at a non-letter, so "E" doesn't take "Everything" but does take glog's
+// "E1016"
+func (p *LogParser) keywordLevel(line string) (LogLevel, bool) {
+	line = strings.TrimLeft(line, " \t[")
+	for _, keyword := range p.levelKeywords {
+		n := len(keyword.word)
+		if len(line) < n || !strings.EqualFold(line[:n], keyword.word) {
+			continue
+		}
+		if len(line) == n || !isASCIILetter(line[n]) {
+			return keyword.level, true
+		}
+	}
+	return INFO, false
+}
+
+// isASCIILetter reports whether c is an ASCII letter
+func isASCIILetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
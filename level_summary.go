@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// shownTotals returns how many of the entries passing the filters are at
+// each level
+func (m *UnifiedModel) shownTotals() levelCounts {
+	if m.indexer != nil {
+		if m.filterStats == nil {
+			return levelCounts{}
+		}
+		return m.filterStats.shown
+	}
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.streamShown
+}
+
+// renderLevelSummary charts the filtered entries per level in width columns:
+// one bar per level in its color, sized by its share of the entries and
+// followed by the count
+func (m *UnifiedModel) renderLevelSummary(width int) string {
+	counts := m.shownTotals()
+	total, widest := 0, 0
+	for _, count := range counts {
+		total += count
+		widest = max(widest, len(strconv.Itoa(count)))
+	}
+
+	// Room left after "ERROR " and " <count>"
+	barWidth := max(width-6-1-widest, 1)
+
+	var content strings.Builder
+	for _, level := range []LogLevel{ERROR, WARN, INFO, DEBUG} {
+		bar := 0
+		if total > 0 {
+			bar = counts[level] * barWidth / total
+			if bar == 0 && counts[level] > 0 {
+				bar = 1 // Rare levels still show up
+			}
+		}
+		style := m.levelStyles[level]
+		content.WriteString(fmt.Sprintf("%-5s %s %d\n", level.String(), style.Render(strings.Repeat("█", bar)), counts[level]))
+	}
+	return content.String()
+}
@@ -0,0 +1,36 @@
+package main
+
+import "fmt"
+
+// setMinLevel shows level and everything more severe, overriding the
+// individual level toggles until one of them is used again
+func (m *UnifiedModel) setMinLevel(level LogLevel) {
+	m.minLevel = LogLevel(max(int(DEBUG), min(int(level), int(ERROR))))
+	m.levelThreshold = true
+	m.showDebug = m.minLevel <= DEBUG
+	m.showInfo = m.minLevel <= INFO
+	m.showWarn = m.minLevel <= WARN
+	m.showError = true
+	m.applyFilters()
+	m.notice = fmt.Sprintf("Showing %s and above", m.minLevel)
+}
+
+// stepMinLevel raises (step 1) or lowers (-1) the minimum level. Coming from
+// the toggles, it starts at the least severe level they show
+func (m *UnifiedModel) stepMinLevel(step int) {
+	if !m.levelThreshold {
+		m.minLevel = ERROR
+		for _, level := range []LogLevel{DEBUG, INFO, WARN, ERROR} {
+			if m.shouldShowLevel(level) {
+				m.minLevel = level
+				break
+			}
+		}
+	}
+	m.setMinLevel(m.minLevel + LogLevel(step))
+}
+
+// useLevelToggles hands the level filter back to the individual toggles
+func (m *UnifiedModel) useLevelToggles() {
+	m.levelThreshold = false
+}
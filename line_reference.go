@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// FileLine returns the file and 1-based line number of line idx. It fails
+// for a tail-only index, which never counted the lines before the tail. In a
+// JSON record file the newlines before the record are counted
+func (fi *FastIndexer) FileLine(idx int) (string, int, bool) {
+	fi.indexMutex.RLock()
+	tailOnly, records := fi.tailOffset > 0, fi.records
+	var offset int64
+	if records && idx >= 0 && idx < len(fi.indices) {
+		offset = fi.indices[idx].Offset
+	}
+	fi.indexMutex.RUnlock()
+
+	if idx < 0 || idx >= fi.GetLineCount() || tailOnly {
+		return "", 0, false
+	}
+	if !records {
+		return fi.filename, idx + 1, true
+	}
+
+	fi.cacheMutex.RLock()
+	counted := fi.lineAt
+	fi.cacheMutex.RUnlock()
+	if counted.offset == offset && counted.line > 0 {
+		return fi.filename, counted.line, true
+	}
+
+	newlines, err := countNewlines(fi.file, offset)
+	if err != nil {
+		return "", 0, false
+	}
+	fi.cacheMutex.Lock()
+	fi.lineAt = lineAtOffset{offset: offset, line: newlines + 1}
+	fi.cacheMutex.Unlock()
+	return fi.filename, newlines + 1, true
+}
+
+// lineAtOffset remembers the line a record starts on, counting the lines
+// before it is a read of the whole file up to there
+type lineAtOffset struct {
+	offset int64
+	line   int
+}
+
+// countNewlines counts the newlines in the first end bytes of r
+func countNewlines(r io.ReaderAt, end int64) (int, error) {
+	buffer := make([]byte, scanBufferSize)
+	count := 0
+	for offset := int64(0); offset < end; {
+		size := len(buffer)
+		if end-offset < int64(size) {
+			size = int(end - offset)
+		}
+		n, err := r.ReadAt(buffer[:size], offset)
+		count += bytes.Count(buffer[:n], []byte{'\n'})
+		offset += int64(n)
+		if err != nil && (err != io.EOF || offset < end) {
+			return 0, err
+		}
+	}
+	return count, nil
+}
+
+// FileLine returns the file and 1-based line number merged line idx came from
+func (mi *MergedIndexer) FileLine(idx int) (string, int, bool) {
+	indexer, line, err := mi.resolve(idx)
+	if err != nil {
+		return "", 0, false
+	}
+	return indexer.FileLine(line)
+}
+
+// lineReference returns where the selected entry is, as file:line
+func (m *UnifiedModel) lineReference() (string, bool) {
+	if m.indexer == nil {
+		return "", false
+	}
+	line, ok := m.selectedLine()
+	if !ok {
+		return "", false
+	}
+	filename, number, ok := m.indexer.FileLine(line)
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprintf("%s:%d", filename, number), true
+}
+
+// copyLineReference copies the selected entry's file:line, e.g. to share it
+func (m *UnifiedModel) copyLineReference() {
+	reference, ok := m.lineReference()
+	if !ok {
+		m.notice = "No line number for this entry"
+		return
+	}
+	writeClipboard(reference)
+	m.notice = "Copied " + reference
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"unicode/utf8"
+)
+
+// defaultMaxLineBytes is where messages are cut unless --max-line-bytes says
+// otherwise, enough for any line meant to be read in the list
+const defaultMaxLineBytes = 64 * 1024
+
+// truncateEntry cuts the message and raw line of entry at limit bytes (0 = no
+// limit), on a character boundary. dropped bytes were already lost while the
+// line was read; the message ends with how many are missing
+func truncateEntry(entry *LogEntry, limit, dropped int) {
+	if limit > 0 && len(entry.Message) > limit {
+		cut := cutAt(entry.Message, limit)
+		dropped += len(entry.Message) - len(cut)
+		entry.Message = cut
+	}
+	if limit > 0 && len(entry.Raw) > limit {
+		entry.Raw = cutAt(entry.Raw, limit)
+	}
+	if dropped == 0 {
+		return
+	}
+	entry.Truncated += dropped
+	entry.Message += fmt.Sprintf("…[truncated %d bytes]", dropped)
+}
+
+// cutAt returns the first limit bytes of s, fewer if a character spans the limit
+func cutAt(s string, limit int) string {
+	for limit > 0 && !utf8.RuneStart(s[limit]) {
+		limit--
+	}
+	return s[:limit]
+}
+
+// readLongLine reads a line of any length without its line ending, keeping at
+// most limit bytes of it (0 = all) and reporting how many were dropped. Unlike
+// bufio.Scanner it never gives up on a line that's too long
+func readLongLine(r *bufio.Reader, limit int) (string, int, error) {
+	var line []byte
+	dropped := 0
+	for {
+		chunk, err := r.ReadSlice('\n')
+		keep := len(chunk)
+		if limit > 0 {
+			keep = max(0, min(keep, limit-len(line)))
+		}
+		line = append(line, chunk[:keep]...)
+		dropped += len(chunk) - keep
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+
+		// The line ending isn't part of the line, kept or not
+		if len(chunk) > 0 && chunk[len(chunk)-1] == '\n' {
+			if keep == len(chunk) {
+				line = line[:len(line)-1]
+			} else {
+				dropped--
+			}
+		}
+		if err != nil && len(chunk) > 0 {
+			err = nil
+		}
+		if n := len(line); n > 0 && line[n-1] == '\r' && dropped == 0 {
+			line = line[:n-1]
+		}
+		return string(line), dropped, err
+	}
+}
+
+// fullEntry returns entry as parsed from its whole line when its message was
+// cut and loadFullEntry has read the selected line again
+func (m *UnifiedModel) fullEntry(entry LogEntry) LogEntry {
+	line, ok := m.selectedLine()
+	if entry.Truncated == 0 || !ok {
+		return entry
+	}
+	if m.full != nil && m.full.indexer == m.indexer && m.full.line == line {
+		return m.full.entry
+	}
+	return entry
+}
+
+// loadFullEntry reads the selected line again from the file when the detail
+// view shows a cut message. The last one read is kept so it isn't read again
+// after every update
+func (m *UnifiedModel) loadFullEntry() {
+	if m.viewMode != DetailView || m.indexer == nil || m.selectedIdx < 0 || m.selectedIdx >= len(m.visibleEntries) {
+		return
+	}
+	entry := m.visibleEntries[m.selectedIdx]
+	line, ok := m.selectedLine()
+	if entry.Truncated == 0 || !ok {
+		return
+	}
+	if m.full != nil && m.full.indexer == m.indexer && m.full.line == line {
+		return
+	}
+
+	raw, err := m.indexer.RawLine(line)
+	if err != nil {
+		return
+	}
+	full := m.parser.parseFull(raw, entry.Source)
+	full.Source, full.Offset, full.Length = entry.Source, entry.Offset, entry.Length
+	m.full = &fullLine{indexer: m.indexer, line: line, entry: full}
+}
+
+// fullLine is the last line read again in full by fullEntry
+type fullLine struct {
+	indexer LineIndexer
+	line    int
+	entry   LogEntry
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// hugeJSONLine returns a JSON line of about size bytes ending in a marker
+func hugeJSONLine(size int) string {
+	return `{"level":"error","msg":"` + strings.Repeat("x", size) + ` END-OF-BLOB"}`
+}
+
+func TestLongLines_IndexedLineKeptWhole(t *testing.T) {
+	huge := hugeJSONLine(3 * 1024 * 1024)
+	path := writeTestLog(t, []string{"2023-12-23 15:30:45 INFO: before", huge, "2023-12-23 15:30:46 INFO: after"})
+
+	config := &Config{Files: []string{path}, RefreshRate: 1, Timezone: "UTC", MaxLineBytes: 64 * 1024}
+	app := NewUnifiedApp(config)
+	app.model.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	app.indexFile(path)
+	model := app.model
+
+	if model.totalLines != 3 {
+		t.Fatalf("Expected 3 lines, got %d", model.totalLines)
+	}
+	entries, err := model.indexer.GetLineRange(0, 3)
+	if err != nil || len(entries) != 3 {
+		t.Fatalf("Failed to read the lines: %v", err)
+	}
+	if !strings.HasSuffix(entries[2].Message, "after") {
+		t.Errorf("Expected the line after the blob intact, got %q", entries[2].Message)
+	}
+
+	cut := entries[1]
+	if cut.Truncated == 0 || len(cut.Message) > 64*1024+64 || !strings.Contains(cut.Message, "…[truncated") {
+		t.Fatalf("Expected the message cut at 64KB with a marker, got %d bytes", len(cut.Message))
+	}
+
+	// The detail view reads the whole line again
+	model.scrollToTop()
+	model.scrollDown()
+	model.Update(keyMsg("enter"))
+	full := model.fullEntry(cut)
+	if full.Truncated != 0 || !strings.HasSuffix(full.Message, "END-OF-BLOB") || len(full.Message) < 3*1024*1024 {
+		t.Errorf("Expected the full message in the detail view, got %d bytes", len(full.Message))
+	}
+	if !strings.Contains(model.View(), "xxxx") {
+		t.Error("Expected the detail view to show the message")
+	}
+}
+
+func TestReadLongLine(t *testing.T) {
+	input := "short\r\n" + strings.Repeat("y", 200) + "\nlast"
+	reader := bufio.NewReaderSize(strings.NewReader(input), 16)
+
+	for _, expected := range []struct {
+		line    string
+		dropped int
+	}{
+		{"short", 0},
+		{strings.Repeat("y", 50), 150},
+		{"last", 0},
+	} {
+		line, dropped, err := readLongLine(reader, 50)
+		if err != nil || line != expected.line || dropped != expected.dropped {
+			t.Errorf("Expected %q with %d dropped, got %q with %d (%v)", expected.line, expected.dropped, line, dropped, err)
+		}
+	}
+	if _, _, err := readLongLine(reader, 50); err != io.EOF {
+		t.Errorf("Expected EOF at the end, got %v", err)
+	}
+}
+
+func TestStreamFrom_KeepsReadingAfterHugeLine(t *testing.T) {
+	config := &Config{MaxLines: 100, Timezone: "UTC", MaxLineBytes: 1024}
+	app := NewUnifiedApp(config)
+	input := "INFO: first\n" + hugeJSONLine(2*1024*1024) + "\nINFO: last\n"
+	app.streamFrom(strings.NewReader(input), "stdin")
+
+	entries := app.model.stream.Drain()
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+	if entries[1].Truncated == 0 || !strings.Contains(entries[1].Message, "…[truncated") {
+		t.Errorf("Expected the huge line cut with a marker, got %d bytes", len(entries[1].Message))
+	}
+	if !strings.HasSuffix(entries[2].Message, "last") {
+		t.Errorf("Expected the line after it, got %q", entries[2].Message)
+	}
+}
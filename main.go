@@ -1,9 +1,13 @@
 package main
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -11,10 +15,47 @@ import (
 var (
 	maxLines    int
 	files       []string
-	refreshRate int
+	refreshRate float64
+	flushEvery  time.Duration
 	include     string
 	exclude     string
+	sourceFilter string
 	timezone    string
+	sourceTZ    []string
+	maxIndexMem int64
+	since       string
+	until       string
+	errorCodes  string
+	errorCodeRe string
+	noTime      bool
+	component   bool
+	wrapMarkers bool
+	redact      []string
+	noFollow    bool
+	tail        int
+	alertLevel  string
+	noBell      bool
+	maxLineBytes int
+	merge       bool
+	follow      bool
+	journalUnit string
+	filesFrom   string
+	levelWords  string
+	format      string
+	formatSample int
+	useRegex    bool
+	fuzzy       bool
+	caseSensitive bool
+	matchAll    bool
+	matchOnly   bool
+	levels      string
+	noStats     bool
+	keepColors  bool
+	printOnly   bool
+	summary     bool
+	alsoTail    []string
+	exportJSON  string
+	timePrecision string
 )
 
 var rootCmd = &cobra.Command{
@@ -28,7 +69,11 @@ Usage:
   panam                        # Read from stdin
   panam file.log               # Read single file
   panam /path/to/logs          # Read all files in directory
-  panam -e file1.log,file2.log # Read multiple files`,
+  panam -e file1.log,file2.log # Read multiple files
+  panam --follow /var/log/app  # Tail every file in a directory as one stream
+  panam --journal-unit nginx   # Read a systemd unit's journal
+  myapp | panam --also-tail app.log # Merge piped output with a tailed file
+  panam --print -i ERROR app.log # Print matching lines without the UI`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Handle positional arguments
 		if len(args) > 0 && len(files) == 0 {
@@ -48,13 +93,169 @@ Usage:
 			}
 		}
 
+		// New files in a followed directory join the live merge
+		var followDirs []string
+		if follow && len(args) > 0 {
+			if fileInfo, err := os.Stat(args[0]); err == nil && fileInfo.IsDir() {
+				followDirs = append(followDirs, args[0])
+			}
+		}
+
+		// Add the files listed in a manifest, or on stdin with "-"
+		if filesFrom != "" {
+			listed, err := readFileList(filesFrom)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			files = append(files, listed...)
+		}
+
 		config := &Config{
 			MaxLines:    maxLines,
 			Files:       files,
 			RefreshRate: refreshRate,
+			FlushInterval: flushEvery,
 			Include:     include,
 			Exclude:     exclude,
+			SourceFilter: sourceFilter,
+			UseRegex:    useRegex,
+			Fuzzy:       fuzzy,
+			CaseSensitive: caseSensitive,
+			MatchAll:    matchAll,
+			MatchOnly:   matchOnly,
 			Timezone:    timezone,
+			Since:       since,
+			Until:       until,
+			NoTime:      noTime,
+			ShowComponent: component,
+			WrapMarkers: wrapMarkers,
+			NoStats:     noStats,
+			KeepColors:  keepColors,
+			NoFollow:    noFollow,
+			Tail:        tail,
+			NoBell:      noBell,
+			Summary:     summary,
+			MaxLineBytes: maxLineBytes,
+			Merge:       merge,
+			Follow:      follow,
+			FollowDirs:  followDirs,
+			JournalUnit: journalUnit,
+			StdinFileList: filesFrom == "-",
+			AlsoTail:    alsoTail,
+			FormatSample: formatSample,
+			StatePath:   DefaultConfigPath(),
+			
+			MaxIndexMemory: maxIndexMem * 1024 * 1024,
+		}
+
+		if len(redact) > 0 {
+			redactor, err := NewRedactor(redact)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			config.Redactor = redactor
+		}
+
+		if len(sourceTZ) > 0 {
+			zone, zones, err := parseSourceTimezones(sourceTZ)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			config.SourceZone, config.SourceZones = zone, zones
+		}
+
+		if levels != "" {
+			shown, err := parseLevelList(levels)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			config.Levels = shown
+		}
+
+		if alertLevel != "" {
+			level, on, err := parseAlertLevel(alertLevel)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			config.AlertOn, config.AlertLevel = on, level
+		}
+
+		if timePrecision != "" {
+			digits, err := parseTimePrecision(timePrecision)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			config.TimePrecision = digits
+		}
+
+		if format != "" {
+			kind, err := parseParserKind(format)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			config.Format = kind
+		}
+
+		if levelWords != "" {
+			keywords, err := parseLevelKeywords(levelWords)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			config.LevelKeywords = keywords
+		}
+
+		if errorCodes != "" {
+			catalog, err := LoadErrorCatalog(errorCodes, errorCodeRe)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			config.ErrorCatalog = catalog
+		}
+
+		// Print the matching lines instead of opening the UI, exiting 1 like
+		// grep when there are none. Saved filters don't apply to scripts
+		if printOnly {
+			if journalUnit != "" {
+				fmt.Fprintf(os.Stderr, "Error: --print can't read the journal\n")
+				os.Exit(2)
+			}
+			config.StatePath = ""
+			matched, err := printMatches(config, os.Stdin, os.Stdout)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(2)
+			}
+			if matched == 0 {
+				os.Exit(1)
+			}
+			return
+		}
+
+		// Write the matching entries as JSON instead of opening the UI
+		if exportJSON != "" {
+			if journalUnit != "" {
+				fmt.Fprintf(os.Stderr, "Error: --export-json can't read the journal\n")
+				os.Exit(2)
+			}
+			config.StatePath = ""
+			exported, err := exportMatchesJSON(config, os.Stdin, exportJSON)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(2)
+			}
+			if exportJSON != "-" {
+				fmt.Printf("Exported %d entries to %s\n", exported, exportJSON)
+			}
+			return
 		}
 
 		// Use the unified fast version - single implementation
@@ -69,10 +270,47 @@ Usage:
 func init() {
 	rootCmd.Flags().IntVarP(&maxLines, "max_line", "m", 50000, "Maximum lines to keep in memory")
 	rootCmd.Flags().StringSliceVarP(&files, "files", "e", []string{}, "List of files to process")
-	rootCmd.Flags().IntVarP(&refreshRate, "refresh_rate", "r", 1, "Refresh rate in seconds")
-	rootCmd.Flags().StringVarP(&include, "include", "i", "", "Default include filter patterns (comma-separated)")
-	rootCmd.Flags().StringVarP(&exclude, "exclude", "x", "", "Default exclude filter patterns (comma-separated)")
-	rootCmd.Flags().StringVar(&timezone, "timezone", "UTC", "Display timezone for timestamps")
+	rootCmd.Flags().Float64VarP(&refreshRate, "refresh_rate", "r", 1, "Seconds between checks of unfollowed files; the screen redraws 20 times as often, within 10-500ms (0 = no checks)")
+	rootCmd.Flags().DurationVar(&flushEvery, "flush-interval", defaultFlushInterval, "Longest a streamed line waits to be sent with others before the screen shows it (at least 1ms)")
+	rootCmd.Flags().StringVarP(&include, "include", "i", "", "Default include filter patterns (comma-separated, component:name matches the component)")
+	rootCmd.Flags().StringVarP(&exclude, "exclude", "x", "", "Default exclude filter patterns (comma-separated, component:name matches the component)")
+	rootCmd.Flags().StringVar(&sourceFilter, "source-filter", "", "Only show entries whose source file or label matches (comma-separated, regex with --regex)")
+	rootCmd.Flags().BoolVar(&useRegex, "regex", false, "Treat include/exclude patterns as regular expressions")
+	rootCmd.Flags().BoolVar(&fuzzy, "fuzzy", false, "Match include/exclude patterns fuzzily: their characters in order, with anything between")
+	rootCmd.Flags().BoolVar(&caseSensitive, "case-sensitive", false, "Match include/exclude patterns case-sensitively")
+	rootCmd.Flags().BoolVar(&matchAll, "match-all", false, "Require every include pattern to match instead of any")
+	rootCmd.Flags().BoolVar(&matchOnly, "match-only", false, "Drop lines no include pattern matched, e.g. from sources a scoped pattern doesn't apply to")
+	rootCmd.Flags().StringVar(&levels, "levels", "", "Levels to show, e.g. error,warn or none (default all)")
+	rootCmd.Flags().StringVar(&timezone, "timezone", "UTC", "Display timezone for timestamps (cycle with UTC and local time using Z)")
+	rootCmd.Flags().StringSliceVar(&sourceTZ, "source-timezone", nil, "Timezone of timestamps written without an offset, for all sources or as source=zone (default UTC)")
+	rootCmd.Flags().StringVar(&format, "format", "auto", "Parse every line as this format: auto, cef, otlp, gelf, docker, json, syslog, klog, logfmt, rails or plain")
+	rootCmd.Flags().IntVar(&formatSample, "format-sample", defaultFormatSample, "Lines of each source sampled to settle its format when they all share one (0 = detect every line)")
+	rootCmd.Flags().StringVar(&levelWords, "level-keywords", "", "Words starting a plain text line that set its level, ignoring case (e.g. ERROR=E|ERR,WARN=W,INFO=I,DEBUG=D)")
+	rootCmd.Flags().StringVar(&since, "since", "", "Only show entries at or after this time (e.g. \"2023-12-23 15:30:00\" or -10m)")
+	rootCmd.Flags().StringVar(&until, "until", "", "Only show entries at or before this time (e.g. \"2023-12-23 15:45:00\" or -5m)")
+	rootCmd.Flags().BoolVar(&noFollow, "no-follow", false, "Read the file once instead of following appended lines")
+	rootCmd.Flags().StringVar(&alertLevel, "alert-on-level", "error", "Flash the header and ring the bell when an entry at this level or above arrives while tailing (none = off)")
+	rootCmd.Flags().BoolVar(&noBell, "no-bell", false, "Only flash the header for --alert-on-level, without the terminal bell")
+	rootCmd.Flags().IntVarP(&tail, "tail", "n", defaultTail, "Start on the last N lines and follow new ones, like tail -n (0 = from the first line)")
+	rootCmd.Flags().BoolVar(&merge, "merge", false, "Show multiple files, e.g. rotated logs, as one timeline ordered by timestamp")
+	rootCmd.Flags().BoolVar(&follow, "follow", false, "Tail every file live as one merged stream; new files in a directory argument join it")
+	rootCmd.Flags().StringSliceVar(&alsoTail, "also-tail", nil, "Tail this file from its end, or read this named pipe, and merge its lines with piped input by arrival, e.g. stderr kept apart from stdout (repeatable)")
+	rootCmd.Flags().StringVar(&filesFrom, "files-from", "", "Read the files to process, one path per line, from this manifest or from stdin with -")
+	rootCmd.Flags().StringVar(&journalUnit, "journal-unit", "", "Read this systemd unit's journal, resuming where the last session stopped (Linux builds with -tags journald)")
+	rootCmd.Flags().StringVar(&timePrecision, "time-precision", "s", "Fractional seconds shown in timestamps: s, ms, us or ns. Ordering always uses the full precision")
+	rootCmd.Flags().BoolVar(&noTime, "no-time", false, "Hide the TIME column (toggle with T)")
+	rootCmd.Flags().BoolVar(&component, "component", false, "Show the COMPONENT column with the logger or module name (toggle with C)")
+	rootCmd.Flags().StringVar(&exportJSON, "export-json", "", "Write the entries passing the filters to this file as a JSON array and exit, without the UI (- for stdout)")
+	rootCmd.Flags().BoolVar(&printOnly, "print", false, "Print the lines passing the filters to stdout and exit, without the UI")
+	rootCmd.Flags().BoolVar(&summary, "summary", false, "Write line, level and top message counts to stderr on exit")
+	rootCmd.Flags().BoolVar(&noStats, "no-stats", false, "Skip counting entries per level, hiding the counts by the level toggles and the s summary")
+	rootCmd.Flags().BoolVar(&keepColors, "keep-colors", false, "Show the ANSI colors of plain text lines, e.g. from cargo or pytest; filters still match the text without them")
+	rootCmd.Flags().BoolVar(&wrapMarkers, "wrap-markers", false, "Start rows that continue a wrapped message with ↳ in the list, detail and preview panels")
+	rootCmd.Flags().StringSliceVar(&redact, "redact", []string{}, "Mask matches with *** (regexes or presets: email, ipv4, jwt, creditcard)")
+	rootCmd.Flags().StringVar(&errorCodes, "error-codes", "", "JSON file mapping error codes to descriptions shown in the detail view")
+	rootCmd.Flags().StringVar(&errorCodeRe, "error-code-pattern", defaultErrorCodePattern, "Regex used to detect error codes in messages and metadata")
+	rootCmd.Flags().IntVar(&maxLineBytes, "max-line-bytes", defaultMaxLineBytes, "Cut messages longer than this many bytes in the list; the detail view still shows indexed lines in full (0 = no limit)")
+	rootCmd.Flags().Int64Var(&maxIndexMem, "max-index-memory", 1024, "Maximum line index size in MB before switching to a sparse index (0 = unlimited)")
 }
 
 func getFilesInDirectory(dir string) []string {
@@ -90,6 +328,47 @@ func getFilesInDirectory(dir string) []string {
 	return files
 }
 
+// readFileList reads newline-separated paths from a manifest, or from stdin
+// for "-". Blank lines and # comments are skipped, directories are expanded
+// and a path that doesn't exist is an error
+func readFileList(manifest string) ([]string, error) {
+	var r io.Reader = os.Stdin
+	if manifest != "-" {
+		f, err := os.Open(manifest)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var files []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		path := strings.TrimSpace(scanner.Text())
+		if path == "" || strings.HasPrefix(path, "#") {
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", manifest, err)
+		}
+		if info.IsDir() {
+			files = append(files, getFilesInDirectory(path)...)
+		} else {
+			files = append(files, path)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", manifest, err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%s lists no files", manifest)
+	}
+	return files, nil
+}
+
 func main() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Printf("Error: %v\n", err)
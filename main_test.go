@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadFileList(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.log", "b.log", "nested/c.log"} {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte("line\n"), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	manifest := filepath.Join(dir, "manifest.txt")
+	content := strings.Join([]string{
+		filepath.Join(dir, "a.log"),
+		"",
+		"# rotated logs",
+		"  " + filepath.Join(dir, "b.log") + "  ",
+		filepath.Join(dir, "nested"),
+	}, "\n")
+	if err := os.WriteFile(manifest, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create manifest: %v", err)
+	}
+
+	files, err := readFileList(manifest)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []string{filepath.Join(dir, "a.log"), filepath.Join(dir, "b.log"), filepath.Join(dir, "nested/c.log")}
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("Expected %v, got %v", expected, files)
+	}
+
+	// A missing file is reported instead of skipped
+	os.WriteFile(manifest, []byte(filepath.Join(dir, "missing.log")+"\n"), 0644)
+	if _, err := readFileList(manifest); err == nil || !strings.Contains(err.Error(), "missing.log") {
+		t.Errorf("Expected an error naming the missing file, got %v", err)
+	}
+
+	os.WriteFile(manifest, []byte("\n# nothing\n"), 0644)
+	if _, err := readFileList(manifest); err == nil {
+		t.Error("Expected an error for an empty list")
+	}
+}
@@ -0,0 +1,370 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LineIndexer is what the model reads indexed lines through: a FastIndexer
+// for one file, or a MergedIndexer for several files shown as one timeline
+type LineIndexer interface {
+	GetLineRange(start, end int) ([]LogEntry, error)
+	GetLineCount() int
+	LineCount() int
+	GetLines(start, count int) []string
+	LineLevel(idx int) (LogLevel, bool)
+	LineTime(idx int) (time.Time, bool)
+	LineSpan(idx int) (FastLineIndex, error)
+	RawLine(idx int) (string, error)
+	FileLine(idx int) (string, int, bool)
+	CopyLines(w io.Writer, first, last int) (int64, error)
+	Extend() (bool, error)
+	IndexStride() int
+	TailOffset() int64
+	Close() error
+}
+
+// mergeChunk is how many lines are read at a time to find their timestamps
+const mergeChunk = 4096
+
+// mergedLine points at a line of one of the merged files
+type mergedLine struct {
+	file int32
+	line int32
+}
+
+// MergedIndexer shows the lines of several indexed files, e.g. rotated logs,
+// as a single timeline ordered by parsed timestamp. Lines without one keep
+// their original relative order after all the sortable lines. Lines added
+// while following come after them in the order they arrive
+type MergedIndexer struct {
+	indexers []*FastIndexer // nil once a file is dropped
+	seen     []int          // Lines of each file already in order
+	order    []mergedLine
+	mutex    sync.RWMutex
+}
+
+// NewMergedIndexer orders the lines of the indexed files by timestamp. Every
+// line is parsed once for this, the entries themselves are still read and
+// cached by the file's own indexer
+func NewMergedIndexer(indexers []*FastIndexer) *MergedIndexer {
+	type stampedLine struct {
+		ref mergedLine
+		at  int64
+	}
+	var sortable []stampedLine
+	var unsortable []mergedLine
+
+	for f, indexer := range indexers {
+		count := indexer.GetLineCount()
+		for start := 0; start < count; start += mergeChunk {
+			for i, line := range indexer.GetLines(start, mergeChunk) {
+				ref := mergedLine{file: int32(f), line: int32(start + i)}
+				entry := indexer.parser.ParseLogLine(strings.TrimRight(line, "\r\n"), indexer.filename)
+				if entry.Time.IsZero() {
+					unsortable = append(unsortable, ref)
+				} else {
+					sortable = append(sortable, stampedLine{ref: ref, at: entry.Time.UnixNano()})
+				}
+			}
+		}
+	}
+
+	// Stable, so equal timestamps keep file order and then line order
+	sort.SliceStable(sortable, func(i, j int) bool {
+		return sortable[i].at < sortable[j].at
+	})
+
+	order := make([]mergedLine, 0, len(sortable)+len(unsortable))
+	for _, line := range sortable {
+		order = append(order, line.ref)
+	}
+	order = append(order, unsortable...)
+
+	seen := make([]int, len(indexers))
+	for f, indexer := range indexers {
+		seen[f] = indexer.GetLineCount()
+	}
+	return &MergedIndexer{indexers: indexers, seen: seen, order: order}
+}
+
+// Files returns how many distinct files are merged
+func (mi *MergedIndexer) Files() int {
+	mi.mutex.RLock()
+	defer mi.mutex.RUnlock()
+
+	names := make(map[string]bool)
+	for _, indexer := range mi.indexers {
+		if indexer != nil {
+			names[indexer.filename] = true
+		}
+	}
+	return len(names)
+}
+
+// latest returns the newest indexer of filename, a rotated file keeps its
+// older indexer for the lines already shown
+func (mi *MergedIndexer) latest(filename string) (int, bool) {
+	for f := len(mi.indexers) - 1; f >= 0; f-- {
+		if mi.indexers[f] != nil && filepath.Clean(mi.indexers[f].filename) == filepath.Clean(filename) {
+			return f, true
+		}
+	}
+	return 0, false
+}
+
+// Has reports whether filename is merged
+func (mi *MergedIndexer) Has(filename string) bool {
+	mi.mutex.RLock()
+	defer mi.mutex.RUnlock()
+
+	_, ok := mi.latest(filename)
+	return ok
+}
+
+// Add merges another indexed file, e.g. one that appeared while following.
+// Its lines come after the lines already merged
+func (mi *MergedIndexer) Add(indexer *FastIndexer) {
+	mi.mutex.Lock()
+	defer mi.mutex.Unlock()
+
+	mi.indexers = append(mi.indexers, indexer)
+	mi.seen = append(mi.seen, 0)
+	mi.appendNew(len(mi.indexers) - 1)
+}
+
+// ExtendFile indexes the lines appended to filename and adds them to the
+// end of the timeline. It reports false when the file isn't merged or shrank
+func (mi *MergedIndexer) ExtendFile(filename string) (bool, error) {
+	mi.mutex.Lock()
+	defer mi.mutex.Unlock()
+
+	f, ok := mi.latest(filename)
+	if !ok {
+		return false, nil
+	}
+	grown, err := mi.indexers[f].Extend()
+	if err != nil || !grown {
+		return false, err
+	}
+	mi.appendNew(f)
+	return true, nil
+}
+
+// Drop removes the lines of filename, e.g. after it was truncated and they
+// can't be read anymore
+func (mi *MergedIndexer) Drop(filename string) {
+	mi.mutex.Lock()
+	defer mi.mutex.Unlock()
+
+	f, ok := mi.latest(filename)
+	if !ok {
+		return
+	}
+	order := mi.order[:0]
+	for _, ref := range mi.order {
+		if int(ref.file) != f {
+			order = append(order, ref)
+		}
+	}
+	mi.order = order
+	mi.indexers[f].Close()
+	mi.indexers[f] = nil
+}
+
+// appendNew adds the lines of file f indexed since it was last looked at
+func (mi *MergedIndexer) appendNew(f int) {
+	count := mi.indexers[f].GetLineCount()
+	for line := mi.seen[f]; line < count; line++ {
+		mi.order = append(mi.order, mergedLine{file: int32(f), line: int32(line)})
+	}
+	mi.seen[f] = max(mi.seen[f], count)
+}
+
+// resolve returns the indexer and file line of merged line idx
+func (mi *MergedIndexer) resolve(idx int) (*FastIndexer, int, error) {
+	mi.mutex.RLock()
+	defer mi.mutex.RUnlock()
+
+	if idx < 0 || idx >= len(mi.order) {
+		return nil, 0, io.EOF
+	}
+	ref := mi.order[idx]
+	return mi.indexers[ref.file], int(ref.line), nil
+}
+
+// GetLineRange retrieves merged lines start through end-1
+func (mi *MergedIndexer) GetLineRange(start, end int) ([]LogEntry, error) {
+	start = max(start, 0)
+	end = min(end, mi.GetLineCount())
+	if start >= end {
+		return []LogEntry{}, nil
+	}
+
+	entries := make([]LogEntry, 0, end-start)
+	for i := start; i < end; i++ {
+		indexer, line, err := mi.resolve(i)
+		if err != nil {
+			return entries, err
+		}
+		read, err := indexer.GetLineRange(line, line+1)
+		if err != nil {
+			return entries, err
+		}
+		entries = append(entries, read...)
+	}
+	return entries, nil
+}
+
+// GetLineCount returns the total lines of all merged files
+func (mi *MergedIndexer) GetLineCount() int {
+	mi.mutex.RLock()
+	defer mi.mutex.RUnlock()
+	return len(mi.order)
+}
+
+// LineCount returns the total lines (alias for GetLineCount)
+func (mi *MergedIndexer) LineCount() int {
+	return mi.GetLineCount()
+}
+
+// GetLines returns raw lines for the given merged range
+func (mi *MergedIndexer) GetLines(start, count int) []string {
+	end := min(start+count, mi.GetLineCount())
+	lines := make([]string, 0, max(end-start, 0))
+	for i := max(start, 0); i < end; i++ {
+		if indexer, line, err := mi.resolve(i); err == nil {
+			lines = append(lines, indexer.GetLines(line, 1)...)
+		}
+	}
+	return lines
+}
+
+// LineLevel returns the level the line's file recorded while indexing
+func (mi *MergedIndexer) LineLevel(idx int) (LogLevel, bool) {
+	indexer, line, err := mi.resolve(idx)
+	if err != nil {
+		return levelUnknown, false
+	}
+	return indexer.LineLevel(line)
+}
+
+// LineTime returns the timestamp the line's file recorded while indexing
+func (mi *MergedIndexer) LineTime(idx int) (time.Time, bool) {
+	indexer, line, err := mi.resolve(idx)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return indexer.LineTime(line)
+}
+
+// LineSpan returns where merged line idx lies in its own file
+func (mi *MergedIndexer) LineSpan(idx int) (FastLineIndex, error) {
+	indexer, line, err := mi.resolve(idx)
+	if err != nil {
+		return FastLineIndex{}, err
+	}
+	return indexer.LineSpan(line)
+}
+
+// RawLine reads merged line idx from its own file, whatever its length
+func (mi *MergedIndexer) RawLine(idx int) (string, error) {
+	indexer, line, err := mi.resolve(idx)
+	if err != nil {
+		return "", err
+	}
+	return indexer.RawLine(line)
+}
+
+// CopyLines writes the original bytes of merged lines first through last to
+// w in timeline order, each line straight from its own file
+func (mi *MergedIndexer) CopyLines(w io.Writer, first, last int) (int64, error) {
+	if first > last {
+		first, last = last, first
+	}
+	var written int64
+	for i := first; i <= last; i++ {
+		indexer, line, err := mi.resolve(i)
+		if err != nil {
+			return written, fmt.Errorf("line %d: %w", i, err)
+		}
+		n, err := indexer.CopyLines(w, line, line)
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// Extend indexes the lines appended to every merged file and adds them to
+// the end of the timeline. It reports false when a file shrank
+func (mi *MergedIndexer) Extend() (bool, error) {
+	mi.mutex.Lock()
+	defer mi.mutex.Unlock()
+
+	for f, indexer := range mi.indexers {
+		if indexer == nil {
+			continue
+		}
+		grown, err := indexer.Extend()
+		if err != nil || !grown {
+			return false, err
+		}
+		mi.appendNew(f)
+	}
+	return true, nil
+}
+
+// IndexStride returns the coarsest stride of the merged files
+func (mi *MergedIndexer) IndexStride() int {
+	mi.mutex.RLock()
+	defer mi.mutex.RUnlock()
+
+	stride := 1
+	for _, indexer := range mi.indexers {
+		if indexer != nil {
+			stride = max(stride, indexer.IndexStride())
+		}
+	}
+	return stride
+}
+
+// TailOffset reports whether any merged file was only indexed from its end
+func (mi *MergedIndexer) TailOffset() int64 {
+	mi.mutex.RLock()
+	defer mi.mutex.RUnlock()
+
+	var offset int64
+	for _, indexer := range mi.indexers {
+		if indexer == nil {
+			continue
+		}
+		if tail := indexer.TailOffset(); tail > offset {
+			offset = tail
+		}
+	}
+	return offset
+}
+
+// Close releases every merged file
+func (mi *MergedIndexer) Close() error {
+	mi.mutex.Lock()
+	defer mi.mutex.Unlock()
+
+	var err error
+	for _, indexer := range mi.indexers {
+		if indexer == nil {
+			continue
+		}
+		if closeErr := indexer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func newMergedTestIndexer(t *testing.T, files ...[]string) *MergedIndexer {
+	t.Helper()
+
+	parser := NewLogParser("UTC")
+	var indexers []*FastIndexer
+	for _, lines := range files {
+		indexer, err := NewFastIndexer(writeTestLog(t, lines), parser)
+		if err != nil {
+			t.Fatalf("Failed to create indexer: %v", err)
+		}
+		if err := indexer.IndexFileUltraFast(); err != nil {
+			t.Fatalf("Failed to index: %v", err)
+		}
+		indexers = append(indexers, indexer)
+	}
+	merged := NewMergedIndexer(indexers)
+	t.Cleanup(func() { merged.Close() })
+	return merged
+}
+
+func TestMergedIndexer_OrdersWithinTheSameSecond(t *testing.T) {
+	merged := newMergedTestIndexer(t,
+		[]string{
+			"2023-12-23 15:30:45.250 INFO: api second",
+			"2023-12-23 15:30:45,900 INFO: api fourth",
+		},
+		[]string{
+			`{"time":"2023-12-23T15:30:45.100000001Z","level":"info","msg":"worker first"}`,
+			`{"time":"2023-12-23T15:30:45.700Z","level":"info","msg":"worker third"}`,
+		},
+	)
+
+	entries, err := merged.GetLineRange(0, 4)
+	if err != nil {
+		t.Fatalf("Failed to read merged lines: %v", err)
+	}
+	expected := []string{"worker first", "api second", "worker third", "api fourth"}
+	for i, entry := range entries {
+		if !strings.Contains(entry.Message, expected[i]) {
+			t.Errorf("Line %d: expected %q, got %q", i, expected[i], entry.Message)
+		}
+	}
+
+	model := NewUnifiedModel(&Config{Timezone: "UTC", TimePrecision: 3})
+	if shown := model.displayTimestamp(entries[0]); shown != "2023-12-23T15:30:45.100Z" {
+		t.Errorf("Expected milliseconds shown, got %s", shown)
+	}
+	model.config.TimePrecision = 9
+	if shown := model.displayTimestamp(entries[0]); shown != "2023-12-23T15:30:45.100000001Z" {
+		t.Errorf("Expected nanoseconds shown, got %s", shown)
+	}
+	model.config.TimePrecision = 0
+	if shown := model.displayTimestamp(entries[0]); shown != "2023-12-23T15:30:45Z" {
+		t.Errorf("Expected whole seconds by default, got %s", shown)
+	}
+}
+
+func TestMergedIndexer_InterleavesByTimestamp(t *testing.T) {
+	merged := newMergedTestIndexer(t,
+		[]string{
+			"2023-12-23 15:30:02 INFO: current b",
+			"continuation without time",
+			"2023-12-23 15:30:04 ERROR: current d",
+		},
+		[]string{
+			"2023-12-23 15:30:01 INFO: rotated a",
+			"2023-12-23 15:30:03 WARN: rotated c",
+			"no time either",
+		},
+	)
+
+	if merged.GetLineCount() != 6 {
+		t.Fatalf("Expected 6 merged lines, got %d", merged.GetLineCount())
+	}
+	entries, err := merged.GetLineRange(0, 6)
+	if err != nil {
+		t.Fatalf("Failed to read merged lines: %v", err)
+	}
+
+	expected := []string{"rotated a", "current b", "rotated c", "current d", "continuation without time", "no time either"}
+	for i, entry := range entries {
+		if !strings.Contains(entry.Message, expected[i]) {
+			t.Errorf("Line %d: expected %q, got %q", i, expected[i], entry.Message)
+		}
+	}
+
+	// Levels and spans come from the line's own file
+	if level, ok := merged.LineLevel(3); !ok || level != ERROR {
+		t.Errorf("Expected ERROR for line 3, got %v (%v)", level, ok)
+	}
+	if span, err := merged.LineSpan(1); err != nil || span.Offset != 0 {
+		t.Errorf("Expected line 1 to start its file, got %+v (%v)", span, err)
+	}
+
+	var copied strings.Builder
+	if _, err := merged.CopyLines(&copied, 0, 1); err != nil {
+		t.Fatalf("Failed to copy lines: %v", err)
+	}
+	if copied.String() != "2023-12-23 15:30:01 INFO: rotated a\n2023-12-23 15:30:02 INFO: current b\n" {
+		t.Errorf("Expected lines copied in timeline order, got %q", copied.String())
+	}
+}
+
+func TestMergedIndexer_ShownByModel(t *testing.T) {
+	merged := newMergedTestIndexer(t,
+		[]string{"2023-12-23 15:30:02 INFO: second"},
+		[]string{"2023-12-23 15:30:01 INFO: first"},
+	)
+
+	model := NewUnifiedModel(&Config{Timezone: "UTC", RefreshRate: 1, Merge: true})
+	model.SetIndexer(merged, "app.log")
+	model.loadVisibleLines()
+
+	if len(model.visibleEntries) != 2 || !strings.Contains(model.visibleEntries[0].Message, "first") {
+		t.Fatalf("Expected the merged timeline, got %v", model.visibleEntries)
+	}
+	if header := model.renderHeader(); !strings.Contains(header, "2 files merged") {
+		t.Errorf("Expected the header to mention the merge, got %q", header)
+	}
+}
+
+func TestMergedIndexer_FollowsDirectoryLive(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string, flag int) string {
+		path := filepath.Join(dir, name)
+		f, err := os.OpenFile(path, flag|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		defer f.Close()
+		f.WriteString(content)
+		return path
+	}
+	api := write("api.log", "2023-12-23 15:30:01 INFO: api started\n", os.O_TRUNC)
+	write("db.log", "2023-12-23 15:30:00 INFO: db started\n", os.O_TRUNC)
+
+	app := NewUnifiedApp(&Config{MaxLines: 100, Files: getFilesInDirectory(dir), RefreshRate: 1, Timezone: "UTC",
+		Follow: true, FollowDirs: []string{dir}})
+	app.mergeFiles(app.config.Files)
+	model := app.model
+	model.Update(tea.WindowSizeMsg{Width: 160, Height: 30})
+	defer model.indexer.Close()
+
+	messages := func() []string {
+		model.loadVisibleLines()
+		var messages []string
+		for _, entry := range model.visibleEntries {
+			messages = append(messages, entry.Message)
+		}
+		return messages
+	}
+	if got := messages(); len(got) != 2 || !strings.Contains(got[0], "db started") {
+		t.Fatalf("Expected the files merged by time, got %v", got)
+	}
+
+	// Appended lines come last, in the order they arrive
+	write("api.log", "2023-12-23 15:29:00 ERROR: api late line\n", os.O_APPEND)
+	model.Update(fileChangedMsg{filename: api})
+	if got := messages(); len(got) != 3 || !strings.Contains(got[2], "api late line") {
+		t.Fatalf("Expected the appended line at the end, got %v", got)
+	}
+
+	// New files in the directory join once indexed off the event loop,
+	// rotated names don't
+	worker := write("worker.log", "2023-12-23 15:30:05 WARN: worker started\n", os.O_TRUNC)
+	_, index := model.Update(fileChangedMsg{filename: worker, replaced: true})
+	if got := messages(); len(got) != 3 || index == nil {
+		t.Fatalf("Expected the new file indexed by a command, got %v", got)
+	}
+	model.Update(index())
+	rotated := write("api.log.1", "2023-12-23 15:30:01 INFO: api started\n", os.O_TRUNC)
+	if _, index := model.Update(fileChangedMsg{filename: rotated, replaced: true}); index != nil {
+		t.Error("Expected the rotated file not to be indexed")
+	}
+	if got := messages(); len(got) != 4 || !strings.Contains(got[3], "worker started") {
+		t.Fatalf("Expected only the new file to join, got %v", got)
+	}
+
+	// Rows name their source in its color
+	if view := model.renderLogStream(); !strings.Contains(view, "SOURCE") || !strings.Contains(view, "worker.log") {
+		t.Errorf("Expected a SOURCE column, got:\n%s", view)
+	}
+
+	// A file truncated in place is read again from its start
+	write("api.log", "2023-12-23 15:31:00 INFO: api restarted\n", os.O_TRUNC)
+	if _, index := model.Update(fileChangedMsg{filename: api}); index != nil {
+		model.Update(index())
+	}
+	got := messages()
+	if len(got) != 3 || !strings.Contains(got[2], "api restarted") {
+		t.Errorf("Expected the truncated file's old lines replaced, got %v", got)
+	}
+}
+
+func TestMergedIndexer_FileLine(t *testing.T) {
+	merged := newMergedTestIndexer(t,
+		[]string{"2023-12-23 15:30:45 INFO: a1", "2023-12-23 15:30:47 INFO: a2"},
+		[]string{"2023-12-23 15:30:46 INFO: b1"},
+	)
+
+	// Merged line 1 is the first line of the second file
+	filename, line, ok := merged.FileLine(1)
+	if !ok || line != 1 || filename != merged.indexers[1].filename {
+		t.Errorf("Expected line 1 of the second file, got %s:%d", filename, line)
+	}
+	if _, line, _ := merged.FileLine(2); line != 2 {
+		t.Errorf("Expected line 2 of the first file, got %d", line)
+	}
+	if _, _, ok := merged.FileLine(3); ok {
+		t.Error("Expected no reference past the last line")
+	}
+}
+
+func TestMergedIndexer_SourceFilterIsolatesAFile(t *testing.T) {
+	merged := newMergedTestIndexer(t,
+		[]string{"2023-12-23 15:30:01 INFO: api one", "2023-12-23 15:30:03 INFO: api two"},
+		[]string{"2023-12-23 15:30:02 INFO: worker one"},
+	)
+
+	model := NewUnifiedModel(&Config{Timezone: "UTC", RefreshRate: 1, Merge: true})
+	model.SetIndexer(merged, "app.log")
+	model.sourceInput.SetValue(merged.indexers[1].filename)
+	model.applyFilters()
+
+	if len(model.filteredIndices) != 1 || !strings.Contains(model.visibleEntries[0].Message, "worker one") {
+		t.Fatalf("Expected only the worker line, got %v", model.visibleEntries)
+	}
+	if header := model.renderHeader(); !strings.Contains(header, "Source: ") {
+		t.Errorf("Expected the header to show the source filter, got %q", header)
+	}
+
+	model.sourceInput.SetValue("missing")
+	model.applyFilters()
+	if view := model.renderLogStream(); !strings.Contains(view, "0 results: source 'missing' matched none of 3 entries") {
+		t.Errorf("Expected the empty list to be explained, got:\n%s", view)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// noMessage stands in for an entry with neither a message nor metadata
+const noMessage = "<no message>"
+
+var noMessageStyle = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "244", Dark: "8"})
+
+// metadataKeyStyle colors metadata keys in the detail view
+var metadataKeyStyle = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "25", Dark: "69"})
+
+// fitColumn cuts s to width terminal cells, marking the cut with ~, and pads
+// it to width. Cells are counted per grapheme, so wide CJK characters take
+// two and combining marks none, keeping columns aligned in any script
+func fitColumn(s string, width int) string {
+	s = ansi.Truncate(s, width, "~")
+	return s + strings.Repeat(" ", max(0, width-ansi.StringWidth(s)))
+}
+
+// displayMessage returns the redacted message shown in list rows. Entries
+// with an empty message, common in OTLP logs keeping everything in
+// attributes, show their metadata as key=value pairs instead
+func (m *UnifiedModel) displayMessage(entry LogEntry) string {
+	if strings.TrimSpace(entry.Message) != "" {
+		return m.redact(entry.Message)
+	}
+	if summary := metadataSummary(entry.Metadata); summary != "" {
+		return m.redact(summary)
+	}
+	return noMessage
+}
+
+// ansiReset ends any color left open by a colored message
+const ansiReset = "\x1b[0m"
+
+// coloredMessage returns the message with the colors of its source line,
+// with --keep-colors. Only plain text lines whose message is the whole line
+// qualify, and only when redaction leaves them alone, since the escapes
+// could split a match. Other escapes, like cursor moves, are dropped
+func (m *UnifiedModel) coloredMessage(entry LogEntry) (string, bool) {
+	if !m.config.KeepColors || strings.IndexByte(entry.Raw, 0x1b) < 0 {
+		return "", false
+	}
+	colored := ansiRegex.ReplaceAllStringFunc(strings.TrimSuffix(entry.Raw, "\r"), func(escape string) string {
+		if strings.HasSuffix(escape, "m") {
+			return escape
+		}
+		return ""
+	})
+	if StripANSI(colored) != entry.Message || m.redact(entry.Message) != entry.Message {
+		return "", false
+	}
+	return colored, true
+}
+
+// metadataSummary joins metadata into key=value pairs, OTLP attributes
+// first and without their prefix, then the other keys sorted. Nested
+// objects are flattened to dotted keys
+func metadataSummary(metadata map[string]interface{}) string {
+	var pairs []string
+	if attributes, ok := metadata["attributes"].(map[string]interface{}); ok {
+		pairs = appendFieldPairs(pairs, "", attributes)
+	}
+	rest := make(map[string]interface{}, len(metadata))
+	for key, value := range metadata {
+		if key != "attributes" {
+			rest[key] = value
+		}
+	}
+	return strings.Join(appendFieldPairs(pairs, "", rest), " ")
+}
+
+// appendFieldPairs appends the fields as key=value pairs in key order
+func appendFieldPairs(pairs []string, prefix string, fields map[string]interface{}) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if nested, ok := fields[key].(map[string]interface{}); ok {
+			pairs = appendFieldPairs(pairs, prefix+key+".", nested)
+			continue
+		}
+		pairs = append(pairs, prefix+key+"="+quoteValue(fmt.Sprint(fields[key])))
+	}
+	return pairs
+}
@@ -0,0 +1,39 @@
+package main
+
+import "strings"
+
+// metadataPredicate parses a "has:key" or "!has:key" pattern into the key
+// path and whether the key must be present
+func metadataPredicate(pattern string) (key string, present, ok bool) {
+	if key, found := strings.CutPrefix(pattern, "!has:"); found && key != "" {
+		return key, false, true
+	}
+	if key, found := strings.CutPrefix(pattern, "has:"); found && key != "" {
+		return key, true, true
+	}
+	return "", false, false
+}
+
+// hasMetadata reports whether the entry carries the key, either as a flat
+// key like "trace.id" or as a path through nested objects
+func hasMetadata(entry LogEntry, key string) bool {
+	if _, ok := entry.Metadata[key]; ok {
+		return true
+	}
+
+	fields := entry.Metadata
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		value, ok := fields[part]
+		if !ok {
+			return false
+		}
+		if i == len(parts)-1 {
+			return true
+		}
+		if fields, ok = value.(map[string]interface{}); !ok {
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// listHeaderRows are the rows of the log stream above its first entry: the
+// border, the title, the position and the column headings with their rule
+const listHeaderRows = 5
+
+// updateMouse scrolls the focused panel with the wheel and selects what's
+// clicked. Prompts and inputs being edited keep the keyboard to themselves
+func (m *UnifiedModel) updateMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.showHelp || m.editMode || m.searching || m.gotoOpen || msg.Action != tea.MouseActionPress {
+		return m, nil
+	}
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		return m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	case tea.MouseButtonWheelDown:
+		return m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	case tea.MouseButtonLeft:
+		if m.viewMode != LogStreamView {
+			return m, nil
+		}
+		y := msg.Y - lipgloss.Height(m.renderHeader())
+		if !m.fullscreen && msg.X < m.leftWidth+2 {
+			m.clickLeftPanel(y)
+		} else {
+			m.clickRightPanel(y)
+		}
+	}
+	return m, nil
+}
+
+// clickLeftPanel selects the left panel item on row y of the panels and
+// toggles it if it's a checkbox
+func (m *UnifiedModel) clickLeftPanel(y int) {
+	line := y - 1 + m.leftScrollOffset
+	for item, itemLine := range m.leftItemLines {
+		// Pattern items show their value on the line below the label
+		twoLines := item == includeItem || item == excludeItem || item == sourceFilterItem
+		if line != itemLine && !(twoLines && line == itemLine+1) {
+			continue
+		}
+
+		m.focus = LeftPanel
+		m.leftPanelItem = item
+		switch item {
+		case regexItem, fuzzyItem, caseItem, matchAllItem, matchOnlyItem, rowColorItem, errorItem, warnItem, infoItem, debugItem, liveItem:
+			m.updateLeftPanel(tea.KeyMsg{Type: tea.KeyEnter})
+		}
+		return
+	}
+}
+
+// clickRightPanel selects the entry on row y of the panels, or focuses the
+// preview when it's below the list. A second click on the same entry within
+// half a second opens it in the detail view, like enter
+func (m *UnifiedModel) clickRightPanel(y int) {
+	if m.isSplit() && y >= m.height-2-m.previewHeight() {
+		m.focus = PreviewPanel
+		return
+	}
+	m.focus = RightPanel
+
+	row := y - listHeaderRows
+	if row < 0 || row >= m.viewportHeight {
+		return
+	}
+	m.mutex.RLock()
+	clicked := -1
+	for i, entry := range m.visibleEntries {
+		row -= m.entryRows(entry)
+		if row < 0 {
+			clicked = i
+			break
+		}
+	}
+	m.mutex.RUnlock()
+	if clicked < 0 {
+		return
+	}
+
+	now := time.Now().UnixNano()
+	position := m.viewportStart + clicked
+	if position == m.viewportStart+m.selectedIdx && now-m.lastClick < 500000000 {
+		m.viewMode = DetailView
+		m.scrollOffset = 0
+		m.lastClick = 0
+		return
+	}
+	if clicked != m.selectedIdx {
+		m.previewScroll = 0
+	}
+	m.tailing = false // Auto-pause tailing when navigating
+	m.selectedIdx = clicked
+	m.lastClick = now
+}
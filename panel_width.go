@@ -0,0 +1,56 @@
+package main
+
+import "fmt"
+
+const (
+	minLeftWidth  = 20 // Narrowest filters panel that still fits its labels
+	maxLeftWidth  = 80
+	minRightWidth = 40 // Room the log stream keeps when the filters panel grows
+	leftWidthStep = 4  // Columns < and > move the panel edge by
+)
+
+// layoutPanels splits the terminal width between the panels: the filters
+// panel takes the width chosen with < and >, or 30% of the terminal within
+// 25-40 columns until one is chosen, and the log stream the rest. Collapsed
+// with f, the log stream takes the full width
+func (m *UnifiedModel) layoutPanels() {
+	if m.fullscreen {
+		m.rightWidth = m.width
+		return
+	}
+
+	width := m.leftPref
+	if width == 0 {
+		width = min(max(m.width*30/100, 25), 40)
+	}
+	m.leftWidth = m.clampLeftWidth(width)
+	m.rightWidth = max(0, m.width-m.leftWidth)
+}
+
+// clampLeftWidth keeps a filters panel width within bounds, leaving the log
+// stream minRightWidth unless the terminal is too narrow for both
+func (m *UnifiedModel) clampLeftWidth(width int) int {
+	return max(minLeftWidth, min(min(width, maxLeftWidth), m.width-minRightWidth))
+}
+
+// resizeLeftPanel moves the edge between the panels by step columns, showing
+// the filters panel again if it was collapsed
+func (m *UnifiedModel) resizeLeftPanel(step int) {
+	if m.fullscreen {
+		m.fullscreen = false
+	} else {
+		m.leftPref = m.clampLeftWidth(m.leftWidth + step)
+	}
+	m.layoutPanels()
+	m.notice = fmt.Sprintf("Filters panel: %d columns", m.leftWidth)
+}
+
+// toggleLeftPanel collapses the filters panel, giving the log stream the
+// full width, or shows it again at its chosen width
+func (m *UnifiedModel) toggleLeftPanel() {
+	m.fullscreen = !m.fullscreen
+	if m.fullscreen {
+		m.focus = RightPanel
+	}
+	m.layoutPanels()
+}
@@ -20,57 +20,133 @@ type OTLPLog struct {
 	Attributes        map[string]interface{} `json:"attributes"`
 	Resource          map[string]interface{} `json:"resource"`
 	InstrumentationScope map[string]interface{} `json:"instrumentationScope"`
+	
+	// Logger names of structured loggers that aren't OTLP (zap, logrus, python-json-logger)
+	Component         string                 `json:"component"`
+	Logger            string                 `json:"logger"`
+	LoggerName        string                 `json:"logger_name"`
 }
 
+// LogParser turns lines into entries. Apart from the format cache, which has
+// its own lock, it is never modified after it's built, so one parser is
+// shared by every source and the indexing goroutines
 type LogParser struct {
-	timezone *time.Location
+	// Zone of timestamps written without an offset, overridden per source
+	sourceZone  *time.Location
+	sourceZones map[string]*time.Location
+	
+	// Keywords starting a plain text line that set its level (nil = built-in detection only)
+	levelKeywords []levelKeyword
+	
+	// Parser forced with --format, and the format settled per source when
+	// it's ParserAuto (nil = every parser is tried for every line)
+	format  ParserKind
+	formats *formatCache
+	
+	// Parsers added with Register, tried before every built-in one
+	custom []customParser
+	
+	// Messages longer than this are cut, see --max-line-bytes (0 = no limit)
+	maxLineBytes int
+	
 	// Pre-compiled regex patterns for performance
 	railsRegex    *regexp.Regexp
 	commonLogRegex *regexp.Regexp
+	syslog5424Regex *regexp.Regexp
+	syslog3164Regex *regexp.Regexp
+	klogRegex     *regexp.Regexp
+	goCallerRegex *regexp.Regexp
 	timestampRegexes []*regexp.Regexp
 }
 
-func NewLogParser(timezone string) *LogParser {
-	loc, err := time.LoadLocation(timezone)
+// NewLogParser returns a parser that reads timestamps without an offset in
+// sourceTimezone (UTC if it's unknown)
+func NewLogParser(sourceTimezone string) *LogParser {
+	loc, err := time.LoadLocation(sourceTimezone)
 	if err != nil {
 		loc = time.UTC
 	}
+	return newLogParser(loc, nil)
+}
+
+// newLogParser returns a parser reading timestamps without an offset in
+// zones[source] for the sources listed, and in zone for all others
+func newLogParser(zone *time.Location, zones map[string]*time.Location) *LogParser {
+	if zone == nil {
+		zone = time.UTC
+	}
 	
 	// Pre-compile regex patterns for better performance
 	railsRegex := regexp.MustCompile(`^\s*\(([0-9.]+)ms\)\s+(.+)$`)
 	commonLogRegex := regexp.MustCompile(`^(\S+) - - \[([^\]]+)\] "([^"]*)" (\d+) (\d+)`)
-	
+	// <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [SD] [MSG]
+	syslog5424Regex := regexp.MustCompile(`^<(\d{1,3})>(\d{1,2}) (\S+) (\S+) (\S+) (\S+) (\S+) ?(.*)$`)
+	// <PRI>Mmm dd HH:MM:SS HOSTNAME [TAG[PID]: ]MSG
+	syslog3164Regex := regexp.MustCompile(`^<(\d{1,3})>([A-Z][a-z]{2} [ \d]\d \d{2}:\d{2}:\d{2}(?:\.\d+)?) (\S+) (?:([^\s\[\]:]+)(?:\[(\d+)\])?: )?(.*)$`)
+	// Lmmdd hh:mm:ss.uuuuuu threadid file:line] msg, the thread id padded with spaces
+	klogRegex := regexp.MustCompile(`^([IWEFD])(\d{4} \d{2}:\d{2}:\d{2}(?:\.\d+)?) +(\d+) ([^\s:\]]+:\d+)\] ?(.*)$`)
+	// Go's log package with Lshortfile or Llongfile: "2009/11/10 23:00:00 main.go:42: message",
+	// optionally after a SetPrefix word or without the date and time flags
+	goCallerRegex := regexp.MustCompile(`^((?:\S+ )?\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}(?:\.\d+)? )?(\S+\.go:\d+): `)
+
 	// Pre-compile timestamp patterns
 	timestampRegexes := []*regexp.Regexp{
-		regexp.MustCompile(`(\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2})`),                    // 2023-01-01 12:00:00
+		regexp.MustCompile(`(\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}(?:[.,]\d+)?)`),        // 2023-01-01 12:00:00, optionally .123
 		regexp.MustCompile(`(\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2})`),                   // 01/Jan/2023:12:00:00
-		regexp.MustCompile(`(\w{3} \d{1,2} \d{2}:\d{2}:\d{2})`),                       // Jan 1 12:00:00
+		regexp.MustCompile(`(\w{3} \d{1,2} \d{2}:\d{2}:\d{2}(?:[.,]\d+)?)`),           // Jan 1 12:00:00, optionally .123
 		regexp.MustCompile(`(\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z|[+-]\d{2}:\d{2}))`), // ISO 8601
+		regexp.MustCompile(`(\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}(?:\.\d+)?)`),          // 2023/01/01 12:00:00 (Go log)
 	}
 	
 	return &LogParser{
-		timezone: loc,
+		sourceZone: zone,
+		sourceZones: zones,
 		railsRegex: railsRegex,
 		commonLogRegex: commonLogRegex,
+		syslog5424Regex: syslog5424Regex,
+		syslog3164Regex: syslog3164Regex,
+		klogRegex: klogRegex,
+		goCallerRegex: goCallerRegex,
 		timestampRegexes: timestampRegexes,
 	}
 }
 
+// ParseLogLine tries OTLP, GELF, Docker json-file, other JSON, RFC5424 and BSD syslog, klog, logfmt (e.g. logrus'
+// text output) and Rails logs, falling back to plain text. Once a source's
+// format is settled, or forced with --format, that parser goes first and the
+// others only get the lines it doesn't take. Registered parsers come before
+// all of them. Messages longer than --max-line-bytes are cut
 func (p *LogParser) ParseLogLine(line string, source string) LogEntry {
-	// First, try to parse as OTLP JSON
-	if entry, ok := p.tryParseOTLP(line); ok {
-		entry.Source = source
+	return p.parseCut(line, source, 0)
+}
+
+// parseCut parses a line that lost dropped bytes off its end while it was
+// read, cutting the message at maxLineBytes and marking what's missing
+func (p *LogParser) parseCut(line, source string, dropped int) LogEntry {
+	entry := p.parseFull(line, source)
+	truncateEntry(&entry, p.maxLineBytes, dropped)
+	return entry
+}
+
+// parseFull parses the line whatever its length
+func (p *LogParser) parseFull(line, source string) LogEntry {
+	if entry, ok := p.parseCustom(line, source); ok {
 		return entry
 	}
 	
-	// Try to parse as structured log (Rails, etc.)
-	if entry, ok := p.tryParseStructured(line); ok {
-		entry.Source = source
+	// Plain text takes any line, so a source settled on it still detects
+	// every line; only --format plain keeps them all plain
+	if kind := p.formatFor(source); kind != ParserAuto && (kind != ParserPlain || p.format == ParserPlain) {
+		if entry, ok := p.parseAs(kind, line, source); ok {
+			return entry
+		}
+		entry, _ := p.detect(line, source)
 		return entry
 	}
 	
-	// Fallback to plain text parsing
-	return p.parsePlainText(line, source)
+	entry, _ := p.detect(line, source)
+	p.sampleFormat(source, line)
+	return entry
 }
 
 func (p *LogParser) tryParseOTLP(line string) (LogEntry, bool) {
@@ -84,6 +160,11 @@ func (p *LogParser) tryParseOTLP(line string) (LogEntry, bool) {
 		return LogEntry{}, false
 	}
 	
+	// Any other JSON object is left to tryParseGenericJSON
+	if otlpLog.Timestamp == 0 && otlpLog.SeverityNumber == 0 && otlpLog.SeverityText == "" && otlpLog.Body == nil {
+		return LogEntry{}, false
+	}
+	
 	entry := LogEntry{
 		Raw:      line,
 		Metadata: make(map[string]interface{}),
@@ -91,10 +172,9 @@ func (p *LogParser) tryParseOTLP(line string) (LogEntry, bool) {
 	
 	// Convert timestamp to ISO 8601
 	if otlpLog.Timestamp > 0 {
-		t := time.Unix(0, otlpLog.Timestamp).In(p.timezone)
-		entry.Timestamp = t.Format(time.RFC3339)
+		setEntryTime(&entry, time.Unix(0, otlpLog.Timestamp))
 	} else {
-		entry.Timestamp = time.Now().In(p.timezone).Format(time.RFC3339)
+		entry.Timestamp = nowTimestamp()
 	}
 	
 	// Convert severity
@@ -123,6 +203,17 @@ func (p *LogParser) tryParseOTLP(line string) (LogEntry, bool) {
 		entry.Metadata["instrumentationScope"] = otlpLog.InstrumentationScope
 	}
 	
+	// Component: an explicit logger name first, then the OTLP scope name
+	for _, name := range []string{otlpLog.Component, otlpLog.Logger, otlpLog.LoggerName} {
+		if name != "" {
+			entry.Component = name
+			break
+		}
+	}
+	if scope, ok := otlpLog.InstrumentationScope["name"].(string); ok && entry.Component == "" {
+		entry.Component = scope
+	}
+	
 	return entry, true
 }
 
@@ -133,7 +224,7 @@ func (p *LogParser) tryParseStructured(line string) (LogEntry, bool) {
 	// Try Rails log format: "  (0.3ms)  SQL query" (after ANSI stripping)
 	if matches := p.railsRegex.FindStringSubmatch(cleanLine); len(matches) == 3 {
 		entry := LogEntry{
-			Timestamp: time.Now().In(p.timezone).Format("2006-01-02 15:04:05"),
+			Timestamp: nowTimestamp(),
 			Level:     INFO,
 			Message:   matches[2],
 			Raw:       line,
@@ -190,7 +281,7 @@ func (p *LogParser) parsePlainText(line string, source string) LogEntry {
 	cleanLine := ansiRegex.ReplaceAllString(line, "")
 	
 	entry := LogEntry{
-		Timestamp: time.Now().In(p.timezone).Format(time.RFC3339),
+		Timestamp: nowTimestamp(),
 		Level:     INFO,
 		Message:   cleanLine,
 		Raw:       line,
@@ -198,9 +289,25 @@ func (p *LogParser) parsePlainText(line string, source string) LogEntry {
 		Metadata:  make(map[string]interface{}),
 	}
 	
-	// Try to detect log level from the line
-	upperLine := strings.ToUpper(cleanLine)
-	if strings.Contains(upperLine, "ERROR") || strings.Contains(upperLine, "FATAL") {
+	// A leading epoch is the time and not part of the message
+	epoch, rest, hasEpoch := splitEpochPrefix(cleanLine)
+	if hasEpoch {
+		cleanLine = rest
+		entry.Message = rest
+		setEntryTime(&entry, epoch)
+	}
+	
+	// Move the file:line of Go's log package out of the message
+	if matches := p.goCallerRegex.FindStringSubmatch(cleanLine); len(matches) == 3 {
+		entry.Metadata["caller"] = matches[2]
+		entry.Message = matches[1] + cleanLine[len(matches[0]):]
+	}
+	
+	// Try to detect log level from the line, a caller like error.go:12 doesn't count
+	upperLine := strings.ToUpper(entry.Message)
+	if level, ok := p.keywordLevel(cleanLine); ok {
+		entry.Level = level
+	} else if strings.Contains(upperLine, "ERROR") || strings.Contains(upperLine, "FATAL") {
 		entry.Level = ERROR
 	} else if strings.Contains(upperLine, "WARN") || strings.Contains(upperLine, "WARNING") {
 		entry.Level = WARN
@@ -209,7 +316,12 @@ func (p *LogParser) parsePlainText(line string, source string) LogEntry {
 	}
 	
 	// Try to extract timestamp from common formats
-	p.extractTimestamp(&entry, cleanLine)
+	if !hasEpoch {
+		p.extractTimestamp(&entry, cleanLine)
+	}
+	
+	// Pairs like user_id=42 status=500 become metadata too
+	p.extractInlineFields(&entry)
 	
 	return entry
 }
@@ -224,11 +336,15 @@ func (p *LogParser) extractTimestamp(entry *LogEntry, line string) {
 				"Jan 2 15:04:05",
 				time.RFC3339,
 				time.RFC3339Nano,
+				"2006/01/02 15:04:05",
 			}
 			
 			for _, format := range formats {
-				if t, err := time.Parse(format, matches[1]); err == nil {
-					entry.Timestamp = t.In(p.timezone).Format(time.RFC3339)
+				if t, err := time.ParseInLocation(format, matches[1], p.zoneFor(entry.Source)); err == nil {
+					if t.Year() == 0 {
+						t = withSyslogYear(t, time.Now())
+					}
+					setEntryTime(entry, t)
 					return
 				}
 			}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cefHeaderFields are the pipe separated fields after "CEF:", the last one
+// followed by the extension: version, device vendor, product and version,
+// signature id, name and severity
+const cefHeaderFields = 7
+
+// cefTimeLayouts are the formats of the rt (receipt time) extension besides
+// milliseconds since the epoch
+var cefTimeLayouts = []string{"Jan 02 2006 15:04:05.000", "Jan 02 2006 15:04:05"}
+
+// tryParseCEF parses ArcSight Common Event Format lines, on their own or
+// after a syslog header: `CEF:0|Vendor|Product|1.0|100|Name|7|src=10.0.0.1`.
+// The name is the message, the severity the level, and the header and the
+// key=value extension become metadata. The time is the rt extension or the
+// syslog header's
+func (p *LogParser) tryParseCEF(line, source string) (LogEntry, bool) {
+	start := strings.Index(line, "CEF:")
+	if start < 0 || (start > 0 && line[start-1] != ' ') {
+		return LogEntry{}, false
+	}
+	header, extension, ok := splitCEFHeader(line[start+len("CEF:"):])
+	if !ok {
+		return LogEntry{}, false
+	}
+
+	entry := LogEntry{
+		Source:  source,
+		Level:   cefSeverityLevel(header[6]),
+		Message: header[5],
+		Raw:     line,
+		Metadata: map[string]interface{}{
+			"cef_version":    header[0],
+			"device_vendor":  header[1],
+			"device_product": header[2],
+			"device_version": header[3],
+			"signature_id":   header[4],
+			"severity":       header[6],
+		},
+	}
+	for key, value := range parseCEFExtension(extension) {
+		entry.Metadata[key] = value
+	}
+
+	if rt, ok := entry.Metadata["rt"].(string); ok {
+		if t, ok := p.parseCEFTime(rt, source); ok {
+			setEntryTime(&entry, t)
+		}
+	}
+	if entry.Time.IsZero() && start > 0 {
+		p.extractTimestamp(&entry, line[:start])
+	}
+	if entry.Time.IsZero() {
+		entry.Timestamp = nowTimestamp()
+	}
+
+	return entry, true
+}
+
+// splitCEFHeader splits the header fields on pipes not escaped with a
+// backslash and returns them unescaped, with the extension after them
+func splitCEFHeader(s string) ([]string, string, bool) {
+	fields := make([]string, 0, cefHeaderFields)
+	var field strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && (s[i+1] == '|' || s[i+1] == '\\'):
+			i++
+			field.WriteByte(s[i])
+		case s[i] == '|':
+			fields = append(fields, field.String())
+			field.Reset()
+			if len(fields) == cefHeaderFields {
+				return fields, s[i+1:], true
+			}
+		default:
+			field.WriteByte(s[i])
+		}
+	}
+
+	// A line may end right after the severity, without an extension
+	if len(fields) == cefHeaderFields-1 {
+		return append(fields, field.String()), "", true
+	}
+	return nil, "", false
+}
+
+// parseCEFExtension parses the key=value pairs after the header. Values may
+// hold spaces, so each runs up to the key of the next unescaped '='
+func parseCEFExtension(extension string) map[string]string {
+	type pair struct{ keyStart, eq int }
+	var pairs []pair
+	for i := 0; i < len(extension); i++ {
+		if extension[i] == '\\' {
+			i++
+			continue
+		}
+		if extension[i] != '=' {
+			continue
+		}
+		keyStart := i
+		for keyStart > 0 && extension[keyStart-1] != ' ' {
+			keyStart--
+		}
+		if keyStart < i {
+			pairs = append(pairs, pair{keyStart, i})
+		}
+	}
+
+	fields := make(map[string]string, len(pairs))
+	for i, pair := range pairs {
+		end := len(extension)
+		if i+1 < len(pairs) {
+			end = pairs[i+1].keyStart
+		}
+		fields[extension[pair.keyStart:pair.eq]] = unescapeCEF(strings.TrimRight(extension[pair.eq+1:end], " "))
+	}
+	return fields
+}
+
+// unescapeCEF undoes the escaping of extension values
+func unescapeCEF(value string) string {
+	if !strings.Contains(value, "\\") {
+		return value
+	}
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i+1 == len(value) {
+			b.WriteByte(value[i])
+			continue
+		}
+		i++
+		switch value[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		default:
+			b.WriteByte(value[i]) // \=, \| and \\
+		}
+	}
+	return b.String()
+}
+
+// cefSeverityLevel maps the severity, 0-10 or a name: 0 is DEBUG, up to 3
+// (Low) INFO, up to 6 (Medium) WARN and above that (High, Very-High) ERROR
+func cefSeverityLevel(severity string) LogLevel {
+	if n, err := strconv.Atoi(severity); err == nil {
+		switch {
+		case n >= 7:
+			return ERROR
+		case n >= 4:
+			return WARN
+		case n >= 1:
+			return INFO
+		default:
+			return DEBUG
+		}
+	}
+	switch strings.ToLower(severity) {
+	case "high", "very-high":
+		return ERROR
+	case "medium":
+		return WARN
+	default:
+		return INFO
+	}
+}
+
+// parseCEFTime parses an rt value: milliseconds since the epoch, or a date
+// like "Dec 23 2023 15:30:45" in the source's zone
+func (p *LogParser) parseCEFTime(value, source string) (time.Time, bool) {
+	if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.UnixMilli(ms), true
+	}
+	for _, layout := range cefTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, p.zoneFor(source)); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,62 @@
+package main
+
+// ParseFunc parses one line in a format panam doesn't know, reporting whether
+// it took the line. The entry it returns needs only the fields the format
+// has, the rest are filled in:
+//   - Time, when set, also sets Timestamp; with neither, the line's read time is used
+//   - Level is DEBUG unless set, as DEBUG is LogLevel's zero value. It must
+//     be one of DEBUG, INFO, WARN or ERROR; others are clamped to that range
+//   - Raw defaults to the line and Metadata to an empty map
+//   - Source is always the line's source, and Offset and Length are set by the index
+type ParseFunc func(line string) (LogEntry, bool)
+
+// customParser is a ParseFunc registered under a name
+type customParser struct {
+	name  string
+	parse ParseFunc
+}
+
+// Register adds a parser tried before the built-in ones, in the order
+// parsers were registered. Registering a name again replaces its parser.
+// Parsers must be registered before the LogParser is shared, like every
+// other setting
+func (p *LogParser) Register(name string, parse ParseFunc) {
+	for i := range p.custom {
+		if p.custom[i].name == name {
+			p.custom[i].parse = parse
+			return
+		}
+	}
+	p.custom = append(p.custom, customParser{name: name, parse: parse})
+}
+
+// parseCustom parses the line with the first registered parser taking it
+func (p *LogParser) parseCustom(line, source string) (LogEntry, bool) {
+	for _, custom := range p.custom {
+		entry, ok := custom.parse(line)
+		if !ok {
+			continue
+		}
+		if entry.Timestamp == "" {
+			if entry.Time.IsZero() {
+				entry.Timestamp = nowTimestamp()
+			} else {
+				setEntryTime(&entry, entry.Time)
+			}
+		}
+		if entry.Level < DEBUG {
+			entry.Level = DEBUG
+		} else if entry.Level > ERROR {
+			entry.Level = ERROR
+		}
+		if entry.Raw == "" {
+			entry.Raw = line
+		}
+		if entry.Metadata == nil {
+			entry.Metadata = make(map[string]interface{})
+		}
+		entry.Source = source
+		return entry, true
+	}
+	return LogEntry{}, false
+}
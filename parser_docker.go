@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// dockerLogLine is a line written by Docker's json-file logging driver
+type dockerLogLine struct {
+	Log    *string `json:"log"`
+	Stream string  `json:"stream"`
+	Time   string  `json:"time"`
+}
+
+// tryParseDockerJSON parses a line of Docker's json-file driver:
+// {"log":"message\n","stream":"stdout","time":"2023-12-23T15:30:45.123Z"}.
+// The log field is parsed like a plain text line for its level and inline
+// fields. Without a level in it, stderr lines count as warnings
+func (p *LogParser) tryParseDockerJSON(line, source string) (LogEntry, bool) {
+	if len(line) == 0 || line[0] != '{' {
+		return LogEntry{}, false
+	}
+
+	var docker dockerLogLine
+	if err := json.Unmarshal([]byte(line), &docker); err != nil {
+		return LogEntry{}, false
+	}
+	if docker.Log == nil || docker.Stream == "" || docker.Time == "" {
+		return LogEntry{}, false
+	}
+
+	message := strings.TrimRight(*docker.Log, "\r\n")
+	entry := p.parsePlainText(message, source)
+	entry.Raw = line
+	entry.Metadata["stream"] = docker.Stream
+	if t, ok := p.jsonTime(docker.Time, source); ok {
+		setEntryTime(&entry, t)
+	}
+
+	// The plain text parser falls back to INFO, so only a keyword says the
+	// message named its level
+	_, keyword := p.keywordLevel(message)
+	leveled := keyword || entry.Level != INFO || strings.Contains(strings.ToUpper(message), "INFO")
+	if docker.Stream == "stderr" && !leveled {
+		entry.Level = WARN
+	}
+
+	return entry, true
+}
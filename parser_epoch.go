@@ -0,0 +1,42 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Years a leading number may fall in to be read as a time, so a pid or a
+// count starting the line isn't taken for one
+const (
+	epochMinYear = 2000
+	epochMaxYear = 2100
+)
+
+// splitEpochPrefix splits a leading Unix time off the line, in seconds (10
+// digits) or milliseconds (13 digits), as in CloudWatch Logs exports like
+// `1703347200123 message`
+func splitEpochPrefix(line string) (time.Time, string, bool) {
+	digits := 0
+	for digits < len(line) && line[digits] >= '0' && line[digits] <= '9' {
+		digits++
+	}
+	if digits < 10 || digits > 13 || (digits < len(line) && line[digits] != ' ' && line[digits] != '\t') {
+		return time.Time{}, "", false
+	}
+
+	value, err := strconv.ParseInt(line[:digits], 10, 64)
+	if err != nil {
+		return time.Time{}, "", false
+	}
+	// 11 digits of seconds are past 2100 and 12 of milliseconds mostly before
+	// 2000, the year check turns those down
+	t := time.UnixMilli(value).UTC()
+	if digits <= 11 {
+		t = time.Unix(value, 0).UTC()
+	}
+	if t.Year() < epochMinYear || t.Year() > epochMaxYear {
+		return time.Time{}, "", false
+	}
+	return t, strings.TrimLeft(line[digits:], " \t"), true
+}
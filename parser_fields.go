@@ -0,0 +1,31 @@
+package main
+
+import "regexp"
+
+// inlineFieldRegex matches key=value tokens starting a word, with the value
+// quoted or running to the next space. URL queries like ?a=1&b=2 and
+// equations like "x = y" don't match
+var inlineFieldRegex = regexp.MustCompile(`(?:^|\s)(\w+)=("[^"]*"|\S+)`)
+
+// minInlineFields is how many key=value tokens make a message structured,
+// a lone one is more likely prose
+const minInlineFields = 2
+
+// extractInlineFields copies key=value pairs found in a plain text message
+// into the entry's metadata, leaving the message as it is. Keys the parser
+// already set, like caller, are kept
+func (p *LogParser) extractInlineFields(entry *LogEntry) {
+	matches := inlineFieldRegex.FindAllStringSubmatch(entry.Message, -1)
+	if len(matches) < minInlineFields {
+		return
+	}
+	for _, match := range matches {
+		key, value := match[1], match[2]
+		if len(value) >= 2 && value[0] == '"' {
+			value = value[1 : len(value)-1]
+		}
+		if _, exists := entry.Metadata[key]; !exists {
+			entry.Metadata[key] = value
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// defaultFormatSample is how many lines of a source are parsed with every
+// parser before its format is settled
+const defaultFormatSample = 100
+
+// ParserKind names the parser that takes a line
+type ParserKind int
+
+const (
+	ParserAuto ParserKind = iota // Try every parser
+	ParserCEF
+	ParserOTLP
+	ParserGELF
+	ParserDocker
+	ParserJSON
+	ParserSyslog
+	ParserKlog
+	ParserLogfmt
+	ParserRails
+	ParserPlain
+)
+
+var parserKindNames = []string{"auto", "cef", "otlp", "gelf", "docker", "json", "syslog", "klog", "logfmt", "rails", "plain"}
+
+func (k ParserKind) String() string {
+	return parserKindNames[k]
+}
+
+// parseParserKind parses a --format value
+func parseParserKind(name string) (ParserKind, error) {
+	for kind, kindName := range parserKindNames {
+		if strings.EqualFold(name, kindName) {
+			return ParserKind(kind), nil
+		}
+	}
+	return ParserAuto, fmt.Errorf("unknown format %q (%s)", name, strings.Join(parserKindNames, ", "))
+}
+
+// detectionOrder is the order parsers are tried in before falling back to
+// plain text. It matters: OTLP, GELF and Docker lines are also generic JSON.
+// CEF comes first, its "CEF:" prefix is unambiguous even after a syslog header
+var detectionOrder = []ParserKind{ParserCEF, ParserOTLP, ParserGELF, ParserDocker, ParserJSON, ParserSyslog, ParserKlog, ParserLogfmt, ParserRails}
+
+// parseAs parses the line with one parser, reporting whether it took it
+func (p *LogParser) parseAs(kind ParserKind, line, source string) (LogEntry, bool) {
+	var entry LogEntry
+	var ok bool
+	switch kind {
+	case ParserCEF:
+		entry, ok = p.tryParseCEF(line, source)
+	case ParserOTLP:
+		entry, ok = p.tryParseOTLP(line)
+	case ParserGELF:
+		entry, ok = p.tryParseGELF(line, source)
+	case ParserDocker:
+		entry, ok = p.tryParseDockerJSON(line, source)
+	case ParserJSON:
+		entry, ok = p.tryParseGenericJSON(line, source)
+	case ParserSyslog:
+		entry, ok = p.tryParseSyslog5424(line)
+		if !ok {
+			entry, ok = p.tryParseSyslog3164(line, source)
+		}
+	case ParserKlog:
+		entry, ok = p.tryParseKlog(line, source)
+	case ParserLogfmt:
+		entry, ok = p.tryParseLogfmt(line, source)
+	case ParserRails:
+		entry, ok = p.tryParseStructured(line)
+	case ParserPlain:
+		return p.parsePlainText(line, source), true
+	}
+	if ok {
+		entry.Source = source
+	}
+	return entry, ok
+}
+
+// detect parses the line with the first parser taking it and returns which
+func (p *LogParser) detect(line, source string) (LogEntry, ParserKind) {
+	for _, kind := range detectionOrder {
+		if entry, ok := p.parseAs(kind, line, source); ok {
+			return entry, kind
+		}
+	}
+	return p.parsePlainText(line, source), ParserPlain
+}
+
+// detectDominantFormat returns the format of the sample lines when they're
+// all one format, and ParserAuto when they're mixed or blank. A file mixing
+// formats keeps trying every parser, since a fast path to generic JSON or
+// plain text would take OTLP or any other lines without ever mismatching
+func (p *LogParser) detectDominantFormat(sample []string) ParserKind {
+	dominant := ParserAuto
+	for _, line := range sample {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		_, kind := p.detect(line, "")
+		if dominant != ParserAuto && kind != dominant {
+			return ParserAuto
+		}
+		dominant = kind
+	}
+	return dominant
+}
+
+// formatCache remembers the format settled for each source. Until a source
+// has sampleSize non-blank lines parsed, they're kept as its sample
+type formatCache struct {
+	mutex      sync.RWMutex
+	sampleSize int
+	samples    map[string][]string
+	kinds      map[string]ParserKind
+}
+
+func newFormatCache(sampleSize int) *formatCache {
+	return &formatCache{
+		sampleSize: sampleSize,
+		samples:    make(map[string][]string),
+		kinds:      make(map[string]ParserKind),
+	}
+}
+
+// formatFor returns the parser to try first for a source's lines: the one
+// forced with --format, else the one settled for the source, else ParserAuto
+func (p *LogParser) formatFor(source string) ParserKind {
+	if p.format != ParserAuto || p.formats == nil {
+		return p.format
+	}
+	p.formats.mutex.RLock()
+	defer p.formats.mutex.RUnlock()
+	return p.formats.kinds[source]
+}
+
+// sampleFormat adds a line parsed with every parser to its source's sample,
+// settling the source's format once the sample is full
+func (p *LogParser) sampleFormat(source, line string) {
+	if p.format != ParserAuto || p.formats == nil || strings.TrimSpace(line) == "" {
+		return
+	}
+
+	cache := p.formats
+	cache.mutex.Lock()
+	if _, settled := cache.kinds[source]; settled {
+		cache.mutex.Unlock()
+		return
+	}
+	sample := append(cache.samples[source], line)
+	if len(sample) < cache.sampleSize {
+		cache.samples[source] = sample
+		cache.mutex.Unlock()
+		return
+	}
+	delete(cache.samples, source)
+	cache.mutex.Unlock()
+
+	// Detection parses the sample again, so it's done outside the lock
+	kind := p.detectDominantFormat(sample)
+	cache.mutex.Lock()
+	cache.kinds[source] = kind
+	cache.mutex.Unlock()
+}
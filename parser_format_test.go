@@ -0,0 +1,91 @@
+package main
+
+import "testing"
+
+func TestDetectDominantFormat(t *testing.T) {
+	parser := NewLogParser("UTC")
+
+	testCases := []struct {
+		sample   []string
+		expected ParserKind
+	}{
+		{[]string{`{"level":"info","msg":"a"}`, "", `{"level":"warn","msg":"b"}`}, ParserJSON},
+		{[]string{`time="2023-12-23T15:30:45Z" level=info msg=a`, `level=error msg=b`}, ParserLogfmt},
+		{[]string{"2023-12-23 15:30:45 INFO: a", "plain b"}, ParserPlain},
+		{[]string{`{"level":"info","msg":"a"}`, "plain b"}, ParserAuto},
+		{[]string{`{"severityText":"INFO","body":"a"}`, `{"level":"info","msg":"b"}`}, ParserAuto},
+		{[]string{"", " "}, ParserAuto},
+	}
+
+	for _, tc := range testCases {
+		if kind := parser.detectDominantFormat(tc.sample); kind != tc.expected {
+			t.Errorf("Expected %v, got %v for sample %q", tc.expected, kind, tc.sample)
+		}
+	}
+}
+
+func TestParseLogLine_SettlesFormatPerSource(t *testing.T) {
+	parser := NewLogParser("UTC")
+	parser.formats = newFormatCache(2)
+
+	parser.ParseLogLine(`{"level":"info","msg":"a"}`, "app.log")
+	if kind := parser.formatFor("app.log"); kind != ParserAuto {
+		t.Fatalf("Expected no format before the sample is full, got %v", kind)
+	}
+	parser.ParseLogLine(`{"level":"info","msg":"b"}`, "app.log")
+	parser.ParseLogLine("plain a", "other.log")
+	if kind := parser.formatFor("app.log"); kind != ParserJSON {
+		t.Errorf("Expected app.log to settle on json, got %v", kind)
+	}
+	if kind := parser.formatFor("other.log"); kind != ParserAuto {
+		t.Errorf("Expected other.log to keep sampling, got %v", kind)
+	}
+
+	// A line the settled parser doesn't take is detected in full
+	entry := parser.ParseLogLine("2023-12-23 15:30:45 ERROR: disk full", "app.log")
+	if entry.Level != ERROR || entry.Source != "app.log" {
+		t.Errorf("Expected a plain text error from app.log, got %+v", entry)
+	}
+}
+
+func TestParseLogLine_PlainSourceStillDetectsOtherFormats(t *testing.T) {
+	parser := NewLogParser("UTC")
+	parser.formats = newFormatCache(100)
+
+	for i := 0; i < 100; i++ {
+		parser.ParseLogLine("2023-12-23 15:30:45 INFO: starting worker", "app.log")
+	}
+	if kind := parser.formatFor("app.log"); kind != ParserPlain {
+		t.Fatalf("Expected app.log to settle on plain text, got %v", kind)
+	}
+
+	// The app switches to JSON mid-stream
+	entry := parser.ParseLogLine(`{"level":"error","msg":"db down","time":"2023-12-23T15:31:00Z","db":"orders"}`, "app.log")
+	if entry.Level != ERROR || entry.Message != "db down" || entry.Metadata["db"] != "orders" {
+		t.Errorf("Expected the JSON line parsed as JSON, got %+v", entry)
+	}
+	if entry.Time.Minute() != 31 {
+		t.Errorf("Expected the JSON time, got %v", entry.Time)
+	}
+	if entry := parser.ParseLogLine("2023-12-23 15:31:01 WARN: back to text", "app.log"); entry.Level != WARN {
+		t.Errorf("Expected plain text lines to keep parsing, got %+v", entry)
+	}
+}
+
+func TestParseLogLine_ForcedFormat(t *testing.T) {
+	kind, err := parseParserKind("PLAIN")
+	if err != nil {
+		t.Fatalf("Failed to parse format: %v", err)
+	}
+	parser := NewLogParser("UTC")
+	parser.format = kind
+
+	line := `{"level":"error","msg":"boom"}`
+	if entry := parser.ParseLogLine(line, ""); entry.Message != line {
+		t.Errorf("Expected the JSON line kept as plain text, got %q", entry.Message)
+	}
+
+	if _, err := parseParserKind("xml"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// tryParseGELF parses a Graylog GELF message, recognized by its version and
+// short_message fields. The level is a syslog severity and the timestamp
+// Unix seconds with a fraction. Additional fields lose their leading
+// underscore and are kept as metadata like every other field
+func (p *LogParser) tryParseGELF(line, source string) (LogEntry, bool) {
+	if len(line) == 0 || line[0] != '{' {
+		return LogEntry{}, false
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &fields); err != nil {
+		return LogEntry{}, false
+	}
+	if _, ok := fields["version"]; !ok {
+		return LogEntry{}, false
+	}
+	message, ok := fields["short_message"].(string)
+	if !ok {
+		return LogEntry{}, false
+	}
+	delete(fields, "version")
+	delete(fields, "short_message")
+
+	entry := LogEntry{
+		Timestamp: nowTimestamp(),
+		Level:     INFO,
+		Message:   message,
+		Raw:       line,
+		Metadata:  make(map[string]interface{}),
+	}
+
+	if severity, ok := fields["level"].(float64); ok {
+		entry.Level = syslogSeverityToLevel(int(severity))
+		delete(fields, "level")
+	}
+	if t, ok := p.jsonTime(fields["timestamp"], source); ok {
+		setEntryTime(&entry, t)
+		delete(fields, "timestamp")
+	}
+
+	for key, value := range fields {
+		key = strings.TrimPrefix(key, "_")
+		if component, ok := value.(string); ok && key == "component" {
+			entry.Component = component
+			continue
+		}
+		entry.Metadata[key] = value
+	}
+
+	return entry, true
+}
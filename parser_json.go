@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Keys generic JSON loggers (zap, logrus, pino, bunyan, slog, ECS) use for
+// the fields of an entry, in order of preference
+var (
+	jsonLevelKeys     = []string{"level", "lvl", "severity", "log.level"}
+	jsonMessageKeys   = []string{"msg", "message", "text"}
+	jsonTimeKeys      = []string{"time", "timestamp", "ts", "@timestamp"}
+	jsonComponentKeys = []string{"component", "logger", "logger_name"}
+)
+
+// jsonTimeLayouts are tried for string time values
+var jsonTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// tryParseGenericJSON parses a JSON object from a structured logger that
+// isn't OTLP. Level, message, time and logger name are looked up under their
+// common keys, every other key is kept as metadata
+func (p *LogParser) tryParseGenericJSON(line, source string) (LogEntry, bool) {
+	if len(line) == 0 || line[0] != '{' {
+		return LogEntry{}, false
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &fields); err != nil {
+		return LogEntry{}, false
+	}
+
+	// ECS nests the level as {"log": {"level": ...}}
+	if nested, ok := fields["log"].(map[string]interface{}); ok {
+		if level, ok := nested["level"]; ok {
+			fields["log.level"] = level
+			delete(nested, "level")
+			if len(nested) == 0 {
+				delete(fields, "log")
+			}
+		}
+	}
+
+	entry := LogEntry{
+		Timestamp: nowTimestamp(),
+		Level:     INFO,
+		Message:   line,
+		Raw:       line,
+		Metadata:  make(map[string]interface{}),
+	}
+
+	if key, value, ok := takeJSONField(fields, jsonLevelKeys); ok {
+		if level, ok := jsonLevel(value); ok {
+			entry.Level = level
+		} else {
+			entry.Metadata[key] = value
+		}
+	}
+	if key, value, ok := takeJSONField(fields, jsonMessageKeys); ok {
+		if message, ok := value.(string); ok {
+			entry.Message = message
+		} else {
+			entry.Metadata[key] = value
+		}
+	}
+	if key, value, ok := takeJSONField(fields, jsonTimeKeys); ok {
+		if t, ok := p.jsonTime(value, source); ok {
+			setEntryTime(&entry, t)
+		} else {
+			entry.Metadata[key] = value
+		}
+	}
+	if _, value, ok := takeJSONField(fields, jsonComponentKeys); ok {
+		entry.Component = fmt.Sprint(value)
+	}
+
+	for key, value := range fields {
+		entry.Metadata[key] = value
+	}
+
+	return entry, true
+}
+
+// takeJSONField removes and returns the first of keys present in fields
+func takeJSONField(fields map[string]interface{}, keys []string) (string, interface{}, bool) {
+	for _, key := range keys {
+		if value, ok := fields[key]; ok && value != nil {
+			delete(fields, key)
+			return key, value, true
+		}
+	}
+	return "", nil, false
+}
+
+// jsonLevel maps a level name, or a pino/bunyan level number (10 trace ...
+// 60 fatal), onto a LogLevel
+func jsonLevel(value interface{}) (LogLevel, bool) {
+	switch v := value.(type) {
+	case string:
+		switch strings.ToUpper(v) {
+		case "ERROR", "ERR", "FATAL", "PANIC", "DPANIC", "CRITICAL", "CRIT", "ALERT", "EMERGENCY":
+			return ERROR, true
+		case "WARN", "WARNING":
+			return WARN, true
+		case "INFO", "NOTICE":
+			return INFO, true
+		case "DEBUG", "TRACE":
+			return DEBUG, true
+		}
+	case float64:
+		switch {
+		case v >= 50:
+			return ERROR, true
+		case v >= 40:
+			return WARN, true
+		case v >= 30:
+			return INFO, true
+		default:
+			return DEBUG, true
+		}
+	}
+	return INFO, false
+}
+
+// jsonTime parses a time value: a timestamp string, read in the source's zone
+// when it has no offset, or a Unix time in seconds, milliseconds, microseconds
+// or nanoseconds, told apart by magnitude
+func (p *LogParser) jsonTime(value interface{}, source string) (time.Time, bool) {
+	switch v := value.(type) {
+	case string:
+		for _, layout := range jsonTimeLayouts {
+			if t, err := time.ParseInLocation(layout, v, p.zoneFor(source)); err == nil {
+				return t, true
+			}
+		}
+	case float64:
+		switch {
+		case v <= 0:
+			return time.Time{}, false
+		case v >= 1e17:
+			return time.Unix(0, int64(v)), true
+		case v >= 1e14:
+			return time.UnixMicro(int64(v)), true
+		case v >= 1e11:
+			return time.UnixMilli(int64(v)), true
+		default:
+			sec, frac := math.Modf(v)
+			return time.Unix(int64(sec), int64(frac*1e9)), true
+		}
+	}
+	return time.Time{}, false
+}
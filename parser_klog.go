@@ -0,0 +1,50 @@
+package main
+
+import "time"
+
+// tryParseKlog parses klog lines written by Kubernetes components, e.g.
+// `I1223 15:30:45.123456   12 server.go:123] message`: a level letter, the
+// month and day, the time, the thread id and the caller. The timestamp has no
+// year, so like BSD syslog it's taken to be in the last twelve months
+func (p *LogParser) tryParseKlog(line, source string) (LogEntry, bool) {
+	if len(line) < 2 || line[1] < '0' || line[1] > '9' {
+		return LogEntry{}, false
+	}
+
+	matches := p.klogRegex.FindStringSubmatch(line)
+	if len(matches) != 6 {
+		return LogEntry{}, false
+	}
+
+	t, err := time.ParseInLocation("0102 15:04:05", matches[2], p.zoneFor(source))
+	if err != nil {
+		return LogEntry{}, false
+	}
+
+	entry := LogEntry{
+		Level:   klogLevel(matches[1][0]),
+		Message: matches[5],
+		Raw:     line,
+		Metadata: map[string]interface{}{
+			"thread": matches[3],
+			"caller": matches[4],
+		},
+	}
+	setEntryTime(&entry, withSyslogYear(t, time.Now()))
+
+	return entry, true
+}
+
+// klogLevel maps the klog severity letter; fatal lines count as errors
+func klogLevel(letter byte) LogLevel {
+	switch letter {
+	case 'W':
+		return WARN
+	case 'E', 'F':
+		return ERROR
+	case 'D':
+		return DEBUG
+	default:
+		return INFO
+	}
+}
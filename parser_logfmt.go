@@ -0,0 +1,129 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+// tryParseLogfmt parses a logfmt line, such as logrus' default text output:
+// time="2023-12-23T15:30:45Z" level=info msg="started" component=api.
+// Fields are looked up under the same keys as JSON logs, so logrus levels
+// like warning, fatal, panic and trace map like they do there. Only lines
+// made of key=value pairs with a level or message are taken
+func (p *LogParser) tryParseLogfmt(line, source string) (LogEntry, bool) {
+	fields, ok := parseLogfmtFields(line)
+	if !ok {
+		return LogEntry{}, false
+	}
+	if !hasAnyField(fields, jsonLevelKeys) && !hasAnyField(fields, jsonMessageKeys) {
+		return LogEntry{}, false
+	}
+
+	entry := LogEntry{
+		Timestamp: nowTimestamp(),
+		Level:     INFO,
+		Message:   line,
+		Raw:       line,
+		Metadata:  make(map[string]interface{}),
+	}
+
+	if key, value, ok := takeJSONField(fields, jsonLevelKeys); ok {
+		if level, ok := jsonLevel(value); ok {
+			entry.Level = level
+		} else {
+			entry.Metadata[key] = value
+		}
+	}
+	if _, value, ok := takeJSONField(fields, jsonMessageKeys); ok {
+		entry.Message = value.(string)
+	}
+	if key, value, ok := takeJSONField(fields, jsonTimeKeys); ok {
+		if t, ok := p.jsonTime(value, source); ok {
+			setEntryTime(&entry, t)
+		} else {
+			entry.Metadata[key] = value
+		}
+	}
+	if _, value, ok := takeJSONField(fields, jsonComponentKeys); ok {
+		entry.Component = value.(string)
+	}
+
+	for key, value := range fields {
+		entry.Metadata[key] = value
+	}
+
+	return entry, true
+}
+
+// parseLogfmtFields splits a line into key=value pairs. Values may be
+// double-quoted with Go escapes. It fails on anything else, like a bare word
+func parseLogfmtFields(line string) (map[string]interface{}, bool) {
+	fields := make(map[string]interface{})
+	rest := strings.TrimSpace(line)
+	for rest != "" {
+		eq := strings.IndexByte(rest, '=')
+		if eq <= 0 || !isLogfmtKey(rest[:eq]) {
+			return nil, false
+		}
+		key := rest[:eq]
+		rest = rest[eq+1:]
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := closingQuote(rest)
+			if end < 0 {
+				return nil, false
+			}
+			unquoted, err := strconv.Unquote(rest[:end+1])
+			if err != nil {
+				return nil, false
+			}
+			value, rest = unquoted, rest[end+1:]
+			if rest != "" && rest[0] != ' ' {
+				return nil, false
+			}
+		} else if space := strings.IndexByte(rest, ' '); space >= 0 {
+			value, rest = rest[:space], rest[space:]
+		} else {
+			value, rest = rest, ""
+		}
+
+		fields[key] = value
+		rest = strings.TrimLeft(rest, " ")
+	}
+	return fields, len(fields) > 0
+}
+
+// isLogfmtKey reports whether s can be a logfmt key, e.g. level or http.status
+func isLogfmtKey(s string) bool {
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '.' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// closingQuote returns the index of the quote ending the string s starts
+// with, skipping escaped quotes, or -1
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// hasAnyField reports whether fields has one of keys
+func hasAnyField(fields map[string]interface{}, keys []string) bool {
+	for _, key := range keys {
+		if _, ok := fields[key]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// syslogNil is the RFC5424 NILVALUE used for absent header fields
+const syslogNil = "-"
+
+// tryParseSyslog5424 parses RFC5424 syslog lines such as
+// `<34>1 2003-10-11T22:14:15.003Z host app 1234 ID47 - message`
+func (p *LogParser) tryParseSyslog5424(line string) (LogEntry, bool) {
+	if len(line) == 0 || line[0] != '<' {
+		return LogEntry{}, false
+	}
+
+	matches := p.syslog5424Regex.FindStringSubmatch(line)
+	if len(matches) != 9 {
+		return LogEntry{}, false
+	}
+
+	priority, err := strconv.Atoi(matches[1])
+	if err != nil || priority > 191 {
+		return LogEntry{}, false
+	}
+
+	structuredData, message, ok := splitStructuredData(matches[8])
+	if !ok {
+		return LogEntry{}, false
+	}
+
+	facility, severity := priority/8, priority%8
+	entry := LogEntry{
+		Level:   syslogSeverityToLevel(severity),
+		Message: strings.TrimPrefix(message, "\ufeff"), // MSG may start with a UTF-8 BOM
+		Raw:     line,
+		Metadata: map[string]interface{}{
+			"facility": facility,
+			"severity": severity,
+		},
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, matches[3]); matches[3] != syslogNil && err == nil {
+		setEntryTime(&entry, t)
+	} else {
+		entry.Timestamp = nowTimestamp()
+	}
+
+	fields := []struct {
+		key   string
+		value string
+	}{
+		{"hostname", matches[4]},
+		{"app_name", matches[5]},
+		{"procid", matches[6]},
+		{"msgid", matches[7]},
+	}
+	for _, field := range fields {
+		if field.value != syslogNil {
+			entry.Metadata[field.key] = field.value
+		}
+	}
+
+	if matches[5] != syslogNil {
+		entry.Component = matches[5]
+	}
+
+	if len(structuredData) > 0 {
+		entry.Metadata["structured_data"] = structuredData
+	}
+
+	return entry, true
+}
+
+// tryParseSyslog3164 parses RFC3164 (BSD) syslog lines such as
+// `<13>Dec 23 15:30:45 host sshd[123]: message`. The timestamp has no year,
+// so it's taken to be in the last twelve months
+func (p *LogParser) tryParseSyslog3164(line, source string) (LogEntry, bool) {
+	if len(line) == 0 || line[0] != '<' {
+		return LogEntry{}, false
+	}
+
+	matches := p.syslog3164Regex.FindStringSubmatch(line)
+	if len(matches) != 7 {
+		return LogEntry{}, false
+	}
+
+	priority, err := strconv.Atoi(matches[1])
+	if err != nil || priority > 191 {
+		return LogEntry{}, false
+	}
+
+	// Days below 10 are padded with a space, e.g. "Jan  2"
+	t, err := time.ParseInLocation("Jan _2 15:04:05", matches[2], p.zoneFor(source))
+	if err != nil {
+		return LogEntry{}, false
+	}
+
+	facility, severity := priority/8, priority%8
+	entry := LogEntry{
+		Level:   syslogSeverityToLevel(severity),
+		Message: matches[6],
+		Raw:     line,
+		Metadata: map[string]interface{}{
+			"facility": facility,
+			"severity": severity,
+			"hostname": matches[3],
+		},
+	}
+	setEntryTime(&entry, withSyslogYear(t, time.Now()))
+
+	if matches[4] != "" {
+		entry.Component = matches[4]
+		entry.Metadata["program"] = matches[4]
+	}
+	if matches[5] != "" {
+		entry.Metadata["pid"] = matches[5]
+	}
+
+	return entry, true
+}
+
+// withSyslogYear puts a yearless timestamp in now's year, or the year before
+// when that would be more than a day ahead, e.g. December lines read in January
+func withSyslogYear(t, now time.Time) time.Time {
+	dated := time.Date(now.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	if dated.After(now.Add(24 * time.Hour)) {
+		dated = dated.AddDate(-1, 0, 0)
+	}
+	return dated
+}
+
+// syslogSeverityToLevel maps the numeric syslog severity (0=emergency ... 7=debug)
+func syslogSeverityToLevel(severity int) LogLevel {
+	switch {
+	case severity <= 3: // emergency, alert, critical, error
+		return ERROR
+	case severity == 4: // warning
+		return WARN
+	case severity <= 6: // notice, informational
+		return INFO
+	default:
+		return DEBUG
+	}
+}
+
+// splitStructuredData separates the RFC5424 STRUCTURED-DATA part from the
+// free-form message. Each `[id key="value" ...]` element becomes a map of its
+// params keyed by the SD-ID
+func splitStructuredData(rest string) (map[string]interface{}, string, bool) {
+	if rest == syslogNil || strings.HasPrefix(rest, syslogNil+" ") {
+		return nil, strings.TrimPrefix(rest[len(syslogNil):], " "), true
+	}
+	if !strings.HasPrefix(rest, "[") {
+		return nil, "", false
+	}
+
+	elements := make(map[string]interface{})
+	i := 0
+	for i < len(rest) && rest[i] == '[' {
+		end, ok := structuredElementEnd(rest, i)
+		if !ok {
+			return nil, "", false
+		}
+		id, params := parseStructuredElement(rest[i+1 : end])
+		elements[id] = params
+		i = end + 1
+	}
+
+	return elements, strings.TrimPrefix(rest[i:], " "), true
+}
+
+// structuredElementEnd finds the `]` closing the element that starts at
+// start, skipping over quoted param values and their escapes
+func structuredElementEnd(s string, start int) (int, bool) {
+	inQuotes := false
+	for i := start + 1; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && inQuotes:
+			i++
+		case s[i] == '"':
+			inQuotes = !inQuotes
+		case s[i] == ']' && !inQuotes:
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// parseStructuredElement splits `id key="value" key2="value2"` into the SD-ID and its params
+func parseStructuredElement(element string) (string, map[string]interface{}) {
+	params := make(map[string]interface{})
+
+	id, rest, _ := strings.Cut(element, " ")
+	for rest != "" {
+		rest = strings.TrimLeft(rest, " ")
+		key, value, found := strings.Cut(rest, `="`)
+		if !found {
+			break
+		}
+
+		var unescaped strings.Builder
+		i := 0
+		for ; i < len(value) && value[i] != '"'; i++ {
+			if value[i] == '\\' && i+1 < len(value) {
+				i++
+			}
+			unescaped.WriteByte(value[i])
+		}
+		params[key] = unescaped.String()
+
+		if i >= len(value) {
+			break
+		}
+		rest = value[i+1:]
+	}
+
+	return id, params
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -54,6 +55,171 @@ func TestLogParser_ParseOTLP(t *testing.T) {
 	}
 }
 
+func TestLogParser_ParseComponent(t *testing.T) {
+	parser := NewLogParser("UTC")
+	
+	testCases := []struct {
+		line              string
+		expectedComponent string
+		description       string
+	}{
+		{
+			line:              `{"severityText": "INFO", "body": "ready", "logger": "http.server"}`,
+			expectedComponent: "http.server",
+			description:       "Should use the zap logger name",
+		},
+		{
+			line:              `{"severityText": "INFO", "body": "ready", "logger_name": "app.db"}`,
+			expectedComponent: "app.db",
+			description:       "Should use the python logger name",
+		},
+		{
+			line:              `{"severityText": "INFO", "body": "ready", "component": "scheduler", "logger": "main"}`,
+			expectedComponent: "scheduler",
+			description:       "Should prefer an explicit component",
+		},
+		{
+			line:              `{"severityText": "INFO", "body": "ready", "instrumentationScope": {"name": "io.opentelemetry.http"}}`,
+			expectedComponent: "io.opentelemetry.http",
+			description:       "Should fall back to the OTLP scope name",
+		},
+		{
+			line:              "2023-12-23 15:30:45 INFO: ready",
+			expectedComponent: "",
+			description:       "Should leave plain text without component",
+		},
+	}
+	
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			entry := parser.ParseLogLine(tc.line, "app.log")
+			if entry.Component != tc.expectedComponent {
+				t.Errorf("Expected component '%s', got '%s'", tc.expectedComponent, entry.Component)
+			}
+			if entry.Source != "app.log" {
+				t.Errorf("Expected source to stay 'app.log', got '%s'", entry.Source)
+			}
+		})
+	}
+}
+
+func TestLogParser_ParseLogrusText(t *testing.T) {
+	parser := NewLogParser("UTC")
+	
+	testCases := []struct {
+		line          string
+		expectedLevel LogLevel
+		description   string
+	}{
+		{`time="2023-12-23T15:30:45Z" level=info msg="started" component=api`, INFO, "Should parse an info line"},
+		{`time="2023-12-23T15:30:45Z" level=warning msg="started" component=api`, WARN, "Should map warning to WARN"},
+		{`time="2023-12-23T15:30:45Z" level=fatal msg="started" component=api`, ERROR, "Should map fatal to ERROR"},
+		{`time="2023-12-23T15:30:45Z" level=panic msg="started" component=api`, ERROR, "Should map panic to ERROR"},
+		{`time="2023-12-23T15:30:45Z" level=trace msg="started" component=api`, DEBUG, "Should map trace to DEBUG"},
+	}
+	
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			entry := parser.ParseLogLine(tc.line, "")
+			
+			if entry.Level != tc.expectedLevel {
+				t.Errorf("Expected level %v, got %v", tc.expectedLevel, entry.Level)
+			}
+			if entry.Message != "started" || entry.Component != "api" {
+				t.Errorf("Expected message 'started' from api, got '%s' from '%s'", entry.Message, entry.Component)
+			}
+			if entry.Timestamp != "2023-12-23T15:30:45Z" {
+				t.Errorf("Expected timestamp 2023-12-23T15:30:45Z, got '%s'", entry.Timestamp)
+			}
+		})
+	}
+	
+	// Quoted values keep escapes and spaces, other keys become metadata
+	entry := parser.ParseLogLine(`level=panic msg="runtime error: \"index out of range\"" goroutine=17 error="boom now"`, "")
+	if entry.Level != ERROR || entry.Message != `runtime error: "index out of range"` {
+		t.Errorf("Expected the panic message unquoted at ERROR, got %v '%s'", entry.Level, entry.Message)
+	}
+	if entry.Metadata["goroutine"] != "17" || entry.Metadata["error"] != "boom now" {
+		t.Errorf("Expected the other fields as metadata, got %v", entry.Metadata)
+	}
+	
+	// Text with a stray = isn't logfmt
+	if entry := parser.ParseLogLine("2023-12-23 15:30:45 INFO: set retries=3", ""); entry.Message != "2023-12-23 15:30:45 INFO: set retries=3" {
+		t.Errorf("Expected plain text to be left alone, got '%s'", entry.Message)
+	}
+}
+
+func TestLogParser_ParseGenericJSON(t *testing.T) {
+	parser := NewLogParser("UTC")
+	
+	testCases := []struct {
+		line              string
+		expectedLevel     LogLevel
+		expectedMessage   string
+		expectedTimestamp string
+		expectedMetadata  map[string]interface{}
+		description       string
+	}{
+		{
+			line:              `{"level":"warn","time":"2023-12-23T15:30:45Z","msg":"slow query","latency_ms":320}`,
+			expectedLevel:     WARN,
+			expectedMessage:   "slow query",
+			expectedTimestamp: "2023-12-23T15:30:45Z",
+			expectedMetadata:  map[string]interface{}{"latency_ms": float64(320)},
+			description:       "Should parse level, time, message and keep other keys",
+		},
+		{
+			line:              `{"level":50,"time":1703345445000,"msg":"payment failed","pid":42}`,
+			expectedLevel:     ERROR,
+			expectedMessage:   "payment failed",
+			expectedTimestamp: "2023-12-23T15:30:45Z",
+			expectedMetadata:  map[string]interface{}{"pid": float64(42)},
+			description:       "Should parse pino level numbers and millisecond times",
+		},
+		{
+			line:              `{"lvl":"debug","ts":1703345445.5,"message":"cache miss"}`,
+			expectedLevel:     DEBUG,
+			expectedMessage:   "cache miss",
+			expectedTimestamp: "2023-12-23T15:30:45Z",
+			expectedMetadata:  map[string]interface{}{},
+			description:       "Should parse zap style seconds",
+		},
+		{
+			line:              `{"@timestamp":"2023-12-23T16:30:45+01:00","log":{"level":"error","logger":"db"},"text":"connection lost"}`,
+			expectedLevel:     ERROR,
+			expectedMessage:   "connection lost",
+			expectedTimestamp: "2023-12-23T15:30:45Z",
+			expectedMetadata:  map[string]interface{}{"log": map[string]interface{}{"logger": "db"}},
+			description:       "Should parse the nested ECS level",
+		},
+	}
+	
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			entry := parser.ParseLogLine(tc.line, "app.log")
+			
+			if entry.Level != tc.expectedLevel {
+				t.Errorf("Expected level %v, got %v", tc.expectedLevel, entry.Level)
+			}
+			if entry.Message != tc.expectedMessage {
+				t.Errorf("Expected message '%s', got '%s'", tc.expectedMessage, entry.Message)
+			}
+			if entry.Timestamp != tc.expectedTimestamp {
+				t.Errorf("Expected timestamp '%s', got '%s'", tc.expectedTimestamp, entry.Timestamp)
+			}
+			if !reflect.DeepEqual(entry.Metadata, tc.expectedMetadata) {
+				t.Errorf("Expected metadata %v, got %v", tc.expectedMetadata, entry.Metadata)
+			}
+		})
+	}
+	
+	// OTLP keeps its own parser
+	entry := parser.ParseLogLine(`{"severityNumber": 17, "body": "otlp", "level": "info"}`, "app.log")
+	if entry.Level != ERROR || entry.Message != "otlp" {
+		t.Errorf("Expected OTLP to be parsed as such, got %+v", entry)
+	}
+}
+
 func TestLogParser_ParseRailsLog(t *testing.T) {
 	parser := NewLogParser("UTC")
 	
@@ -170,6 +336,74 @@ func TestLogParser_ExtractTimestamp(t *testing.T) {
 	}
 }
 
+func TestLogParser_ParseGoLog(t *testing.T) {
+	parser := NewLogParser("UTC")
+	
+	testCases := []struct {
+		line            string
+		expectedMessage string
+		expectedCaller  string
+		expectedTime    string
+		description     string
+	}{
+		{
+			line:            "2009/11/10 23:00:00 server started",
+			expectedMessage: "2009/11/10 23:00:00 server started",
+			expectedTime:    "2009-11-10T23:00:00Z",
+			description:     "Should parse the default Go log format",
+		},
+		{
+			line:            "2009/11/10 23:00:00 main.go:42: server started",
+			expectedMessage: "2009/11/10 23:00:00 server started",
+			expectedCaller:  "main.go:42",
+			expectedTime:    "2009-11-10T23:00:00Z",
+			description:     "Should move the short file caller to metadata",
+		},
+		{
+			line:            "2009/11/10 23:00:00.123456 /src/app/error.go:7: server started",
+			expectedMessage: "2009/11/10 23:00:00.123456 server started",
+			expectedCaller:  "/src/app/error.go:7",
+			expectedTime:    "2009-11-10T23:00:00Z",
+			description:     "Should move the long file caller to metadata",
+		},
+		{
+			line:            "api: 2009/11/10 23:00:00 main.go:42: server started",
+			expectedMessage: "api: 2009/11/10 23:00:00 server started",
+			expectedCaller:  "main.go:42",
+			expectedTime:    "2009-11-10T23:00:00Z",
+			description:     "Should parse a line after a SetPrefix prefix",
+		},
+		{
+			line:            "main.go:42: server started",
+			expectedMessage: "server started",
+			expectedCaller:  "main.go:42",
+			description:     "Should parse the short file caller without date and time",
+		},
+	}
+	
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			entry := parser.ParseLogLine(tc.line, "")
+			
+			if tc.expectedTime != "" && entry.Timestamp != tc.expectedTime {
+				t.Errorf("Expected timestamp %s, got '%s'", tc.expectedTime, entry.Timestamp)
+			}
+			if tc.expectedTime == "" && !entry.Time.IsZero() {
+				t.Errorf("Expected no parsed time, got %v", entry.Time)
+			}
+			if entry.Message != tc.expectedMessage {
+				t.Errorf("Expected message '%s', got '%s'", tc.expectedMessage, entry.Message)
+			}
+			if caller, _ := entry.Metadata["caller"].(string); caller != tc.expectedCaller {
+				t.Errorf("Expected caller '%s', got '%s'", tc.expectedCaller, caller)
+			}
+			if entry.Level != INFO {
+				t.Errorf("Expected INFO level, got %v", entry.Level)
+			}
+		})
+	}
+}
+
 func TestCircularBuffer(t *testing.T) {
 	buffer := NewCircularBuffer(3)
 	
@@ -206,6 +440,16 @@ func TestCircularBuffer(t *testing.T) {
 	if all[2].Message != "Entry 4" {
 		t.Errorf("Expected last entry to be 'Entry 4', got '%s'", all[2].Message)
 	}
+	
+	// Evictions are counted for good, clearing doesn't reset them
+	if buffer.EvictedCount() != 1 {
+		t.Errorf("Expected 1 evicted entry, got %d", buffer.EvictedCount())
+	}
+	buffer.Clear()
+	buffer.Add(LogEntry{Message: "Entry 5"})
+	if buffer.EvictedCount() != 1 {
+		t.Errorf("Expected the evicted count kept after Clear, got %d", buffer.EvictedCount())
+	}
 }
 
 func TestStripANSI(t *testing.T) {
@@ -256,4 +500,433 @@ func BenchmarkCircularBuffer_Add(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		buffer.Add(entry)
 	}
-}
\ No newline at end of file
+}
+func TestLogParser_ParseSyslog5424(t *testing.T) {
+	parser := NewLogParser("UTC")
+
+	line := `<34>1 2003-10-11T22:14:15.003Z mymachine.example.com su - ID47 [exampleSDID@32473 iut="3" eventSource="App \"X\""] 'su root' failed for lonvick`
+	entry := parser.ParseLogLine(line, "syslog")
+
+	// Priority 34 = facility 4 (auth), severity 2 (critical)
+	if entry.Level != ERROR {
+		t.Errorf("Expected level ERROR, got %v", entry.Level)
+	}
+	if entry.Message != "'su root' failed for lonvick" {
+		t.Errorf("Unexpected message: '%s'", entry.Message)
+	}
+	if entry.Timestamp != "2003-10-11T22:14:15Z" {
+		t.Errorf("Unexpected timestamp: '%s'", entry.Timestamp)
+	}
+	if entry.Metadata["facility"] != 4 || entry.Metadata["hostname"] != "mymachine.example.com" || entry.Metadata["app_name"] != "su" {
+		t.Errorf("Unexpected header metadata: %v", entry.Metadata)
+	}
+	if entry.Component != "su" {
+		t.Errorf("Expected app name 'su' as component, got '%s'", entry.Component)
+	}
+	if _, ok := entry.Metadata["procid"]; ok {
+		t.Error("Expected NILVALUE procid to be omitted")
+	}
+
+	sd, ok := entry.Metadata["structured_data"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected structured data in metadata, got %v", entry.Metadata["structured_data"])
+	}
+	params, _ := sd["exampleSDID@32473"].(map[string]interface{})
+	if params["iut"] != "3" || params["eventSource"] != `App "X"` {
+		t.Errorf("Unexpected structured data params: %v", params)
+	}
+}
+
+func TestLogParser_ParseSyslog5424Levels(t *testing.T) {
+	parser := NewLogParser("UTC")
+
+	testCases := []struct {
+		line          string
+		expectedLevel LogLevel
+		message       string
+	}{
+		{"<165>1 - host app 1234 - - warning text", INFO, "warning text"}, // severity 5 notice
+		{"<12>1 2023-12-23T15:30:45Z - - - - - disk almost full", WARN, "disk almost full"},
+		{"<15>1 2023-12-23T15:30:45Z host app - - -", DEBUG, ""},
+	}
+
+	for _, tc := range testCases {
+		entry := parser.ParseLogLine(tc.line, "")
+		if entry.Level != tc.expectedLevel {
+			t.Errorf("Expected level %v, got %v for line: %s", tc.expectedLevel, entry.Level, tc.line)
+		}
+		if entry.Message != tc.message {
+			t.Errorf("Expected message '%s', got '%s'", tc.message, entry.Message)
+		}
+		if _, ok := entry.Metadata["severity"]; !ok {
+			t.Errorf("Expected line to be parsed as syslog: %s", tc.line)
+		}
+	}
+}
+
+func TestLogParser_ParseSyslog3164(t *testing.T) {
+	parser := NewLogParser("UTC")
+
+	entry := parser.ParseLogLine("<13>Dec 23 15:30:45 web01 sshd[123]: Accepted publickey for deploy", "syslog")
+
+	// Priority 13 = facility 1 (user), severity 5 (notice)
+	if entry.Level != INFO {
+		t.Errorf("Expected level INFO, got %v", entry.Level)
+	}
+	if entry.Message != "Accepted publickey for deploy" {
+		t.Errorf("Unexpected message: '%s'", entry.Message)
+	}
+	if entry.Time.Year() == 0 || entry.Time.Month() != time.December || entry.Time.Day() != 23 || entry.Time.Hour() != 15 {
+		t.Errorf("Expected Dec 23 15:30:45 in a recent year, got %v", entry.Time)
+	}
+	if entry.Metadata["facility"] != 1 || entry.Metadata["hostname"] != "web01" || entry.Metadata["program"] != "sshd" || entry.Metadata["pid"] != "123" {
+		t.Errorf("Unexpected header metadata: %v", entry.Metadata)
+	}
+	if entry.Component != "sshd" {
+		t.Errorf("Expected program 'sshd' as component, got '%s'", entry.Component)
+	}
+
+	// A space-padded day, no pid, and no tag at all
+	entry = parser.ParseLogLine("<11>Jan  2 03:04:05 router kernel: link down", "syslog")
+	if entry.Level != ERROR || entry.Time.Day() != 2 || entry.Component != "kernel" || entry.Message != "link down" {
+		t.Errorf("Unexpected entry for a padded day: %+v", entry)
+	}
+	if _, ok := entry.Metadata["pid"]; ok {
+		t.Error("Expected no pid without one in the tag")
+	}
+	entry = parser.ParseLogLine("<12>Mar 10 08:00:00 switch port 4 flapping", "syslog")
+	if entry.Level != WARN || entry.Component != "" || entry.Message != "port 4 flapping" {
+		t.Errorf("Unexpected entry without a tag: %+v", entry)
+	}
+}
+
+func TestWithSyslogYear(t *testing.T) {
+	now := time.Date(2024, time.January, 5, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		month    time.Month
+		day      int
+		expected int
+	}{
+		{time.January, 5, 2024},
+		{time.January, 6, 2024}, // Clocks a little ahead
+		{time.December, 31, 2023},
+		{time.March, 1, 2023},
+	}
+	for _, tc := range cases {
+		got := withSyslogYear(time.Date(0, tc.month, tc.day, 10, 0, 0, 0, time.UTC), now)
+		if got.Year() != tc.expected {
+			t.Errorf("%s %d: expected %d, got %d", tc.month, tc.day, tc.expected, got.Year())
+		}
+	}
+}
+
+func TestLogParser_LevelKeywords(t *testing.T) {
+	keywords, err := parseLevelKeywords("ERROR=E|ERR, warn=W,INFO=I,DEBUG=D")
+	if err != nil {
+		t.Fatalf("Failed to parse level keywords: %v", err)
+	}
+	parser := NewLogParser("UTC")
+	parser.levelKeywords = keywords
+
+	testCases := []struct {
+		line          string
+		expectedLevel LogLevel
+	}{
+		{"E 2023-12-23 15:30:45 payment failed", ERROR},
+		{"err: payment failed", ERROR},
+		{"[W] disk almost full", WARN},
+		{"I1223 15:30:45.000000 main.go] started", INFO},
+		{"D cache warm, 0 errors", DEBUG},
+		{"Everything is fine", INFO},          // Not a keyword
+		{"2023-12-23 ERROR disk full", ERROR}, // Built-in detection still applies
+		{"\x1b[31mE\x1b[0m payment failed", ERROR},
+	}
+
+	for _, tc := range testCases {
+		if entry := parser.ParseLogLine(tc.line, ""); entry.Level != tc.expectedLevel {
+			t.Errorf("Expected level %v, got %v for line: %q", tc.expectedLevel, entry.Level, tc.line)
+		}
+		line := []byte(tc.line + "\n")
+		if level := parser.chunkLevel(line, 0, 0, int64(len(line))); level != levelUnknown && level != tc.expectedLevel {
+			t.Errorf("Expected indexed level %v, got %v for line: %q", tc.expectedLevel, level, tc.line)
+		}
+	}
+
+	for _, value := range []string{"ERROR", "FATAL=F", "WARN=W|"} {
+		if _, err := parseLevelKeywords(value); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
+
+func TestLogParser_InlineFields(t *testing.T) {
+	parser := NewLogParser("UTC")
+
+	testCases := []struct {
+		line     string
+		expected map[string]interface{}
+	}{
+		{
+			`2023-12-23 15:30:45 ERROR: request failed user_id=42 path="/api/orders" status=500`,
+			map[string]interface{}{"user_id": "42", "path": "/api/orders", "status": "500"},
+		},
+		{`2023-12-23 15:30:45 INFO: retry=3 for job`, map[string]interface{}{}},                  // A lone pair is prose
+		{`GET http://example.com/search?q=1&page=2 took 3ms`, map[string]interface{}{}},         // URL queries aren't fields
+		{`2023-12-23 15:30:45 INFO: solved x = y + 1 and a = b`, map[string]interface{}{}},      // Nor are equations
+		{`2023/12/23 15:30:45 main.go:12: caller=override id=7`, map[string]interface{}{"caller": "main.go:12", "id": "7"}},
+	}
+
+	for _, tc := range testCases {
+		entry := parser.ParseLogLine(tc.line, "")
+		if len(entry.Metadata) != len(tc.expected) {
+			t.Errorf("Expected metadata %v, got %v for line: %s", tc.expected, entry.Metadata, tc.line)
+			continue
+		}
+		for key, value := range tc.expected {
+			if entry.Metadata[key] != value {
+				t.Errorf("Expected %s=%v, got %v for line: %s", key, value, entry.Metadata[key], tc.line)
+			}
+		}
+	}
+
+	line := `2023-12-23 15:30:45 ERROR: request failed user_id=42 status=500`
+	if entry := parser.ParseLogLine(line, ""); entry.Message != line {
+		t.Errorf("Expected the message left intact, got %q", entry.Message)
+	}
+}
+
+func TestLogParser_ParseGELF(t *testing.T) {
+	parser := NewLogParser("UTC")
+
+	line := `{"version":"1.1","host":"web-1","short_message":"payment failed","full_message":"stack trace","timestamp":1703345445.25,"level":3,"_request_id":"abc","_component":"billing"}`
+	entry := parser.ParseLogLine(line, "")
+
+	if entry.Message != "payment failed" {
+		t.Errorf("Expected the short message, got %q", entry.Message)
+	}
+	if entry.Level != ERROR {
+		t.Errorf("Expected syslog level 3 to be ERROR, got %v", entry.Level)
+	}
+	if entry.Timestamp != "2023-12-23T15:30:45Z" {
+		t.Errorf("Expected the epoch timestamp converted, got %s", entry.Timestamp)
+	}
+	if entry.Component != "billing" {
+		t.Errorf("Expected component billing, got %q", entry.Component)
+	}
+	if entry.Metadata["full_message"] != "stack trace" || entry.Metadata["host"] != "web-1" {
+		t.Errorf("Expected full_message and host in metadata, got %v", entry.Metadata)
+	}
+	if entry.Metadata["request_id"] != "abc" {
+		t.Errorf("Expected _request_id stored as request_id, got %v", entry.Metadata)
+	}
+	if _, ok := entry.Metadata["version"]; ok {
+		t.Error("Expected version to be dropped")
+	}
+
+	// Without the GELF signature it's generic JSON
+	entry = parser.ParseLogLine(`{"level":"warn","short_message":"not gelf"}`, "")
+	if entry.Level != WARN || entry.Metadata["short_message"] != "not gelf" {
+		t.Errorf("Expected a generic JSON entry, got %+v", entry)
+	}
+}
+
+func TestLogParser_ParseDockerJSON(t *testing.T) {
+	parser := NewLogParser("UTC")
+
+	line := `{"log":"ERROR payment failed user=42\n","stream":"stdout","time":"2023-12-23T15:30:45.123456789Z"}`
+	entry := parser.ParseLogLine(line, "")
+	if entry.Level != ERROR {
+		t.Errorf("Expected the level from the message, got %v", entry.Level)
+	}
+	if strings.HasSuffix(entry.Message, "\n") || !strings.Contains(entry.Message, "payment failed") {
+		t.Errorf("Expected the log field without its newline, got %q", entry.Message)
+	}
+	if !strings.HasPrefix(entry.Timestamp, "2023-12-23T15:30:45") {
+		t.Errorf("Expected the docker time, got %s", entry.Timestamp)
+	}
+	if entry.Metadata["stream"] != "stdout" {
+		t.Errorf("Expected the stream in metadata, got %v", entry.Metadata)
+	}
+	if entry.Raw != line {
+		t.Errorf("Expected the raw line kept, got %q", entry.Raw)
+	}
+
+	// stderr without a level in the message is a warning
+	entry = parser.ParseLogLine(`{"log":"connection reset\n","stream":"stderr","time":"2023-12-23T15:30:46Z"}`, "")
+	if entry.Level != WARN || entry.Message != "connection reset" {
+		t.Errorf("Expected a WARN entry from stderr, got %+v", entry)
+	}
+	entry = parser.ParseLogLine(`{"log":"INFO started\n","stream":"stderr","time":"2023-12-23T15:30:46Z"}`, "")
+	if entry.Level != INFO {
+		t.Errorf("Expected the message's level to win over stderr, got %v", entry.Level)
+	}
+
+	// Without stream and time it's generic JSON
+	entry = parser.ParseLogLine(`{"log":"not docker","level":"debug"}`, "")
+	if entry.Level != DEBUG || entry.Metadata["log"] != "not docker" {
+		t.Errorf("Expected a generic JSON entry, got %+v", entry)
+	}
+}
+
+func TestLogParser_ParseKlog(t *testing.T) {
+	parser := NewLogParser("UTC")
+
+	entry := parser.ParseLogLine("I1223 15:30:45.123456   12 server.go:123] Starting controller", "")
+	if entry.Level != INFO || entry.Message != "Starting controller" {
+		t.Errorf("Expected an INFO entry with the text after ], got %+v", entry)
+	}
+	if entry.Metadata["caller"] != "server.go:123" || entry.Metadata["thread"] != "12" {
+		t.Errorf("Expected caller and thread in metadata, got %v", entry.Metadata)
+	}
+	if entry.Time.Month() != time.December || entry.Time.Day() != 23 || entry.Time.Nanosecond() != 123456000 {
+		t.Errorf("Expected December 23 with microseconds, got %v", entry.Time)
+	}
+
+	for _, tc := range []struct {
+		line   string
+		level  LogLevel
+		thread string
+	}{
+		{"W0102 03:04:05.000001 4242 reflector.go:7] watch closed", WARN, "4242"},
+		{"E0102 03:04:05.000001 1234567 cache.go:99] sync failed", ERROR, "1234567"},
+		{"F0102 03:04:05.000001       1 main.go:50] cannot start: port in use", ERROR, "1"},
+	} {
+		entry := parser.ParseLogLine(tc.line, "")
+		if entry.Level != tc.level || entry.Metadata["thread"] != tc.thread {
+			t.Errorf("%q: expected %v from thread %s, got %v and %v", tc.line, tc.level, tc.thread, entry.Level, entry.Metadata)
+		}
+	}
+
+	// A level letter alone isn't enough
+	entry = parser.ParseLogLine("I1223 is not a klog line", "")
+	if _, ok := entry.Metadata["caller"]; ok {
+		t.Errorf("Expected plain text, got %+v", entry)
+	}
+}
+
+func TestLogParser_ParseCEF(t *testing.T) {
+	parser := NewLogParser("UTC")
+
+	entry := parser.ParseLogLine(`CEF:0|Security|threatmanager|1.0|100|worm successfully stopped|10|src=10.0.0.1 dst=2.1.2.2 msg=Detected a \= sign rt=1703345445123`, "")
+	if entry.Level != ERROR || entry.Message != "worm successfully stopped" {
+		t.Errorf("Expected an ERROR entry named by the header, got %+v", entry)
+	}
+	if entry.Metadata["device_vendor"] != "Security" || entry.Metadata["signature_id"] != "100" || entry.Metadata["src"] != "10.0.0.1" {
+		t.Errorf("Expected header and extension fields in metadata, got %v", entry.Metadata)
+	}
+	if entry.Metadata["msg"] != "Detected a = sign" {
+		t.Errorf("Expected an unescaped value with spaces, got %q", entry.Metadata["msg"])
+	}
+	if entry.Time.UnixMilli() != 1703345445123 {
+		t.Errorf("Expected the rt time, got %v", entry.Time)
+	}
+
+	// After a syslog header, with an escaped pipe in the name
+	entry = parser.ParseLogLine(`2023-12-23 15:30:45 host CEF:0|Vendor|Product|2|login|a \| b|Medium|suser=bob`, "")
+	if entry.Level != WARN || entry.Message != "a | b" || entry.Metadata["suser"] != "bob" {
+		t.Errorf("Expected a WARN entry with the unescaped name, got %+v", entry)
+	}
+	if entry.Time.Hour() != 15 || entry.Time.Minute() != 30 {
+		t.Errorf("Expected the syslog header time, got %v", entry.Time)
+	}
+
+	for _, tc := range []struct {
+		severity string
+		level    LogLevel
+	}{
+		{"0", DEBUG}, {"3", INFO}, {"Low", INFO}, {"6", WARN}, {"7", ERROR}, {"Very-High", ERROR},
+	} {
+		entry := parser.ParseLogLine("CEF:0|V|P|1|1|name|"+tc.severity+"|", "")
+		if entry.Level != tc.level {
+			t.Errorf("Severity %s: expected %v, got %v", tc.severity, tc.level, entry.Level)
+		}
+	}
+
+	// Too few fields isn't CEF
+	entry = parser.ParseLogLine("CEF:0|Vendor|Product", "")
+	if _, ok := entry.Metadata["device_vendor"]; ok {
+		t.Errorf("Expected plain text, got %+v", entry)
+	}
+}
+
+func TestLogParser_EpochPrefix(t *testing.T) {
+	parser := NewLogParser("UTC")
+
+	entry := parser.ParseLogLine("1703347200123 ERROR payment failed user=42 status=500", "")
+	if entry.Message != "ERROR payment failed user=42 status=500" || entry.Level != ERROR {
+		t.Errorf("Expected the epoch stripped and the level found, got %q at %v", entry.Message, entry.Level)
+	}
+	if expected := time.Date(2023, 12, 23, 16, 0, 0, 123e6, time.UTC); !entry.Time.Equal(expected) {
+		t.Errorf("Expected %v from the milliseconds, got %v", expected, entry.Time)
+	}
+	if entry.Metadata["user"] != "42" {
+		t.Errorf("Expected inline fields after the epoch, got %v", entry.Metadata)
+	}
+
+	entry = parser.ParseLogLine("1703347200\tworker started", "")
+	if entry.Message != "worker started" || entry.Time.Unix() != 1703347200 {
+		t.Errorf("Expected epoch seconds, got %q at %v", entry.Message, entry.Time)
+	}
+
+	// Numbers that aren't a plausible time stay in the message
+	for _, line := range []string{
+		"12345 process started",
+		"9999999999 too far ahead",
+		"17033472001234567 too long",
+		"1703347200123ms without a space",
+	} {
+		if entry := parser.ParseLogLine(line, ""); entry.Message != line || !entry.Time.IsZero() {
+			t.Errorf("%q: expected the line kept as it is, got %q at %v", line, entry.Message, entry.Time)
+		}
+	}
+}
+
+func TestLogParser_RegisterCustomParser(t *testing.T) {
+	parser := NewLogParser("UTC")
+
+	// A proprietary format: time|level letter|component|message
+	parser.Register("pipes", func(line string) (LogEntry, bool) {
+		fields := strings.SplitN(line, "|", 4)
+		if len(fields) != 4 || len(fields[1]) != 1 {
+			return LogEntry{}, false
+		}
+		t, err := time.Parse(time.RFC3339, fields[0])
+		if err != nil {
+			return LogEntry{}, false
+		}
+		levels := map[string]LogLevel{"E": ERROR, "W": WARN, "I": INFO, "D": DEBUG}
+		return LogEntry{Time: t, Level: levels[fields[1]], Component: fields[2], Message: fields[3]}, true
+	})
+
+	entry := parser.ParseLogLine("2023-12-23T15:30:45Z|W|billing|card declined, INFO follows", "app.log")
+	if entry.Level != WARN || entry.Component != "billing" || entry.Message != "card declined, INFO follows" {
+		t.Errorf("Expected the custom parser to take the line, got %+v", entry)
+	}
+	if entry.Timestamp != "2023-12-23T15:30:45Z" || entry.Source != "app.log" || entry.Raw == "" || entry.Metadata == nil {
+		t.Errorf("Expected the missing fields filled in, got %+v", entry)
+	}
+
+	// Lines it doesn't take go to the built-in parsers
+	if entry := parser.ParseLogLine(`{"level":"error","msg":"boom"}`, ""); entry.Level != ERROR || entry.Message != "boom" {
+		t.Errorf("Expected generic JSON for other lines, got %+v", entry)
+	}
+
+	// Levels past the known ones are clamped, they index per-level counts
+	parser.Register("levels", func(line string) (LogEntry, bool) {
+		level, ok := map[string]LogLevel{"fatal": LogLevel(5), "trace": LogLevel(-3)}[line]
+		return LogEntry{Level: level, Message: line}, ok
+	})
+	if entry := parser.ParseLogLine("fatal", ""); entry.Level != ERROR {
+		t.Errorf("Expected a level above ERROR clamped, got %v", entry.Level)
+	}
+	if entry := parser.ParseLogLine("trace", ""); entry.Level != DEBUG {
+		t.Errorf("Expected a level below DEBUG clamped, got %v", entry.Level)
+	}
+	model := NewUnifiedModel(&Config{MaxLines: 100, RefreshRate: 1, Timezone: "UTC"})
+	model.AddLogBatch([]LogEntry{parser.ParseLogLine("fatal", "")})
+
+	// The indexer can't guess levels a custom parser may set
+	if level := parser.chunkLevel([]byte("INFO started"), 0, 0, 12); level != levelUnknown {
+		t.Errorf("Expected levels left for a parse, got %v", level)
+	}
+}
@@ -0,0 +1,23 @@
+package main
+
+// togglePause freezes the view on the lines shown, or resumes tailing.
+// Lines keep arriving while paused, they're only counted in the header
+func (m *UnifiedModel) togglePause() {
+	if m.paused {
+		m.resumeTailing()
+		return
+	}
+	m.paused = true
+	m.pausedLines = 0
+	m.tailing = false
+	m.followMatches = false
+}
+
+// resumeTailing ends a pause and follows the newest line again
+func (m *UnifiedModel) resumeTailing() {
+	m.paused = false
+	m.pausedLines = 0
+	m.tailing = true
+	m.followMatches = false
+	m.scrollToBottom()
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"io"
+	"os"
+)
+
+// pipeReader reads a named pipe across writers: when the last writer closes
+// it, the pipe is opened again, waiting for the next one, instead of
+// returning io.EOF. With once it stops after the first writer, for
+// --no-follow
+type pipeReader struct {
+	path string
+	file *os.File
+	once bool
+}
+
+func (p *pipeReader) Read(b []byte) (int, error) {
+	for {
+		if p.file == nil {
+			// Opening blocks until a writer opens the pipe too
+			file, err := os.Open(p.path)
+			if err != nil {
+				return 0, err
+			}
+			p.file = file
+		}
+
+		n, err := p.file.Read(b)
+		if n > 0 {
+			return n, nil
+		}
+		if err == io.EOF && !p.once {
+			p.file.Close()
+			p.file = nil
+			continue
+		}
+		return 0, err
+	}
+}
+
+// isNamedPipe reports whether path is a FIFO, made with mkfifo
+func isNamedPipe(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode()&os.ModeNamedPipe != 0
+}
+
+// namedPipes returns the FIFOs among files
+func namedPipes(files []string) []string {
+	var pipes []string
+	for _, file := range files {
+		if isNamedPipe(file) {
+			pipes = append(pipes, file)
+		}
+	}
+	return pipes
+}
+
+// regularFiles returns the files that aren't named pipes
+func regularFiles(files []string) []string {
+	var regular []string
+	for _, file := range files {
+		if !isNamedPipe(file) {
+			regular = append(regular, file)
+		}
+	}
+	return regular
+}
+
+// streamPipes streams named pipes in the background like stdin, since they
+// can't be indexed or read again, until panam quits
+func (a *UnifiedApp) streamPipes(pipes []string) {
+	for _, pipe := range pipes {
+		go a.streamFrom(&pipeReader{path: pipe, once: a.config.NoFollow}, pipe)
+	}
+}
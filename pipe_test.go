@@ -0,0 +1,155 @@
+//go:build unix
+
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPipeReader_KeepsReadingAcrossWriters(t *testing.T) {
+	pipe := filepath.Join(t.TempDir(), "logpipe")
+	if err := syscall.Mkfifo(pipe, 0600); err != nil {
+		t.Skipf("Can't make a named pipe: %v", err)
+	}
+	if !isNamedPipe(pipe) || isNamedPipe(t.TempDir()) {
+		t.Fatal("Expected only the FIFO to be a named pipe")
+	}
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(&pipeReader{path: pipe})
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	// Each writer opens the pipe, writes and closes it again
+	for _, line := range []string{"INFO: first writer", "ERROR: second writer"} {
+		writer, err := os.OpenFile(pipe, os.O_WRONLY, 0)
+		if err != nil {
+			t.Fatalf("Failed to open the pipe for writing: %v", err)
+		}
+		writer.WriteString(line + "\n")
+		writer.Close()
+
+		select {
+		case got := <-lines:
+			if got != line {
+				t.Errorf("Expected %q, got %q", line, got)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Timed out waiting for %q", line)
+		}
+	}
+}
+
+func TestPipe_NotedInHeader(t *testing.T) {
+	model := NewUnifiedModel(&Config{Timezone: "UTC"})
+	model.width = 160
+	model.pipes = []string{"/tmp/logpipe"}
+	model.AddLogEntry(LogEntry{Message: "hello", Level: INFO})
+	if header := model.renderHeader(); !strings.Contains(header, "Reading pipe logpipe") {
+		t.Errorf("Expected the header to note the pipe, got %q", header)
+	}
+}
+
+func TestPipe_FilesIndexedNextToPipe(t *testing.T) {
+	pipe := filepath.Join(t.TempDir(), "logpipe")
+	if err := syscall.Mkfifo(pipe, 0600); err != nil {
+		t.Skipf("Can't make a named pipe: %v", err)
+	}
+	file := writeTestLog(t, []string{"2023-12-23 15:30:45 INFO: from the file", "2023-12-23 15:30:46 WARN: file again"})
+	files := []string{file, pipe}
+	if regular := regularFiles(files); len(regular) != 1 || regular[0] != file {
+		t.Fatalf("Expected only the file to be indexed, got %v", regular)
+	}
+
+	app := NewUnifiedApp(&Config{MaxLines: 50, RefreshRate: 1, Timezone: "UTC", Files: files, NoFollow: true, Tail: defaultTail})
+	app.model.Update(tea.WindowSizeMsg{Width: 160, Height: 30})
+	app.streamPipes(namedPipes(files))
+	app.indexFile(file)
+	if _, ok := app.model.indexer.(*FastIndexer); !ok {
+		t.Fatalf("Expected the file to be indexed, got %T", app.model.indexer)
+	}
+
+	writer, err := os.OpenFile(pipe, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("Failed to open the pipe for writing: %v", err)
+	}
+	writer.WriteString("2023-12-23 15:30:47 ERROR: from the pipe\n")
+	writer.Close()
+	deadline := time.Now().Add(2 * time.Second)
+	for app.model.totalLines < 3 && time.Now().Before(deadline) {
+		app.model.Update(streamReadyMsg{})
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	view := app.model.View()
+	for _, want := range []string{"from the file", "file again", "from the pipe", "logpipe"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in the log stream, got:\n%s", want, view)
+		}
+	}
+
+	// Polling for changes checks the file, a pipe can't be indexed again
+	app.model.checkFileChanges()
+	if app.model.loadingFile != file {
+		t.Errorf("Expected the file to be checked for changes, got %s", app.model.loadingFile)
+	}
+}
+
+func TestAlsoTail_StreamsNamedPipeAsItsOwnSource(t *testing.T) {
+	pipe := filepath.Join(t.TempDir(), "app.err")
+	if err := syscall.Mkfifo(pipe, 0600); err != nil {
+		t.Skipf("Can't make a named pipe: %v", err)
+	}
+
+	app := NewUnifiedApp(&Config{MaxLines: 50, RefreshRate: 1, Timezone: "UTC", AlsoTail: []string{pipe}, NoFollow: true})
+	done := make(chan struct{})
+	go func() {
+		app.tailInto(pipe)
+		close(done)
+	}()
+
+	writer, err := os.OpenFile(pipe, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("Failed to open the pipe for writing: %v", err)
+	}
+	writer.WriteString("2023-12-23 15:30:45 ERROR: from stderr\n")
+	writer.Close()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out reading the pipe")
+	}
+	app.streamFrom(strings.NewReader("2023-12-23 15:30:46 INFO: from stdout"), "stdin")
+	app.model.Update(streamReadyMsg{})
+
+	if len(app.model.entries) != 2 || app.model.entries[0].Source != pipe || app.model.entries[1].Source != "stdin" {
+		t.Fatalf("Expected one entry from each stream, got %+v", app.model.entries)
+	}
+	app.model.Update(tea.WindowSizeMsg{Width: 160, Height: 30})
+	view := app.model.View()
+	if !strings.Contains(view, "SOURCE") {
+		t.Errorf("Expected a SOURCE column, got:\n%s", view)
+	}
+	for _, want := range []string{"app.err", "from stderr", "stdin", "from stdout"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in the rows, got:\n%s", want, view)
+		}
+	}
+
+	app.model.sourceInput.SetValue("app.err")
+	app.model.applyFilters()
+	if view := app.model.View(); !strings.Contains(view, "from stderr") || strings.Contains(view, "from stdout") {
+		t.Errorf("Expected the source filter to show only stderr, got:\n%s", view)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+)
+
+// printMatches runs the filters without the UI: the files, or stdin when
+// none are given, are parsed line by line and the lines passing the
+// include/exclude, level and time filters are written to w as they were
+// read, redacted with --redact. Like grep, lines are prefixed with their
+// source when there are several files. It returns how many lines matched
+func printMatches(config *Config, stdin io.Reader, w io.Writer) (int, error) {
+	model := NewUnifiedModel(config)
+	out := bufio.NewWriter(w)
+	prefix := len(config.Files) > 1
+
+	matched := 0
+	err := model.eachMatch(stdin, func(line string, entry LogEntry) {
+		if prefix {
+			fmt.Fprintf(out, "%s:", model.sourceLabel(entry.Source))
+		}
+		out.WriteString(model.redact(line) + "\n")
+		matched++
+	})
+	if flushErr := out.Flush(); err == nil {
+		err = flushErr
+	}
+	if model.summary != nil {
+		model.summary.write(summaryOut, model.displayZone)
+	}
+	return matched, err
+}
+
+// eachMatch parses the configured files, or stdin when none are given, line
+// by line and calls match with every line passing the filters and its entry
+func (m *UnifiedModel) eachMatch(stdin io.Reader, match func(line string, entry LogEntry)) error {
+	filter := m.entryFilter()
+	matchFrom := func(r io.Reader, source string) error {
+		reader := bufio.NewReaderSize(r, 64*1024)
+		for {
+			line, _, err := readLongLine(reader, 0)
+			if err == io.EOF {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			entry := m.parser.ParseLogLine(line, source)
+			passes := m.passes(entry, filter)
+			if m.summary != nil {
+				m.summary.add(entry, passes)
+			}
+			if passes {
+				match(line, entry)
+			}
+		}
+	}
+
+	if len(m.config.Files) == 0 {
+		return matchFrom(stdin, "stdin")
+	}
+
+	for _, file := range m.config.Files {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		err = matchFrom(f, file)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", file, err)
+		}
+	}
+	return nil
+}
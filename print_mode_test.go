@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPrintMatches(t *testing.T) {
+	dir := t.TempDir()
+	api := filepath.Join(dir, "api.log")
+	worker := filepath.Join(dir, "worker.log")
+	os.WriteFile(api, []byte("2023-12-23 15:30:45 ERROR: upstream timeout\n2023-12-23 15:30:46 INFO: served\n"), 0644)
+	os.WriteFile(worker, []byte("2023-12-23 15:31:00 ERROR: job timeout\n2023-12-23 15:31:01 WARN: retry timeout\n"), 0644)
+
+	var out bytes.Buffer
+	matched, err := printMatches(&Config{Timezone: "UTC", Files: []string{api, worker}, Include: "timeout", Levels: []LogLevel{ERROR}}, nil, &out)
+	if err != nil {
+		t.Fatalf("printMatches failed: %v", err)
+	}
+	expected := "api.log:2023-12-23 15:30:45 ERROR: upstream timeout\nworker.log:2023-12-23 15:31:00 ERROR: job timeout\n"
+	if matched != 2 || out.String() != expected {
+		t.Errorf("Expected 2 lines:\n%s\ngot %d:\n%s", expected, matched, out.String())
+	}
+
+	// Stdin, with the time window
+	out.Reset()
+	stdin := strings.NewReader("2023-12-23 15:30:45 INFO: early\n2023-12-23 15:31:00 INFO: late\n")
+	matched, err = printMatches(&Config{Timezone: "UTC", Since: "2023-12-23 15:30:50"}, stdin, &out)
+	if err != nil || matched != 1 || out.String() != "2023-12-23 15:31:00 INFO: late\n" {
+		t.Errorf("Expected the late line from stdin, got %d %q (%v)", matched, out.String(), err)
+	}
+
+	if _, err := printMatches(&Config{Timezone: "UTC", Files: []string{filepath.Join(dir, "missing.log")}}, nil, &out); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func TestPrintMatches_Redacted(t *testing.T) {
+	redactor, err := NewRedactor([]string{"email"})
+	if err != nil {
+		t.Fatalf("Failed to create redactor: %v", err)
+	}
+
+	var out bytes.Buffer
+	stdin := strings.NewReader("2023-12-23 15:30:45 ERROR: reset failed for jane@example.com\n")
+	if _, err := printMatches(&Config{Timezone: "UTC", Redactor: redactor}, stdin, &out); err != nil {
+		t.Fatalf("printMatches failed: %v", err)
+	}
+	if strings.Contains(out.String(), "jane@example.com") || !strings.Contains(out.String(), "***") {
+		t.Errorf("Expected the printed line redacted, got %q", out.String())
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+)
+
+// levelUnknown marks index entries whose level is only known after parsing
+const levelUnknown LogLevel = -1
+
+var (
+	quickErrorWords = [][]byte{[]byte("ERROR"), []byte("FATAL")}
+	quickWarnWords  = [][]byte{[]byte("WARN")}
+	quickDebugWords = [][]byte{[]byte("DEBUG"), []byte("TRACE")}
+)
+
+// chunkLevel detects the level of the line from lineStart to lineEnd when it
+// lies entirely inside the chunk read into buffer at chunkStart
+func (p *LogParser) chunkLevel(buffer []byte, chunkStart, lineStart, lineEnd int64) LogLevel {
+	// A registered parser may take any line
+	if lineStart < chunkStart || len(p.custom) > 0 {
+		return levelUnknown
+	}
+	line := buffer[lineStart-chunkStart : lineEnd-chunkStart]
+	level := quickDetectLevel(line)
+	if level == levelUnknown || len(p.levelKeywords) == 0 {
+		return level
+	}
+	// A plain text line, where configured keywords come first like in parsePlainText
+	if keywordLevel, ok := p.keywordLevel(string(line)); ok {
+		return keywordLevel
+	}
+	return level
+}
+
+// quickDetectLevel finds the level of a plain text line with the keyword scan
+// of parsePlainText, without parsing it. Lines that another parser may take,
+// or whose keywords it may move or strip, are left to a full parse
+func quickDetectLevel(line []byte) LogLevel {
+	trimmed := bytes.TrimLeft(line, " \t")
+	if len(trimmed) > 0 && strings.IndexByte("{[<(", trimmed[0]) >= 0 {
+		return levelUnknown // JSON, syslog or Rails
+	}
+	if eq := bytes.IndexByte(trimmed, '='); eq > 0 && bytes.IndexByte(trimmed[:eq], ' ') < 0 {
+		return levelUnknown // logfmt, whose level= and msg= fields are parsed
+	}
+	if bytes.IndexByte(line, 0x1b) >= 0 || bytes.Contains(line, []byte(" - - [")) || bytes.Contains(line, []byte(".go:")) {
+		return levelUnknown // ANSI codes, common log format or a Go caller
+	}
+	if bytes.Contains(line, []byte("CEF:")) {
+		return levelUnknown // CEF, whose severity sets the level
+	}
+	if isKlogHeader(trimmed) {
+		return levelUnknown // klog, whose severity letter sets the level
+	}
+
+	switch {
+	case containsAnyFold(line, quickErrorWords):
+		return ERROR
+	case containsAnyFold(line, quickWarnWords):
+		return WARN
+	case containsAnyFold(line, quickDebugWords):
+		return DEBUG
+	}
+	return INFO
+}
+
+// isKlogHeader reports whether line starts like a klog line, a severity
+// letter and the date, e.g. "E1223 "
+func isKlogHeader(line []byte) bool {
+	if len(line) < 6 || strings.IndexByte("IWEFD", line[0]) < 0 || line[5] != ' ' {
+		return false
+	}
+	for _, c := range line[1:5] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// containsAnyFold reports whether line contains one of the upper case words,
+// ignoring ASCII case
+func containsAnyFold(line []byte, words [][]byte) bool {
+	for i := range line {
+		for _, word := range words {
+			if len(line)-i >= len(word) && asciiEqualFold(line[i:i+len(word)], word) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// asciiEqualFold compares b with the upper case word, ignoring ASCII case
+func asciiEqualFold(b, word []byte) bool {
+	for i, c := range b {
+		if 'a' <= c && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		if c != word[i] {
+			return false
+		}
+	}
+	return true
+}
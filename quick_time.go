@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"time"
+)
+
+// quickTimeLayout is the leading timestamp quickDetectTime reads, the first
+// one extractTimestamp looks for
+const quickTimeLayout = "2006-01-02 15:04:05"
+
+// chunkTime records the leading timestamp of a line whose level was found by
+// chunkLevel, so the line is plain text, in Unix nanoseconds. Other lines
+// are left to a full parse (0)
+func (fi *FastIndexer) chunkTime(buffer []byte, chunkStart, lineStart, lineEnd int64, level LogLevel) int64 {
+	if level == levelUnknown || lineStart < chunkStart {
+		return 0
+	}
+	return quickDetectTime(buffer[lineStart-chunkStart:lineEnd-chunkStart], fi.zone)
+}
+
+// quickDetectTime reads a 2006-01-02 15:04:05 timestamp, optionally with
+// fractional seconds, at the start of a plain text line without parsing it.
+// extractTimestamp finds the same time for such a line, in zone
+func quickDetectTime(line []byte, zone *time.Location) int64 {
+	line = bytes.TrimLeft(line, " \t")
+	if len(line) < len(quickTimeLayout) || line[4] != '-' || line[7] != '-' || line[10] != ' ' || line[13] != ':' || line[16] != ':' {
+		return 0
+	}
+	end := len(quickTimeLayout)
+	if end+1 < len(line) && (line[end] == '.' || line[end] == ',') && isDigit(line[end+1]) {
+		for end++; end < len(line) && isDigit(line[end]); end++ {
+		}
+	}
+
+	t, err := time.ParseInLocation(quickTimeLayout, string(line[:end]), zone)
+	if err != nil {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
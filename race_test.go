@@ -0,0 +1,116 @@
+//go:build race
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Only built with go test -race: the model is driven through a running
+// program while lines stream in and a file is indexed off the event loop, so
+// any model state touched outside Update shows up as a data race
+
+func TestRace_ScrollWhileStreaming(t *testing.T) {
+	app := NewUnifiedApp(&Config{MaxLines: 500, RefreshRate: 1, Timezone: "UTC", Tail: defaultTail})
+	app.program = tea.NewProgram(app.model, tea.WithInput(nil), tea.WithOutput(&bytes.Buffer{}), tea.WithoutSignalHandler())
+	done := make(chan struct{})
+	go func() {
+		app.program.Run()
+		close(done)
+	}()
+
+	go app.streamFrom(strings.NewReader(strings.Join(numberedLines(2000), "\n")+"\n"), "stdin")
+	for i := 0; i < 300; i++ {
+		app.program.Send(keyMsg([]string{"up", "down", "k", "j", "G", "g"}[i%6]))
+	}
+	time.Sleep(50 * time.Millisecond)
+	app.program.Send(shutdownMsg{})
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		app.program.Kill()
+		t.Fatal("Timed out waiting for the program to quit")
+	}
+	if _, ok := app.model.indexer.(*StreamIndexer); !ok || app.model.totalLines == 0 {
+		t.Errorf("Expected streamed entries shown through the stream indexer, got %T with %d lines", app.model.indexer, app.model.totalLines)
+	}
+}
+
+func TestRace_ScrollWhileFileGrows(t *testing.T) {
+	path := writeTestLog(t, numberedLines(1000))
+	app := NewUnifiedApp(&Config{MaxLines: 500, Files: []string{path}, RefreshRate: 1, Timezone: "UTC", Tail: defaultTail})
+	app.program = tea.NewProgram(app.model, tea.WithInput(nil), tea.WithOutput(&bytes.Buffer{}), tea.WithoutSignalHandler())
+	done := make(chan struct{})
+	go func() {
+		app.program.Run()
+		close(done)
+	}()
+	app.indexFile(path)
+
+	// Lines are appended and reported like the watcher does, while the
+	// view scrolls and loads the visible lines
+	appended := make(chan struct{})
+	go func() {
+		defer close(appended)
+		file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return
+		}
+		defer file.Close()
+		for i := 0; i < 200; i++ {
+			file.WriteString("2023-12-23 15:40:00 WARN: appended\n")
+			app.program.Send(fileChangedMsg{filename: path})
+		}
+	}()
+	for i := 0; i < 300; i++ {
+		app.program.Send(keyMsg([]string{"up", "down", "k", "G", "g", "j"}[i%6]))
+	}
+	<-appended
+	time.Sleep(100 * time.Millisecond)
+	app.program.Send(shutdownMsg{})
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		app.program.Kill()
+		t.Fatal("Timed out waiting for the program to quit")
+	}
+	if app.model.totalLines != 1200 {
+		t.Errorf("Expected every appended line indexed, got %d lines", app.model.totalLines)
+	}
+}
+
+func TestRace_ScrollWhileIndexing(t *testing.T) {
+	path := writeTestLog(t, numberedLines(5000))
+	app := NewUnifiedApp(&Config{MaxLines: 500, Files: []string{path}, RefreshRate: 1, Timezone: "UTC", Tail: defaultTail})
+	app.program = tea.NewProgram(app.model, tea.WithInput(nil), tea.WithOutput(&bytes.Buffer{}), tea.WithoutSignalHandler())
+	done := make(chan struct{})
+	go func() {
+		app.program.Run()
+		close(done)
+	}()
+
+	go app.indexFile(path)
+	for i := 0; i < 300; i++ {
+		app.program.Send(keyMsg([]string{"up", "down", "G", "g"}[i%4]))
+		if i == 150 {
+			app.program.Send(keyMsg("r")) // Re-index in the background
+		}
+	}
+	time.Sleep(100 * time.Millisecond)
+	app.program.Send(shutdownMsg{})
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		app.program.Kill()
+		t.Fatal("Timed out waiting for the program to quit")
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	rateWindow = 5 * time.Second // Lines counted for the rate
+	rateIdle   = 2 * time.Second // Without new lines for this long the rate drops to 0
+)
+
+// rateSample is a batch of lines received at once
+type rateSample struct {
+	at    time.Time
+	lines int
+}
+
+// rateMeter tracks how fast lines arrive over the last rateWindow
+type rateMeter struct {
+	samples []rateSample
+}
+
+// add records lines received at now, forgetting batches out of the window
+func (r *rateMeter) add(now time.Time, lines int) {
+	if lines <= 0 {
+		return
+	}
+	kept := r.samples[:0]
+	for _, sample := range r.samples {
+		if now.Sub(sample.at) < rateWindow {
+			kept = append(kept, sample)
+		}
+	}
+	r.samples = append(kept, rateSample{at: now, lines: lines})
+}
+
+// perSecond returns the lines per second over the window, or 0 once no
+// lines came for rateIdle
+func (r *rateMeter) perSecond(now time.Time) float64 {
+	if len(r.samples) == 0 || now.Sub(r.samples[len(r.samples)-1].at) > rateIdle {
+		return 0
+	}
+
+	lines := 0
+	oldest := now
+	for _, sample := range r.samples {
+		if now.Sub(sample.at) < rateWindow {
+			lines += sample.lines
+			if sample.at.Before(oldest) {
+				oldest = sample.at
+			}
+		}
+	}
+	// A single batch isn't spread over less than a second
+	span := max(int(now.Sub(oldest)/time.Millisecond), 1000)
+	return float64(lines) * 1000 / float64(span)
+}
+
+// formatRate formats a lines per second rate, e.g. 12/s, 1.2k/s or 3.4M/s
+func formatRate(rate float64) string {
+	switch {
+	case rate >= 1e6:
+		return fmt.Sprintf("%.1fM/s", rate/1e6)
+	case rate >= 1e3:
+		return fmt.Sprintf("%.1fk/s", rate/1e3)
+	case rate >= 10:
+		return fmt.Sprintf("%.0f/s", rate)
+	default:
+		return fmt.Sprintf("%.1f/s", rate)
+	}
+}
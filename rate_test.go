@@ -0,0 +1,54 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRateMeter_RollingWindow(t *testing.T) {
+	var meter rateMeter
+	start := time.Date(2023, 12, 23, 15, 30, 0, 0, time.UTC)
+
+	if rate := meter.perSecond(start); rate != 0 {
+		t.Fatalf("Expected no rate before any lines, got %v", rate)
+	}
+
+	// 1,200 lines a second for four seconds
+	for i := 0; i <= 4; i++ {
+		meter.add(start.Add(time.Duration(i)*time.Second), 1200)
+	}
+	now := start.Add(4 * time.Second)
+	if got := formatRate(meter.perSecond(now)); got != "1.5k/s" {
+		t.Errorf("Expected 6,000 lines over 4s, got %s", got)
+	}
+
+	// Batches older than the window stop counting
+	meter.add(start.Add(10*time.Second), 30)
+	if got := formatRate(meter.perSecond(start.Add(10 * time.Second))); got != "30/s" {
+		t.Errorf("Expected only the last batch to count, got %s", got)
+	}
+	if len(meter.samples) != 1 {
+		t.Errorf("Expected old batches to be forgotten, kept %d", len(meter.samples))
+	}
+
+	// Quiet for longer than rateIdle
+	if rate := meter.perSecond(start.Add(13 * time.Second)); rate != 0 {
+		t.Errorf("Expected the rate to drop to 0 when lines stop, got %v", rate)
+	}
+}
+
+func TestRateMeter_ShownInHeader(t *testing.T) {
+	model := NewUnifiedModel(&Config{MaxLines: 100})
+	model.Update(tea.WindowSizeMsg{Width: 160, Height: 30})
+	if strings.Contains(model.renderHeader(), "Rate:") {
+		t.Error("Expected no rate before lines arrive")
+	}
+
+	model.AddLogBatch([]LogEntry{{Message: "a"}, {Message: "b"}, {Message: "c"}})
+	if header := model.renderHeader(); !strings.Contains(header, "Rate: 3.0/s") {
+		t.Errorf("Expected the header to show the rate, got %q", header)
+	}
+}
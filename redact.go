@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// redactMask replaces every redacted match
+const redactMask = "***"
+
+// redactPresets are named patterns usable with --redact
+var redactPresets = map[string]string{
+	"email":      `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`,
+	"ipv4":       `\b(?:(?:25[0-5]|2[0-4]\d|1?\d?\d)\.){3}(?:25[0-5]|2[0-4]\d|1?\d?\d)\b`,
+	"jwt":        `\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`,
+	"creditcard": `\b(?:\d[ -]?){12,18}\d\b`,
+}
+
+// Redactor scrubs sensitive data from messages before they are shown or exported
+type Redactor struct {
+	patterns []*regexp.Regexp
+}
+
+// NewRedactor compiles each spec, which is either a preset name or a regex
+func NewRedactor(specs []string) (*Redactor, error) {
+	r := &Redactor{}
+	for _, spec := range specs {
+		if spec == "" {
+			continue
+		}
+		pattern := spec
+		if preset, ok := redactPresets[spec]; ok {
+			pattern = preset
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact pattern %q: %w", spec, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// redact replaces every match of every pattern with the mask
+func (r *Redactor) redact(msg string) string {
+	if r == nil {
+		return msg
+	}
+	for _, re := range r.patterns {
+		msg = re.ReplaceAllString(msg, redactMask)
+	}
+	return msg
+}
@@ -0,0 +1,54 @@
+package main
+
+import "time"
+
+const (
+	// ticksPerRefresh is how many redraw ticks make up one refresh period,
+	// so the default -r 1 redraws every 50ms
+	ticksPerRefresh = 20
+
+	// minTickInterval and maxTickInterval bound the redraw tick: a tiny or
+	// zero refresh rate mustn't spin the event loop, and a long one mustn't
+	// leave the view stale while tailing
+	minTickInterval = 10 * time.Millisecond
+	maxTickInterval = 500 * time.Millisecond
+
+	// defaultFlushInterval is how long streamed lines wait for a batch of
+	// 100 before being sent anyway; minFlushInterval is the floor
+	defaultFlushInterval = 20 * time.Millisecond
+	minFlushInterval     = time.Millisecond
+)
+
+// tickInterval is how often the model redraws and checks for work, derived
+// from the refresh rate in seconds
+func tickInterval(refreshRate float64) time.Duration {
+	interval := time.Duration(refreshRate * float64(time.Second) / ticksPerRefresh)
+	if interval < minTickInterval {
+		return minTickInterval
+	}
+	if interval > maxTickInterval {
+		return maxTickInterval
+	}
+	return interval
+}
+
+// pollInterval is how often an unfollowed file is checked for changes
+// (0 = never). Checks happen on ticks, so never more often than those
+func pollInterval(refreshRate float64) time.Duration {
+	if refreshRate <= 0 {
+		return 0
+	}
+	return time.Duration(refreshRate * float64(time.Second))
+}
+
+// flushInterval is the configured batch flush interval (0 = the default),
+// raised to the floor so a stream doesn't wake the model for every line
+func flushInterval(interval time.Duration) time.Duration {
+	if interval == 0 {
+		return defaultFlushInterval
+	}
+	if interval < minFlushInterval {
+		return minFlushInterval
+	}
+	return interval
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTickInterval_FollowsRefreshRate(t *testing.T) {
+	for _, tc := range []struct {
+		rate float64
+		want time.Duration
+	}{
+		{1, 50 * time.Millisecond},
+		{0.5, 25 * time.Millisecond},
+		{5, 250 * time.Millisecond},
+		{0, minTickInterval}, // No busy loop
+		{0.001, minTickInterval},
+		{-1, minTickInterval},
+		{60, maxTickInterval},
+	} {
+		if got := tickInterval(tc.rate); got != tc.want {
+			t.Errorf("tickInterval(%v) = %v, want %v", tc.rate, got, tc.want)
+		}
+	}
+
+	if pollInterval(0) != 0 || pollInterval(-1) != 0 {
+		t.Error("Expected no file checks without a refresh rate")
+	}
+	if pollInterval(2.5) != 2500*time.Millisecond {
+		t.Errorf("Expected checks every 2.5s, got %v", pollInterval(2.5))
+	}
+}
+
+func TestFlushInterval_Bounds(t *testing.T) {
+	if flushInterval(0) != defaultFlushInterval {
+		t.Errorf("Expected the default when unset, got %v", flushInterval(0))
+	}
+	if flushInterval(time.Microsecond) != minFlushInterval || flushInterval(-time.Second) != minFlushInterval {
+		t.Error("Expected tiny intervals raised to the floor")
+	}
+	if flushInterval(100*time.Millisecond) != 100*time.Millisecond {
+		t.Errorf("Expected the configured interval, got %v", flushInterval(100*time.Millisecond))
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// reloadFile indexes the file again from scratch, for when it was rewritten
+// rather than appended to. A missing file leaves the loaded lines in place
+func (m *UnifiedModel) reloadFile() tea.Cmd {
+	if m.indexer == nil || m.indexing {
+		return nil
+	}
+	if _, ok := m.indexer.(*MergedIndexer); ok {
+		m.notice = "r reloads a single file, not a merged timeline"
+		return nil
+	}
+	if stream, ok := m.indexer.(*StreamIndexer); ok && stream.base == nil {
+		m.notice = "r reloads a file, not streamed input"
+		return nil
+	}
+
+	filename := m.loadingFile
+	if _, err := os.Stat(filename); err != nil {
+		m.notice = fmt.Sprintf("Reload %s: %v", m.sourceLabel(filename), err)
+		return nil
+	}
+
+	m.notice = ""
+	m.viewportStart = 0
+	m.selectedIdx = 0
+	return m.reindexFile(filename)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// startSearch opens the search prompt over the log stream. Unlike the include
+// filter, searching keeps every row and only moves between matches
+func (m *UnifiedModel) startSearch() tea.Cmd {
+	m.searching = true
+	m.searchInput.SetValue(m.searchQuery)
+	m.searchInput.CursorEnd()
+	m.searchInput.Focus()
+	return textinput.Blink
+}
+
+// updateSearch handles keys while the search prompt is open, searching as
+// the query is typed. Enter keeps the query for n/N, Esc clears it
+func (m *UnifiedModel) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.searching = false
+		m.searchInput.Blur()
+		m.setSearchQuery("")
+		return m, nil
+	case "enter":
+		m.searching = false
+		m.searchInput.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.searchInput, cmd = m.searchInput.Update(msg)
+	if m.searchInput.Value() != m.searchQuery {
+		m.setSearchQuery(m.searchInput.Value())
+	}
+	return m, cmd
+}
+
+// setSearchQuery finds the query in the filtered entries and jumps to the
+// first match at or after the selection
+func (m *UnifiedModel) setSearchQuery(query string) {
+	m.searchQuery = query
+	m.findSearchMatches()
+	if len(m.searchMatches) == 0 {
+		return
+	}
+
+	current := m.viewportStart + m.selectedIdx
+	for i, pos := range m.searchMatches {
+		if pos >= current {
+			m.searchIdx = i
+			m.jumpToPosition(pos)
+			return
+		}
+	}
+	m.jumpToPosition(m.searchMatches[0])
+}
+
+// findSearchMatches records the filtered positions whose message matches the
+// search query, using the regex and case options of the filters
+func (m *UnifiedModel) findSearchMatches() {
+	m.searchMatches = nil
+	m.searchIdx = 0
+	m.addSearchMatches(0)
+}
+
+// addSearchMatches records the search matches from filtered position first on
+func (m *UnifiedModel) addSearchMatches(first int) {
+	if m.searchQuery == "" || m.indexer == nil {
+		return
+	}
+
+	for pos := first; pos < len(m.filteredIndices); pos++ {
+		line := m.filteredIndices[pos]
+		entries, err := m.indexer.GetLineRange(line, line+1)
+		if err == nil && len(entries) > 0 && m.matchesPattern(entries[0].Message, m.searchQuery) {
+			m.searchMatches = append(m.searchMatches, pos)
+		}
+	}
+}
+
+// stepSearch moves to the next (1) or previous (-1) search match, wrapping around
+func (m *UnifiedModel) stepSearch(step int) {
+	if len(m.searchMatches) == 0 {
+		return
+	}
+	m.searchIdx = (m.searchIdx + step + len(m.searchMatches)) % len(m.searchMatches)
+	m.jumpToPosition(m.searchMatches[m.searchIdx])
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// sortMode orders the filtered entries, cycled with o
+type sortMode int
+
+const (
+	sortNone     sortMode = iota // File order
+	sortTime                     // Newest first
+	sortDuration                 // Slowest first
+	sortLevel                    // Most severe first
+	sortModeCount
+)
+
+var sortModeNames = []string{"none", "time", "duration", "level"}
+
+func (s sortMode) String() string {
+	return sortModeNames[s]
+}
+
+// durationWidth is the width of the DURATION column, e.g. "1234.5ms"
+const durationWidth = 9
+
+// entryDuration returns the duration_ms found by the Rails parser or logged
+// as a field, in milliseconds
+func entryDuration(entry LogEntry) (float64, bool) {
+	value, ok := entry.Metadata["duration_ms"]
+	if !ok || value == nil {
+		return 0, false
+	}
+	ms, err := strconv.ParseFloat(fmt.Sprint(value), 64)
+	if err != nil {
+		return 0, false
+	}
+	return ms, true
+}
+
+// formatDuration fits a duration in milliseconds into the DURATION column
+func formatDuration(ms float64) string {
+	if ms >= 1e5 {
+		return fmt.Sprintf("%.0fs", ms/1000)
+	}
+	return fmt.Sprintf("%.1fms", ms)
+}
+
+// cycleSort moves to the next sort mode and reorders the view, keeping the
+// selected entry selected. The header names the sort while there is one
+func (m *UnifiedModel) cycleSort() {
+	selected, ok := m.selectedLine()
+	m.sortMode = (m.sortMode + 1) % sortModeCount
+	if m.sortMode == sortDuration {
+		m.showDuration = true
+	}
+	if len(m.filteredIndices) == 0 {
+		return
+	}
+
+	// A sorted view has no bottom to follow
+	m.followMatches = false
+	m.sortFiltered()
+	m.findSearchMatches()
+	if !ok {
+		m.loadVisibleLines()
+		return
+	}
+	for pos, line := range m.filteredIndices {
+		if line == selected {
+			m.jumpToPosition(pos)
+			return
+		}
+	}
+}
+
+// sortFiltered reorders filteredIndices by the sort mode, back to file order
+// for sortNone. Entries without the key sort last, and ties keep file order
+func (m *UnifiedModel) sortFiltered() {
+	matched := m.matchedLines()
+	sort.Ints(m.filteredIndices)
+	if m.sortMode != sortNone {
+		type sortKey struct {
+			line  int
+			value float64
+			known bool
+		}
+		keys := make([]sortKey, len(m.filteredIndices))
+		for i, line := range m.filteredIndices {
+			keys[i] = sortKey{line: line}
+			if m.sortMode == sortLevel {
+				level, known := m.lineLevel(line)
+				keys[i].value, keys[i].known = float64(level), known
+				continue
+			}
+			entries, err := m.indexer.GetLineRange(line, line+1)
+			if err != nil || len(entries) == 0 {
+				continue
+			}
+			switch m.sortMode {
+			case sortTime:
+				keys[i].value, keys[i].known = float64(entries[0].Time.UnixNano()), !entries[0].Time.IsZero()
+			case sortDuration:
+				keys[i].value, keys[i].known = entryDuration(entries[0])
+			}
+		}
+		sort.SliceStable(keys, func(i, j int) bool {
+			if keys[i].known != keys[j].known {
+				return keys[i].known
+			}
+			return keys[i].value > keys[j].value
+		})
+		for i, key := range keys {
+			m.filteredIndices[i] = key.line
+		}
+	}
+
+	m.setMatchedLines(matched)
+}
+
+// matchedLines returns the file lines of matchedIndices, which are positions
+// and so don't survive filteredIndices being reordered or shortened
+func (m *UnifiedModel) matchedLines() map[int]bool {
+	matched := make(map[int]bool, len(m.matchedIndices))
+	for _, pos := range m.matchedIndices {
+		matched[m.filteredIndices[pos]] = true
+	}
+	return matched
+}
+
+// setMatchedLines points matchedIndices at the positions of the matched lines
+func (m *UnifiedModel) setMatchedLines(matched map[int]bool) {
+	m.matchedIndices = m.matchedIndices[:0]
+	for pos, line := range m.filteredIndices {
+		if matched[line] {
+			m.matchedIndices = append(m.matchedIndices, pos)
+		}
+	}
+}
+
+// nearestPosition returns the position of line target in the view, or of the
+// first line after it shown, or else the last line shown
+func (m *UnifiedModel) nearestPosition(target int) int {
+	if m.sortMode == sortNone {
+		return min(sort.SearchInts(m.filteredIndices, target), len(m.filteredIndices)-1)
+	}
+	nearest, last := -1, 0
+	for pos, line := range m.filteredIndices {
+		if line >= target && (nearest < 0 || line < m.filteredIndices[nearest]) {
+			nearest = pos
+		}
+		if line > m.filteredIndices[last] {
+			last = pos
+		}
+	}
+	if nearest < 0 {
+		return last
+	}
+	return nearest
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSortMode_CyclesAndKeepsSelection(t *testing.T) {
+	lines := []string{
+		"  (1.5ms)  SELECT * FROM users",
+		"2023-12-23 15:30:45 ERROR: request failed",
+		"  (250.0ms)  SELECT * FROM orders",
+		"  (12.25ms)  SELECT * FROM items",
+		"2023-12-23 15:30:46 WARN: slow request",
+	}
+	model := newIndexedTestModel(t, lines, 120, 30)
+	model.scrollToTop()
+	model.scrollDown() // The ERROR line
+
+	order := func() string {
+		var lines []string
+		for _, line := range model.filteredIndices {
+			lines = append(lines, string(rune('0'+line)))
+		}
+		return strings.Join(lines, "")
+	}
+
+	model.Update(keyMsg("o"))
+	if model.sortMode != sortTime {
+		t.Fatalf("Expected time sort first, got %v", model.sortMode)
+	}
+
+	model.Update(keyMsg("o"))
+	if got := order(); got != "23014" {
+		t.Errorf("Expected the slowest queries first and lines without a duration last, got %s", got)
+	}
+	if line, _ := model.selectedLine(); line != 1 {
+		t.Errorf("Expected the ERROR line to stay selected, got line %d", line)
+	}
+	if !model.showDuration || !strings.Contains(model.View(), "250.0ms") {
+		t.Error("Expected the DURATION column while sorting by duration")
+	}
+	if !strings.Contains(model.renderHeader(), "Sorted by duration") {
+		t.Error("Expected the header to name the sort")
+	}
+
+	model.Update(keyMsg("o"))
+	if got := order(); !strings.HasPrefix(got, "14") {
+		t.Errorf("Expected ERROR then WARN first, got %s", got)
+	}
+
+	// Filtering again keeps the sort, and its end goes back to file order
+	model.includeInput.SetValue("SELECT")
+	model.applyFilters()
+	if model.sortMode != sortLevel || len(model.filteredIndices) != 3 {
+		t.Errorf("Expected the level sort kept over 3 lines, got %v over %d", model.sortMode, len(model.filteredIndices))
+	}
+	model.Update(keyMsg("o"))
+	if got := order(); got != "023" {
+		t.Errorf("Expected file order again, got %s", got)
+	}
+}
+
+func TestEntryDuration(t *testing.T) {
+	for _, tc := range []struct {
+		value interface{}
+		ms    float64
+		ok    bool
+	}{
+		{"0.3", 0.3, true},
+		{12.5, 12.5, true},
+		{int64(40), 40, true},
+		{"slow", 0, false},
+		{nil, 0, false},
+	} {
+		entry := LogEntry{Metadata: map[string]interface{}{"duration_ms": tc.value}}
+		if ms, ok := entryDuration(entry); ms != tc.ms || ok != tc.ok {
+			t.Errorf("%v: expected %v %v, got %v %v", tc.value, tc.ms, tc.ok, ms, ok)
+		}
+	}
+	if _, ok := entryDuration(LogEntry{}); ok {
+		t.Error("Expected no duration without metadata")
+	}
+}
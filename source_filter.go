@@ -0,0 +1,153 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// filterPattern is one comma-separated include/exclude pattern. A prefix
+// naming a source, as in "service-a:ERROR", scopes it to that source
+type filterPattern struct {
+	source  string
+	pattern string
+}
+
+// appliesTo reports whether the pattern is checked against entries from source
+func (p filterPattern) appliesTo(source string) bool {
+	return p.source == "" || sourceMatches(p.source, source)
+}
+
+// sourceMatches reports whether name refers to source by its full path,
+// base name or base name without extension
+func sourceMatches(name, source string) bool {
+	base := filepath.Base(source)
+	return name == source || name == base || name == strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// parseFilterPatterns splits a filter input into patterns. A "name:" prefix
+// only scopes the pattern when name is one of the loaded sources, so patterns
+// like "ERROR:" or "http://" keep matching the message as before
+func (m *UnifiedModel) parseFilterPatterns(value string) []filterPattern {
+	var patterns []filterPattern
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		pattern := filterPattern{pattern: part}
+		if name, rest, found := strings.Cut(part, ":"); found && rest != "" && m.isSource(name) {
+			// A label stands for the file it names
+			if file, ok := m.sourceByLabel(name); ok {
+				name = file
+			}
+			pattern = filterPattern{source: name, pattern: rest}
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns
+}
+
+// isSource reports whether name refers to one of the loaded or tailed files,
+// by path or label, or stdin
+func (m *UnifiedModel) isSource(name string) bool {
+	if name == "stdin" {
+		return true
+	}
+	for _, files := range [][]string{m.config.Files, m.config.AlsoTail} {
+		for _, file := range files {
+			if sourceMatches(name, file) || m.sourceLabel(file) == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// splitSourceFilter splits the source filter input into its patterns
+func splitSourceFilter(value string) []string {
+	var patterns []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			patterns = append(patterns, part)
+		}
+	}
+	return patterns
+}
+
+// matchesSource reports whether the entry's source passes the source filter:
+// any pattern matching its path or label, with the regex and case options of
+// the include patterns. No patterns let every source through
+func (m *UnifiedModel) matchesSource(entry LogEntry, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	label := m.sourceLabel(entry.Source)
+	for _, pattern := range patterns {
+		if m.matchesPattern(entry.Source, pattern) || m.matchesPattern(label, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// componentPattern returns the pattern of a component:pattern filter, which
+// matches the entry's component instead of its message
+func componentPattern(pattern string) (string, bool) {
+	rest, found := strings.CutPrefix(pattern, "component:")
+	return rest, found && rest != ""
+}
+
+// matchesEntry reports whether a pattern matches the entry's message. A
+// has:key or !has:key pattern checks the entry's metadata instead, and a
+// component:pattern one its component
+func (m *UnifiedModel) matchesEntry(entry LogEntry, pattern string) bool {
+	if key, present, ok := metadataPredicate(pattern); ok {
+		return hasMetadata(entry, key) == present
+	}
+	if component, ok := componentPattern(pattern); ok {
+		return entry.Component != "" && m.matchesPattern(entry.Component, component)
+	}
+	return m.matchesPattern(entry.Message, pattern)
+}
+
+// excludes reports whether an exclude pattern for the entry's source matches it
+func (m *UnifiedModel) excludes(entry LogEntry, patterns []filterPattern) bool {
+	for _, p := range patterns {
+		if p.appliesTo(entry.Source) && m.matchesEntry(entry, p.pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// includes reports whether the entry passes the include patterns for its
+// source, and whether they matched rather than none applying. Any pattern
+// matching will do, or with Match All every one of them must. Entries no
+// pattern applies to pass unmatched, unless Match Only drops them
+func (m *UnifiedModel) includes(entry LogEntry, patterns []filterPattern) (pass, matched bool) {
+	pass, matched = m.includePatterns(entry, patterns)
+	if m.matchOnly && len(patterns) > 0 && !matched {
+		pass = false
+	}
+	return pass, matched
+}
+
+// includePatterns checks the include patterns for includes
+func (m *UnifiedModel) includePatterns(entry LogEntry, patterns []filterPattern) (pass, matched bool) {
+	applicable := false
+	for _, p := range patterns {
+		if !p.appliesTo(entry.Source) {
+			continue
+		}
+		applicable = true
+		if m.matchesEntry(entry, p.pattern) != m.matchAll {
+			// The first match passes with any, the first miss fails with all
+			return !m.matchAll, !m.matchAll
+		}
+	}
+	if m.matchAll {
+		return true, applicable
+	}
+	return !applicable, false
+}
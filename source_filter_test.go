@@ -0,0 +1,190 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSourceFilter_ScopesPatternsToSources(t *testing.T) {
+	model := NewUnifiedModel(&Config{
+		MaxLines:    100,
+		RefreshRate: 1,
+		Timezone:    "UTC",
+		Files:       []string{"/var/log/service-a.log", "/var/log/service-b.log"},
+		Include:     "service-a:ERROR, http://",
+		Exclude:     "service-b.log:healthcheck",
+	})
+
+	entries := []LogEntry{
+		{Source: "/var/log/service-a.log", Message: "ERROR payment failed"},
+		{Source: "/var/log/service-a.log", Message: "INFO payment ok"},
+		{Source: "/var/log/service-a.log", Message: "GET http://example.com"},
+		{Source: "/var/log/service-b.log", Message: "INFO order created"},
+		{Source: "/var/log/service-b.log", Message: "GET /healthcheck"},
+	}
+
+	// Service B has no scoped include, but the unscoped "http://" still applies
+	model.AddLogBatch(entries)
+	var shown []string
+	for _, entry := range model.filteredEntries {
+		shown = append(shown, entry.Message)
+	}
+	expected := []string{"ERROR payment failed", "GET http://example.com"}
+	if len(shown) != len(expected) || shown[0] != expected[0] || shown[1] != expected[1] {
+		t.Errorf("Expected %q, got %q", expected, shown)
+	}
+
+	// Without unscoped includes, other sources pass untouched
+	model.includeInput.SetValue("service-a:ERROR")
+	model.applyFilters()
+	if len(model.filteredEntries) != 2 || model.filteredEntries[1].Message != "INFO order created" {
+		t.Errorf("Expected service A errors and all of service B but the health check, got %v", model.filteredEntries)
+	}
+}
+
+func TestParseFilterPatterns_OnlyKnownSourcesScope(t *testing.T) {
+	model := NewUnifiedModel(&Config{Timezone: "UTC", Files: []string{"logs/api.log"}})
+
+	patterns := model.parseFilterPatterns("api:timeout, ERROR: disk, stdin:panic, ,logs/api.log:slow")
+	expected := []filterPattern{
+		{source: "api", pattern: "timeout"},
+		{pattern: "ERROR: disk"},
+		{source: "stdin", pattern: "panic"},
+		{source: "logs/api.log", pattern: "slow"},
+	}
+	if len(patterns) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, patterns)
+	}
+	for i := range expected {
+		if patterns[i] != expected[i] {
+			t.Errorf("Pattern %d: expected %v, got %v", i, expected[i], patterns[i])
+		}
+	}
+}
+
+func TestSourceFilter_MatchesComponent(t *testing.T) {
+	model := NewUnifiedModel(&Config{
+		MaxLines:    100,
+		RefreshRate: 1,
+		Timezone:    "UTC",
+		Include:     "component:http.server",
+		Exclude:     "component:migrations",
+	})
+
+	model.AddLogBatch([]LogEntry{
+		{Component: "http.server", Message: "listening"},
+		{Component: "db", Message: "connected"},
+		{Component: "db", Message: "GET /health from http.server"},
+		{Component: "http.server", Message: "migrations done"},
+		{Component: "migrations", Message: "http.server table created"},
+	})
+	if len(model.filteredEntries) != 2 || model.filteredEntries[0].Message != "listening" || model.filteredEntries[1].Message != "migrations done" {
+		t.Errorf("Expected only the http.server component, got %v", model.filteredEntries)
+	}
+
+	// Plain patterns keep matching the message only
+	model.includeInput.SetValue("http.server")
+	model.excludeInput.SetValue("")
+	model.applyFilters()
+	if len(model.filteredEntries) != 2 || model.filteredEntries[0].Message != "GET /health from http.server" {
+		t.Errorf("Expected only the message matches, got %v", model.filteredEntries)
+	}
+}
+
+func TestMetadataFilter_HasPredicates(t *testing.T) {
+	model := NewUnifiedModel(&Config{MaxLines: 100, RefreshRate: 1, Timezone: "UTC", Include: "has:trace.id", Exclude: "!has:status_code"})
+
+	entries := []LogEntry{
+		{Message: "flat", Metadata: map[string]interface{}{"trace.id": "abc", "status_code": 200}},
+		{Message: "nested", Metadata: map[string]interface{}{"trace": map[string]interface{}{"id": "def"}, "status_code": 500}},
+		{Message: "no status", Metadata: map[string]interface{}{"trace.id": "ghi"}},
+		{Message: "no trace", Metadata: map[string]interface{}{"trace": "jkl", "status_code": 200}},
+		{Message: "has:trace.id in text"},
+	}
+
+	model.AddLogBatch(entries)
+	var shown []string
+	for _, entry := range model.filteredEntries {
+		shown = append(shown, entry.Message)
+	}
+	expected := []string{"flat", "nested"}
+	if len(shown) != len(expected) || shown[0] != expected[0] || shown[1] != expected[1] {
+		t.Errorf("Expected %q, got %q", expected, shown)
+	}
+}
+
+func TestSourceFilter_OnlyShowsMatchingSources(t *testing.T) {
+	model := NewUnifiedModel(&Config{
+		MaxLines:     100,
+		Timezone:     "UTC",
+		Files:        []string{"/var/log/api.log", "/var/log/worker.log"},
+		SourceFilter: "API",
+	})
+
+	model.AddLogBatch([]LogEntry{
+		{Source: "/var/log/api.log", Message: "request served"},
+		{Source: "/var/log/worker.log", Message: "job done"},
+		{Source: "/var/log/api.log", Message: "request failed"},
+	})
+	if len(model.filteredEntries) != 2 || model.filteredEntries[1].Message != "request failed" {
+		t.Errorf("Expected only the api entries, got %v", model.filteredEntries)
+	}
+
+	// Labels match too, and any of several patterns will do
+	model.sourceLabels = map[string]string{"/var/log/worker.log": "jobs"}
+	model.sourceInput.SetValue("nothing, jobs")
+	model.applyFilters()
+	if len(model.filteredEntries) != 1 || model.filteredEntries[0].Message != "job done" {
+		t.Errorf("Expected the entry of the labelled source, got %v", model.filteredEntries)
+	}
+	if state := model.filterState(); state.Source != "nothing, jobs" {
+		t.Errorf("Expected the source filter to be saved, got %q", state.Source)
+	}
+}
+
+func TestMatchOnly_DropsUnmatchedLines(t *testing.T) {
+	dir := t.TempDir()
+	api := filepath.Join(dir, "api.log")
+	worker := filepath.Join(dir, "worker.log")
+	os.WriteFile(api, []byte("2023-12-23 15:30:01 ERROR: upstream timeout\n2023-12-23 15:30:03 INFO: served\n"), 0644)
+	os.WriteFile(worker, []byte("2023-12-23 15:30:02 INFO: job done\n2023-12-23 15:30:04 ERROR: job failed\n"), 0644)
+
+	parser := NewLogParser("UTC")
+	var indexers []*FastIndexer
+	for _, file := range []string{api, worker} {
+		indexer, err := NewFastIndexer(file, parser)
+		if err != nil || indexer.IndexFileUltraFast() != nil {
+			t.Fatalf("Failed to index %s: %v", file, err)
+		}
+		indexers = append(indexers, indexer)
+	}
+	model := NewUnifiedModel(&Config{Timezone: "UTC", RefreshRate: 1, Merge: true, Files: []string{api, worker}, Include: "api:ERROR"})
+	model.SetIndexer(NewMergedIndexer(indexers), api)
+	defer model.indexer.Close()
+	model.tailing = false
+
+	// The worker's lines pass the api-only pattern without matching it
+	if !reflect.DeepEqual(model.filteredIndices, []int{0, 1, 3}) || !reflect.DeepEqual(model.matchedIndices, []int{0}) {
+		t.Fatalf("Expected the api error and the worker's lines, got %v matching %v", model.filteredIndices, model.matchedIndices)
+	}
+	model.currentMatchIdx = 2
+
+	model.focus = LeftPanel
+	model.leftPanelItem = matchOnlyItem
+	model.Update(keyMsg("enter"))
+	if !reflect.DeepEqual(model.filteredIndices, []int{0}) || !reflect.DeepEqual(model.matchedIndices, []int{0}) || model.currentMatchIdx != 0 {
+		t.Errorf("Expected only the api error, every line a match, got %v matching %v at %d", model.filteredIndices, model.matchedIndices, model.currentMatchIdx)
+	}
+	if state := model.filterState(); !state.MatchOnly {
+		t.Error("Expected Match Only to be saved")
+	}
+
+	// Without include patterns there is nothing to match, so nothing is dropped
+	model.includeInput.SetValue("")
+	model.applyFilters()
+	if len(model.filteredIndices) != 4 {
+		t.Errorf("Expected every line without patterns, got %v", model.filteredIndices)
+	}
+}
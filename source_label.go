@@ -0,0 +1,111 @@
+package main
+
+import (
+	"hash/fnv"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	// /var/lib/docker/containers/<id>/<id>-json.log
+	dockerLogRegex = regexp.MustCompile(`/containers/([0-9a-f]{12,})/[0-9a-f]+-json\.log$`)
+	// /var/log/pods/<namespace>_<pod>_<uid>/<container>/0.log
+	podLogRegex = regexp.MustCompile(`/pods/[^_/]+_([^_/]+)_[^/]+/([^/]+)/\d+\.log$`)
+	// /var/log/containers/<pod>_<namespace>_<container>-<id>.log
+	containerLogRegex = regexp.MustCompile(`/containers/([^_/]+)_[^_/]+_(.+)-[0-9a-f]{64}\.log$`)
+)
+
+// defaultSourceLabel names a source when it wasn't renamed: pod/container
+// for Kubernetes logs, the short container id for Docker logs and the base
+// name for any other file
+func defaultSourceLabel(source string) string {
+	if matches := podLogRegex.FindStringSubmatch(source); matches != nil {
+		return matches[1] + "/" + matches[2]
+	}
+	if matches := containerLogRegex.FindStringSubmatch(source); matches != nil {
+		return matches[1] + "/" + matches[2]
+	}
+	if matches := dockerLogRegex.FindStringSubmatch(source); matches != nil {
+		return matches[1][:12]
+	}
+	return filepath.Base(source)
+}
+
+// sourceColors tell the sources of a merged timeline apart
+var sourceColors = []lipgloss.AdaptiveColor{
+	{Light: "30", Dark: "44"},   // Cyan
+	{Light: "127", Dark: "170"}, // Magenta
+	{Light: "28", Dark: "78"},   // Green
+	{Light: "130", Dark: "215"}, // Orange
+	{Light: "61", Dark: "141"},  // Purple
+	{Light: "94", Dark: "180"},  // Tan
+}
+
+// sourceColor picks a color for a source by its path, so it keeps its color
+// between sessions and after other files are added
+func sourceColor(source string) lipgloss.AdaptiveColor {
+	hash := fnv.New32a()
+	hash.Write([]byte(source))
+	return sourceColors[hash.Sum32()%uint32(len(sourceColors))]
+}
+
+// showSource reports whether rows name their source, which they do when
+// several files are merged into one timeline, or streamed together like
+// stdout piped in and stderr in a named pipe
+func (m *UnifiedModel) showSource() bool {
+	if _, merged := m.indexer.(*MergedIndexer); merged {
+		return true
+	}
+	return len(m.config.AlsoTail) > 0 || (len(m.pipes) > 0 && len(m.config.Files) > 1)
+}
+
+// sourceLabel returns the name shown for a source, the user's label if it
+// was renamed
+func (m *UnifiedModel) sourceLabel(source string) string {
+	if label, ok := m.sourceLabels[source]; ok {
+		return label
+	}
+	return defaultSourceLabel(source)
+}
+
+// sourceByLabel returns the loaded file a label refers to
+func (m *UnifiedModel) sourceByLabel(label string) (string, bool) {
+	for _, file := range m.config.Files {
+		if m.sourceLabel(file) == label {
+			return file, true
+		}
+	}
+	return "", false
+}
+
+// startRenameSource opens the label input for the file shown
+func (m *UnifiedModel) startRenameSource() tea.Cmd {
+	if m.loadingFile == "" {
+		return nil
+	}
+	m.labelInput.SetValue(m.sourceLabel(m.loadingFile))
+	m.labelInput.CursorEnd()
+	m.editMode = true
+	m.activeInput = &m.labelInput
+	m.labelInput.Focus()
+	return textinput.Blink
+}
+
+// setSourceLabel renames a source. An empty label, or the default one,
+// goes back to the default
+func (m *UnifiedModel) setSourceLabel(source, label string) {
+	label = strings.TrimSpace(label)
+	if label == "" || label == defaultSourceLabel(source) {
+		delete(m.sourceLabels, source)
+		return
+	}
+	if m.sourceLabels == nil {
+		m.sourceLabels = make(map[string]string)
+	}
+	m.sourceLabels[source] = label
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDefaultSourceLabel(t *testing.T) {
+	testCases := []struct {
+		source   string
+		expected string
+	}{
+		{"/var/log/app.log", "app.log"},
+		{"stdin", "stdin"},
+		{"/var/lib/docker/containers/8f3a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8/8f3a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8-json.log", "8f3a1b2c3d4e"},
+		{"/var/log/pods/default_api-7d9f8-x2k4p_0b1c2d3e-aaaa-bbbb-cccc-123456789abc/server/0.log", "api-7d9f8-x2k4p/server"},
+		{"/var/log/containers/api-7d9f8-x2k4p_default_server-8f3a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8.log", "api-7d9f8-x2k4p/server"},
+	}
+
+	for _, tc := range testCases {
+		if label := defaultSourceLabel(tc.source); label != tc.expected {
+			t.Errorf("Expected label %q for %s, got %q", tc.expected, tc.source, label)
+		}
+	}
+}
+
+func TestSourceLabel_RenameFromFilesPanel(t *testing.T) {
+	model := newIndexedTestModel(t, numberedLines(5), 120, 60)
+	path := model.config.Files[0]
+
+	model.Update(keyMsg("tab"))
+	model.leftPanelItem = sourceItem
+	model.Update(keyMsg("r"))
+	if model.activeInput != &model.labelInput || model.labelInput.Value() != "test.log" {
+		t.Fatalf("Expected r to open the label input with the current label, got %q", model.labelInput.Value())
+	}
+	model.labelInput.SetValue("api")
+	model.Update(keyMsg("enter"))
+
+	if model.sourceLabel(path) != "api" {
+		t.Fatalf("Expected label api, got %q", model.sourceLabel(path))
+	}
+	if panel := model.renderLeftPanel(); !strings.Contains(panel, "api") {
+		t.Errorf("Expected the label in the Files section, got:\n%s", panel)
+	}
+	if state := model.filterState(); state.SourceLabels[path] != "api" {
+		t.Errorf("Expected the label to be saved by path, got %v", state.SourceLabels)
+	}
+
+	// The label scopes filters like the file name does
+	model.includeInput.SetValue("api:line 2")
+	model.applyFilters()
+	if len(model.filteredIndices) != 1 {
+		t.Errorf("Expected api:line 2 to match one line, got %d", len(model.filteredIndices))
+	}
+
+	// Clearing the label goes back to the default
+	model.setSourceLabel(path, "")
+	if model.sourceLabel(path) != "test.log" {
+		t.Errorf("Expected the default label back, got %q", model.sourceLabel(path))
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+)
+
+// defaultStreamLimit bounds the stream buffer when MaxLines isn't set
+const defaultStreamLimit = 10000
+
+// streamBuffer sits between the stdin reader and the UI. The reader queues
+// parsed entries and only wakes the model when no wake-up is pending, so a
+// firehose coalesces into a few large batches instead of one message per
+// batch. Entries beyond the limit are dropped, oldest first
+type streamBuffer struct {
+	mutex     sync.Mutex
+	buffer    *CircularBuffer
+	limit     int
+	pending   bool
+	coalesced int
+}
+
+func newStreamBuffer(limit int) *streamBuffer {
+	if limit <= 0 {
+		limit = defaultStreamLimit
+	}
+	return &streamBuffer{limit: limit}
+}
+
+// Push queues the entries and reports whether the model needs a wake-up
+// message. Batches pushed while one is pending are coalesced
+func (b *streamBuffer) Push(entries []LogEntry) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	// Allocated on first use so file mode doesn't pay for it
+	if b.buffer == nil {
+		b.buffer = NewCircularBuffer(b.limit)
+	}
+	for _, entry := range entries {
+		b.buffer.Add(entry)
+	}
+
+	if b.pending {
+		b.coalesced++
+		return false
+	}
+	b.pending = true
+	return true
+}
+
+// Drain hands the queued entries to the model and rearms the wake-up
+func (b *streamBuffer) Drain() []LogEntry {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.pending = false
+	if b.buffer == nil {
+		return nil
+	}
+	entries := b.buffer.GetAll()
+	b.buffer.Clear()
+	return entries
+}
+
+// Stats describes how much backpressure was applied, or "" if none
+func (b *streamBuffer) Stats() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	dropped := 0
+	if b.buffer != nil {
+		dropped = b.buffer.EvictedCount()
+	}
+	if b.coalesced == 0 && dropped == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Coalesced: %d Dropped: %d", b.coalesced, dropped)
+}
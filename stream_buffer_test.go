@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestStreamBuffer_CoalescesAndDrops(t *testing.T) {
+	buffer := newStreamBuffer(5)
+	batch := func(from, to int) []LogEntry {
+		entries := []LogEntry{}
+		for i := from; i < to; i++ {
+			entries = append(entries, LogEntry{Message: fmt.Sprintf("line %d", i)})
+		}
+		return entries
+	}
+
+	if !buffer.Push(batch(0, 2)) {
+		t.Fatal("Expected the first batch to wake the model")
+	}
+	if buffer.Push(batch(2, 4)) || buffer.Push(batch(4, 8)) {
+		t.Fatal("Expected batches to coalesce while a wake-up is pending")
+	}
+
+	entries := buffer.Drain()
+	if len(entries) != 5 || entries[0].Message != "line 3" || entries[4].Message != "line 7" {
+		t.Errorf("Expected the newest 5 entries, got %v", entries)
+	}
+	if stats := buffer.Stats(); stats != "Coalesced: 2 Dropped: 3" {
+		t.Errorf("Unexpected stats %q", stats)
+	}
+
+	// Draining rearms the wake-up
+	if !buffer.Push(batch(8, 9)) {
+		t.Error("Expected a wake-up after draining")
+	}
+	if entries := buffer.Drain(); len(entries) != 1 {
+		t.Errorf("Expected only the new entry, got %v", entries)
+	}
+}
+
+func TestStreamFrom_AddsEntriesAndShowsStats(t *testing.T) {
+	app := NewUnifiedApp(&Config{MaxLines: 50, RefreshRate: 1, Timezone: "UTC"})
+	app.streamFrom(strings.NewReader(strings.Join(numberedLines(300), "\n")), "stdin")
+
+	// No program ran, so nothing drained the first wake-up
+	app.model.Update(streamReadyMsg{})
+	if len(app.model.entries) != 50 {
+		t.Fatalf("Expected MaxLines entries, got %d", len(app.model.entries))
+	}
+	if app.model.entries[49].Message != numberedLines(300)[299] {
+		t.Errorf("Expected the newest line last, got %q", app.model.entries[49].Message)
+	}
+
+	app.model.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	if header := app.model.renderHeader(); !strings.Contains(header, "Dropped: 250") {
+		t.Errorf("Expected backpressure stats in the header, got %q", header)
+	}
+}
+
+// BenchmarkStreamFrom_Firehose feeds 1M lines as fast as they can be read
+// while a UI that renders every few milliseconds drains the buffer
+func BenchmarkStreamFrom_Firehose(b *testing.B) {
+	var input strings.Builder
+	for i := 0; i < 1000000; i++ {
+		fmt.Fprintf(&input, "2023-12-23 15:30:45 INFO: request %d served in 12ms\n", i)
+	}
+	data := input.String()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		app := NewUnifiedApp(&Config{MaxLines: 50000, RefreshRate: 1, Timezone: "UTC"})
+		done := make(chan struct{})
+		drained := make(chan struct{})
+		go func() {
+			defer close(drained)
+			for {
+				select {
+				case <-done:
+					app.model.Update(streamReadyMsg{})
+					return
+				case <-time.After(5 * time.Millisecond):
+					app.model.Update(streamReadyMsg{})
+				}
+			}
+		}()
+
+		app.streamFrom(strings.NewReader(data), "stdin")
+		close(done)
+		<-drained
+
+		b.ReportMetric(float64(app.model.stream.coalesced), "coalesced/op")
+		b.ReportMetric(float64(app.model.stream.buffer.EvictedCount()), "dropped/op")
+	}
+}
+
+func TestAddLogBatch_RefiltersOnFilterChange(t *testing.T) {
+	model := NewUnifiedModel(&Config{MaxLines: 3, RefreshRate: 1, Timezone: "UTC", Include: "payment"})
+	model.AddLogBatch([]LogEntry{
+		{Level: INFO, Message: "payment ok"},
+		{Level: ERROR, Message: "payment failed"},
+		{Level: INFO, Message: "order created"},
+	})
+	if len(model.filteredEntries) != 2 {
+		t.Fatalf("Expected 2 payment entries, got %v", model.filteredEntries)
+	}
+
+	// Changing a filter rescans what was streamed so far
+	model.includeInput.SetValue("order")
+	model.applyFilters()
+	if len(model.filteredEntries) != 1 || model.filteredEntries[0].Message != "order created" {
+		t.Errorf("Expected only the order entry, got %v", model.filteredEntries)
+	}
+
+	// New batches are filtered on their own and stay within MaxLines
+	model.AddLogBatch([]LogEntry{{Level: INFO, Message: "order shipped"}, {Level: INFO, Message: "payment ok"}})
+	if len(model.entries) != 3 || len(model.filteredEntries) != 2 || model.filteredEntries[1].Message != "order shipped" {
+		t.Errorf("Expected 3 entries and 2 order entries, got %v and %v", model.entries, model.filteredEntries)
+	}
+}
+
+func TestAddLogBatch_EvictsFilteredEntriesWithTheirEntries(t *testing.T) {
+	model := NewUnifiedModel(&Config{MaxLines: 3, RefreshRate: 1, Timezone: "UTC", Include: "payment"})
+	model.AddLogBatch([]LogEntry{
+		{Level: ERROR, Message: "payment failed"},
+		{Level: INFO, Message: "order created"},
+		{Level: INFO, Message: "order shipped"},
+	})
+
+	// The payment entry leaves the kept entries, and so the filtered ones
+	model.AddLogBatch([]LogEntry{{Level: INFO, Message: "order delivered"}})
+	if len(model.filteredEntries) != 0 {
+		t.Errorf("Expected no filtered entries left, got %v", model.filteredEntries)
+	}
+	if model.streamShown != (levelCounts{}) {
+		t.Errorf("Expected no shown entries counted, got %v", model.streamShown)
+	}
+}
+
+func TestAddLogBatch_FiltersOnlyNewEntries(t *testing.T) {
+	lines := []string{"2023-12-23 15:30:45 INFO: keep file", "2023-12-23 15:30:46 ERROR: drop file"}
+	model := newIndexedTestModel(t, lines, 120, 30)
+	model.config.MaxLines = 5
+	model.includeInput.SetValue("keep")
+	model.excludeInput.SetValue("secret")
+	model.searchQuery = "payment"
+	model.applyFilters()
+
+	for i := 0; i < 6; i++ {
+		model.AddLogBatch([]LogEntry{
+			{Level: INFO, Message: fmt.Sprintf("keep payment %d", i)},
+			{Level: ERROR, Message: fmt.Sprintf("other %d", i)},
+			{Level: WARN, Message: fmt.Sprintf("keep secret %d", i)},
+		})
+
+		// The incremental pass ends where filtering everything again does
+		filtered := fmt.Sprint(model.filteredIndices, model.matchedIndices, model.searchMatches, *model.filterStats)
+		model.applyFilters()
+		if again := fmt.Sprint(model.filteredIndices, model.matchedIndices, model.searchMatches, *model.filterStats); filtered != again {
+			t.Fatalf("Batch %d: expected %s after a full pass, got %s", i, again, filtered)
+		}
+	}
+	if model.totalLines != 2+5 {
+		t.Errorf("Expected the file lines and 5 streamed ones, got %d", model.totalLines)
+	}
+}
+
+// benchmarkAddLogBatch adds batches of 100 entries to a model holding 50k,
+// with or without rescanning all of them after each batch
+func benchmarkAddLogBatch(b *testing.B, rescan bool) {
+	model := NewUnifiedModel(&Config{MaxLines: 50000, RefreshRate: 1, Timezone: "UTC", Include: "ERROR,timeout", Exclude: "healthcheck"})
+	batch := make([]LogEntry, 100)
+	for i := range batch {
+		batch[i] = LogEntry{Level: INFO, Timestamp: "2023-12-23T15:30:45Z", Message: fmt.Sprintf("ERROR request %d timeout", i)}
+	}
+	for i := 0; i < 500; i++ {
+		model.AddLogBatch(batch)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		model.AddLogBatch(batch)
+		if rescan {
+			model.refilterEntries()
+		}
+	}
+}
+
+func BenchmarkAddLogBatch_Incremental(b *testing.B) { benchmarkAddLogBatch(b, false) }
+func BenchmarkAddLogBatch_FullRescan(b *testing.B)  { benchmarkAddLogBatch(b, true) }
@@ -0,0 +1,343 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// errStreamed is returned for file positions of streamed lines, which were
+// never in a file that could be read again
+var errStreamed = errors.New("streamed line isn't in a file")
+
+// StreamIndexer shows streamed entries, from stdin, named pipes or tailed
+// files, through the same view as indexed files. The entries are the
+// model's, kept to MaxLines. When a file was also indexed, its lines come
+// first and the streamed ones follow in the order they arrived
+type StreamIndexer struct {
+	base    LineIndexer // nil without an indexed file
+	entries []LogEntry
+	mutex   sync.RWMutex
+}
+
+// NewStreamIndexer shows streamed entries after the lines of base, if any
+func NewStreamIndexer(base LineIndexer) *StreamIndexer {
+	return &StreamIndexer{base: base}
+}
+
+// setEntries replaces the streamed entries with the model's current ones
+func (si *StreamIndexer) setEntries(entries []LogEntry) {
+	si.mutex.Lock()
+	defer si.mutex.Unlock()
+	si.entries = entries
+}
+
+// swapBase puts the lines of base before the streamed ones, for a file
+// indexed after the stream started or indexed again, and returns the
+// indexer it replaced
+func (si *StreamIndexer) swapBase(base LineIndexer) LineIndexer {
+	si.mutex.Lock()
+	defer si.mutex.Unlock()
+	old := si.base
+	si.base = base
+	return old
+}
+
+// hasBase reports whether the streamed entries follow an indexed file
+func (si *StreamIndexer) hasBase() bool {
+	si.mutex.RLock()
+	defer si.mutex.RUnlock()
+	return si.base != nil
+}
+
+// resolve returns the base line of idx, or the streamed entry it refers to
+func (si *StreamIndexer) resolve(idx int) (LineIndexer, int, *LogEntry) {
+	si.mutex.RLock()
+	defer si.mutex.RUnlock()
+
+	if si.base != nil {
+		lines := si.base.GetLineCount()
+		if idx < lines {
+			return si.base, idx, nil
+		}
+		idx -= lines
+	}
+	if idx < 0 || idx >= len(si.entries) {
+		return nil, 0, nil
+	}
+	return nil, 0, &si.entries[idx]
+}
+
+// GetLineRange returns lines start through end-1
+func (si *StreamIndexer) GetLineRange(start, end int) ([]LogEntry, error) {
+	start = max(start, 0)
+	end = min(end, si.GetLineCount())
+	if start >= end {
+		return []LogEntry{}, nil
+	}
+
+	entries := make([]LogEntry, 0, end-start)
+	for i := start; i < end; i++ {
+		base, line, entry := si.resolve(i)
+		if entry != nil {
+			entries = append(entries, *entry)
+			continue
+		}
+		if base == nil {
+			return entries, io.EOF
+		}
+		read, err := base.GetLineRange(line, line+1)
+		if err != nil {
+			return entries, err
+		}
+		entries = append(entries, read...)
+	}
+	return entries, nil
+}
+
+// GetLineCount returns the base lines plus the streamed entries
+func (si *StreamIndexer) GetLineCount() int {
+	si.mutex.RLock()
+	defer si.mutex.RUnlock()
+	count := len(si.entries)
+	if si.base != nil {
+		count += si.base.GetLineCount()
+	}
+	return count
+}
+
+// LineCount returns the total lines (alias for GetLineCount)
+func (si *StreamIndexer) LineCount() int {
+	return si.GetLineCount()
+}
+
+// GetLines returns the raw text of lines start through start+count-1
+func (si *StreamIndexer) GetLines(start, count int) []string {
+	end := min(start+count, si.GetLineCount())
+	lines := make([]string, 0, max(end-start, 0))
+	for i := max(start, 0); i < end; i++ {
+		if line, err := si.RawLine(i); err == nil {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// LineLevel returns the level of a streamed entry, or the one its file
+// recorded while indexing
+func (si *StreamIndexer) LineLevel(idx int) (LogLevel, bool) {
+	base, line, entry := si.resolve(idx)
+	if entry != nil {
+		return entry.Level, true
+	}
+	if base == nil {
+		return levelUnknown, false
+	}
+	return base.LineLevel(line)
+}
+
+// LineTime returns the timestamp of a streamed entry, or the one its file
+// recorded while indexing
+func (si *StreamIndexer) LineTime(idx int) (time.Time, bool) {
+	base, line, entry := si.resolve(idx)
+	if entry != nil {
+		return entry.Time, !entry.Time.IsZero()
+	}
+	if base == nil {
+		return time.Time{}, false
+	}
+	return base.LineTime(line)
+}
+
+// LineSpan returns where a line of the indexed file lies in it
+func (si *StreamIndexer) LineSpan(idx int) (FastLineIndex, error) {
+	base, line, entry := si.resolve(idx)
+	if entry != nil || base == nil {
+		return FastLineIndex{}, errStreamed
+	}
+	return base.LineSpan(line)
+}
+
+// RawLine returns the line as it was read
+func (si *StreamIndexer) RawLine(idx int) (string, error) {
+	base, line, entry := si.resolve(idx)
+	if entry != nil {
+		if entry.Raw != "" {
+			return entry.Raw, nil
+		}
+		return entry.Message, nil
+	}
+	if base == nil {
+		return "", io.EOF
+	}
+	return base.RawLine(line)
+}
+
+// FileLine returns the file and line number of a line of the indexed file;
+// streamed lines have none
+func (si *StreamIndexer) FileLine(idx int) (string, int, bool) {
+	base, line, entry := si.resolve(idx)
+	if entry != nil || base == nil {
+		return "", 0, false
+	}
+	return base.FileLine(line)
+}
+
+// CopyLines writes lines first through last to w, streamed ones as read
+func (si *StreamIndexer) CopyLines(w io.Writer, first, last int) (int64, error) {
+	if first > last {
+		first, last = last, first
+	}
+	var written int64
+	for i := first; i <= last; i++ {
+		base, line, entry := si.resolve(i)
+		if entry == nil && base != nil {
+			n, err := base.CopyLines(w, line, line)
+			written += n
+			if err != nil {
+				return written, err
+			}
+			continue
+		}
+		raw, err := si.RawLine(i)
+		if err != nil {
+			return written, fmt.Errorf("line %d: %w", i, err)
+		}
+		n, err := io.WriteString(w, raw+"\n")
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// Extend indexes the lines appended to the indexed file. Streamed entries
+// are added by the model instead
+func (si *StreamIndexer) Extend() (bool, error) {
+	si.mutex.RLock()
+	base := si.base
+	si.mutex.RUnlock()
+	if base == nil {
+		return false, nil
+	}
+	return base.Extend()
+}
+
+// IndexStride returns the stride of the indexed file, streamed entries are
+// all kept
+func (si *StreamIndexer) IndexStride() int {
+	si.mutex.RLock()
+	defer si.mutex.RUnlock()
+	if si.base == nil {
+		return 1
+	}
+	return si.base.IndexStride()
+}
+
+// TailOffset reports whether the indexed file was only indexed from its end
+func (si *StreamIndexer) TailOffset() int64 {
+	si.mutex.RLock()
+	defer si.mutex.RUnlock()
+	if si.base == nil {
+		return 0
+	}
+	return si.base.TailOffset()
+}
+
+// Close releases the indexed file
+func (si *StreamIndexer) Close() error {
+	si.mutex.Lock()
+	defer si.mutex.Unlock()
+	if si.base == nil {
+		return nil
+	}
+	return si.base.Close()
+}
+
+// showStream shows the streamed entries, the last added of them new, through
+// a StreamIndexer after the lines of the file indexed with them, if any.
+// Only the new entries are filtered, and the evicted ones taken out
+func (m *UnifiedModel) showStream(added int, evicted []LogEntry) {
+	stream, ok := m.indexer.(*StreamIndexer)
+	if !ok {
+		if added == 0 {
+			return
+		}
+		stream = NewStreamIndexer(m.indexer)
+		m.indexer = stream
+	}
+	m.mutex.RLock()
+	stream.setEntries(m.entries)
+	kept := len(m.entries)
+	m.mutex.RUnlock()
+
+	lines := stream.GetLineCount()
+	m.totalLines = lines
+	if m.indexing {
+		return // Shown with the file being indexed once it's done
+	}
+	if added >= kept {
+		// A batch past MaxLines replaced every entry
+		m.applyFilters()
+	} else {
+		// Entries past MaxLines were dropped from the front of the stream
+		m.dropStreamed(lines-kept, evicted)
+		m.showNewLines(lines - added)
+	}
+	if m.tailing {
+		m.scrollToBottom()
+	} else if m.followMatches {
+		m.followNewMatch(lines - added)
+	}
+}
+
+// dropStreamed takes the streamed entries evicted past MaxLines out of the
+// last filter pass. They were the first streamed lines, from line base on,
+// and the lines after them move up in their place
+func (m *UnifiedModel) dropStreamed(base int, evicted []LogEntry) {
+	if len(evicted) == 0 || m.filterStats == nil || m.sortMode != sortNone || m.collapse {
+		return // Nothing filtered yet, or filtered again as a whole
+	}
+
+	filter := m.entryFilter()
+	for _, entry := range evicted {
+		stage, _ := m.entryStage(entry, filter)
+		m.filterStats.count(stage, entry.Level, -1)
+	}
+
+	first := sort.SearchInts(m.filteredIndices, base)
+	last := sort.SearchInts(m.filteredIndices, base+len(evicted))
+	for i := last; i < len(m.filteredIndices); i++ {
+		m.filteredIndices[i] -= len(evicted)
+	}
+	m.filteredIndices = append(m.filteredIndices[:first], m.filteredIndices[last:]...)
+	m.matchedIndices = dropPositions(m.matchedIndices, first, last)
+	m.searchMatches = dropPositions(m.searchMatches, first, last)
+	if m.currentMatchIdx >= len(m.matchedIndices) {
+		m.currentMatchIdx = 0
+	}
+	if m.searchIdx >= len(m.searchMatches) {
+		m.searchIdx = 0
+	}
+	if m.viewportStart > first {
+		m.viewportStart = max(first, m.viewportStart-(last-first))
+	}
+}
+
+// dropPositions removes the filtered positions first through last-1 from
+// positions and moves the ones after them up
+func dropPositions(positions []int, first, last int) []int {
+	kept := positions[:0]
+	for _, pos := range positions {
+		if pos < first {
+			kept = append(kept, pos)
+		} else if pos >= last {
+			kept = append(kept, pos-(last-first))
+		}
+	}
+	return kept
+}
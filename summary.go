@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// summaryTop is how many of the most frequent messages the summary lists
+const summaryTop = 5
+
+// summaryOut is where --summary is written once the UI is gone, swapped out
+// in tests
+var summaryOut io.Writer = os.Stderr
+
+// runSummary adds up what a session read for --summary
+type runSummary struct {
+	lines    int
+	levels   levelCounts
+	matched  int
+	messages map[string]int // Matching entries per message template
+	first    time.Time
+	last     time.Time
+}
+
+func newRunSummary() *runSummary {
+	return &runSummary{messages: make(map[string]int)}
+}
+
+// add counts an entry, and the template of its message if it passed the
+// filters, so messages differing only in ids and numbers count together
+func (s *runSummary) add(entry LogEntry, matched bool) {
+	s.lines++
+	s.levels[entry.Level]++
+	if matched {
+		s.matched++
+		s.messages[messageTemplate(strings.ReplaceAll(entry.Message, "\n", " "))]++
+	}
+	if entry.Time.IsZero() {
+		return
+	}
+	if s.first.IsZero() || entry.Time.Before(s.first) {
+		s.first = entry.Time
+	}
+	if entry.Time.After(s.last) {
+		s.last = entry.Time
+	}
+}
+
+// write prints the summary, with times in zone
+func (s *runSummary) write(w io.Writer, zone *time.Location) {
+	fmt.Fprintf(w, "Lines:      %d\n", s.lines)
+	levels := make([]string, 0, 4)
+	for _, level := range []LogLevel{ERROR, WARN, INFO, DEBUG} {
+		levels = append(levels, fmt.Sprintf("%s %d", level, s.levels[level]))
+	}
+	fmt.Fprintf(w, "Levels:     %s\n", strings.Join(levels, ", "))
+	fmt.Fprintf(w, "Matching:   %d\n", s.matched)
+	if s.first.IsZero() {
+		fmt.Fprintf(w, "Time span:  no timestamps\n")
+	} else {
+		fmt.Fprintf(w, "Time span:  %s to %s (%v)\n", s.first.In(zone).Format(time.RFC3339), s.last.In(zone).Format(time.RFC3339), s.last.Sub(s.first))
+	}
+
+	top := rankTemplates(s.messages, summaryTop)
+	if len(top) > 0 {
+		fmt.Fprintf(w, "Top messages:\n")
+	}
+	for _, template := range top {
+		fmt.Fprintf(w, "%8d  %s\n", template.Count, template.Template)
+	}
+}
+
+// summarizeIndex adds up the indexed lines for --summary before the indexer
+// is closed. Streamed entries are counted as they're added instead, and
+// collapsed repeats still count as matching
+func (m *UnifiedModel) summarizeIndex() {
+	indexer := m.indexer
+	if stream, ok := indexer.(*StreamIndexer); ok {
+		indexer = stream.base
+	}
+	if m.summary == nil || indexer == nil {
+		return
+	}
+	filter := m.entryFilter()
+	const chunk = 4096
+	lines := indexer.GetLineCount()
+	for start := 0; start < lines; start += chunk {
+		entries, err := indexer.GetLineRange(start, start+chunk)
+		if err != nil {
+			return
+		}
+		for _, entry := range entries {
+			m.summary.add(entry, m.passes(entry, filter))
+		}
+	}
+}
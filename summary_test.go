@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestSummary_PrintMatches(t *testing.T) {
+	var summary bytes.Buffer
+	summaryOut = &summary
+	defer func() { summaryOut = os.Stderr }()
+
+	stdin := strings.NewReader(strings.Join([]string{
+		"2023-12-23 15:30:45 ERROR: upstream timeout",
+		"2023-12-23 15:30:46 INFO: served",
+		"2023-12-23 15:30:50 ERROR: upstream timeout",
+		"2023-12-23 15:31:00 WARN: retry timeout",
+		"2023-12-23 15:32:45 ERROR: disk full",
+	}, "\n") + "\n")
+	var out bytes.Buffer
+	matched, err := printMatches(&Config{Timezone: "UTC", Summary: true, Include: "timeout"}, stdin, &out)
+	if err != nil || matched != 3 {
+		t.Fatalf("Expected 3 matches, got %d (%v)", matched, err)
+	}
+
+	for _, want := range []string{
+		"Lines:      5\n",
+		"Levels:     ERROR 3, WARN 1, INFO 1, DEBUG 0\n",
+		"Matching:   3\n",
+		"Time span:  2023-12-23T15:30:45Z to 2023-12-23T15:32:45Z (2m0s)\n",
+		"Top messages:\n       2  <num>-<num>-<num> <num>:<num>:<num> ERROR: upstream timeout\n       1  <num>-<num>-<num> <num>:<num>:<num> WARN: retry timeout\n",
+	} {
+		if !strings.Contains(summary.String(), want) {
+			t.Errorf("Expected %q in the summary:\n%s", want, summary.String())
+		}
+	}
+	if strings.Contains(summary.String(), "disk full") {
+		t.Errorf("Expected only matching messages in the top list:\n%s", summary.String())
+	}
+}
+
+func TestSummary_QuitCountsIndex(t *testing.T) {
+	var summary bytes.Buffer
+	summaryOut = &summary
+	defer func() { summaryOut = os.Stderr }()
+
+	model := newIndexedTestModel(t, numberedLines(20), 120, 30)
+	model.summary = newRunSummary()
+	model.quit()
+	if model.summary.lines != 20 || model.summary.matched != 20 {
+		t.Errorf("Expected 20 lines all matching, got %d and %d", model.summary.lines, model.summary.matched)
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import "fmt"
+
+// suppressSelected hides the selected entry and every other entry with the
+// exact same message, for this session only
+func (m *UnifiedModel) suppressSelected() {
+	m.mutex.RLock()
+	if m.selectedIdx < 0 || m.selectedIdx >= len(m.visibleEntries) {
+		m.mutex.RUnlock()
+		return
+	}
+	message := m.visibleEntries[m.selectedIdx].Message
+	m.mutex.RUnlock()
+
+	if m.suppressed == nil {
+		m.suppressed = make(map[string]bool)
+	}
+	m.suppressed[message] = true
+	before := len(m.filteredIndices)
+	m.applyFilters()
+
+	// The next entry moves up into the selection, like dd in vim
+	m.notice = fmt.Sprintf("Hid %d lines, X to show them", before-len(m.filteredIndices))
+}
+
+// clearSuppressed shows every entry hidden with x again
+func (m *UnifiedModel) clearSuppressed() {
+	if len(m.suppressed) == 0 {
+		m.notice = "No hidden lines, x to hide one"
+		return
+	}
+	m.suppressed = nil
+	m.applyFilters()
+	m.notice = "Showing hidden lines"
+}
+
+// isSuppressed reports whether the entry's message was hidden with x
+func (m *UnifiedModel) isSuppressed(entry LogEntry) bool {
+	return m.suppressed[entry.Message]
+}
@@ -0,0 +1,41 @@
+package main
+
+// defaultTail is how many lines the view starts on, the default of tail -n
+const defaultTail = 10
+
+// startAtTail positions a freshly indexed file like tail -n: following the
+// newest line and, when the last config.Tail lines are more than a page,
+// starting on the first of them. Tail 0 starts at the first line instead
+func (m *UnifiedModel) startAtTail() {
+	tail := m.config.Tail
+	if tail <= 0 {
+		m.tailing = false
+		m.viewportStart = 0
+		m.selectedIdx = 0
+		m.loadVisibleLines()
+		return
+	}
+
+	// Tailing has already put the newest line at the bottom of the page
+	m.tailing = true
+	if tail <= m.viewportHeight || len(m.filteredIndices) <= m.viewportHeight {
+		return
+	}
+	m.viewportStart = max(0, len(m.filteredIndices)-tail)
+	m.selectedIdx = 0
+	m.loadVisibleLines()
+}
+
+// startStream positions the view on the first streamed batch as on an
+// indexed file, every entry kept. A stream after an indexed file starts
+// where the file did
+func (m *UnifiedModel) startStream() {
+	stream, ok := m.indexer.(*StreamIndexer)
+	if m.streamStarted || !ok || m.indexing {
+		return
+	}
+	m.streamStarted = true
+	if !stream.hasBase() {
+		m.startAtTail()
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// topTemplateCount is how many templates the analysis view ranks
+const topTemplateCount = 100
+
+var (
+	uuidRegex   = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	hexIDRegex  = regexp.MustCompile(`\b(?:0x[0-9a-fA-F]+|[0-9a-fA-F]{8,})\b`)
+	numberRegex = regexp.MustCompile(`\d+(?:\.\d+)?`)
+)
+
+// TemplateCount is how many messages share a template
+type TemplateCount struct {
+	Template string
+	Count    int
+}
+
+// messageTemplate replaces the variable parts of a message with placeholders:
+// UUIDs and hex ids with <id>, other numbers with <num>
+func messageTemplate(message string) string {
+	template := uuidRegex.ReplaceAllString(message, "<id>")
+	template = hexIDRegex.ReplaceAllStringFunc(template, func(hex string) string {
+		// Long words like "deadline" aren't ids, hex ids almost always
+		// have a digit
+		if strings.HasPrefix(hex, "0x") || strings.ContainsAny(hex, "0123456789") {
+			return "<id>"
+		}
+		return hex
+	})
+	return numberRegex.ReplaceAllString(template, "<num>")
+}
+
+// topTemplates counts the templates of the entries' messages and returns the
+// n most frequent, ties in template order
+func topTemplates(entries []LogEntry, n int) []TemplateCount {
+	counts := make(map[string]int)
+	for _, entry := range entries {
+		counts[messageTemplate(strings.ReplaceAll(entry.Message, "\n", " "))]++
+	}
+	return rankTemplates(counts, n)
+}
+
+// rankTemplates returns the n most frequent of the counted templates, ties in
+// template order
+func rankTemplates(counts map[string]int, n int) []TemplateCount {
+	templates := make([]TemplateCount, 0, len(counts))
+	for template, count := range counts {
+		templates = append(templates, TemplateCount{Template: template, Count: count})
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		if templates[i].Count != templates[j].Count {
+			return templates[i].Count > templates[j].Count
+		}
+		return templates[i].Template < templates[j].Template
+	})
+
+	if len(templates) > n {
+		templates = templates[:n]
+	}
+	return templates
+}
+
+// filteredLogEntries returns every entry passing the filters, read from the
+// index or taken from the stream
+func (m *UnifiedModel) filteredLogEntries() []LogEntry {
+	if m.indexer == nil {
+		m.mutex.RLock()
+		defer m.mutex.RUnlock()
+		return append([]LogEntry(nil), m.filteredEntries...)
+	}
+
+	entries := make([]LogEntry, 0, len(m.filteredIndices))
+	for _, line := range m.filteredIndices {
+		if read, err := m.indexer.GetLineRange(line, line+1); err == nil && len(read) > 0 {
+			entries = append(entries, read[0])
+		}
+	}
+	return entries
+}
+
+// openTemplates ranks the templates of the filtered entries in place of the
+// log stream
+func (m *UnifiedModel) openTemplates() {
+	if m.indexing {
+		return
+	}
+	entries := m.filteredLogEntries()
+	m.templates = topTemplates(entries, topTemplateCount)
+	m.templateTotal = len(entries)
+	m.templateScroll = 0
+	m.viewMode = TemplatesView
+}
+
+// scrollTemplates moves the ranked list by step rows
+func (m *UnifiedModel) scrollTemplates(step int) {
+	m.templateScroll = max(0, min(m.templateScroll+step, len(m.templates)-1))
+}
+
+// renderTemplatesPanel renders the ranked templates with their counts
+func (m *UnifiedModel) renderTemplatesPanel() string {
+	var content strings.Builder
+
+	content.WriteString("📊 TOP MESSAGES\n")
+	content.WriteString("   (j/k to scroll, ESC to return)\n")
+	content.WriteString("───────────────────────────────────────────\n\n")
+
+	if len(m.templates) == 0 {
+		content.WriteString("No entries to analyze\n")
+	} else {
+		content.WriteString(fmt.Sprintf("%d entries, %d most frequent messages:\n\n", m.templateTotal, len(m.templates)))
+	}
+
+	templateWidth := max(10, m.rightWidth-20)
+	rows := max(1, m.height-12)
+	for i := m.templateScroll; i < len(m.templates) && i < m.templateScroll+rows; i++ {
+		template := ansi.Truncate(m.redact(m.templates[i].Template), templateWidth, "...")
+		content.WriteString(fmt.Sprintf("%3d. %7d  %s\n", i+1, m.templates[i].Count, template))
+	}
+
+	return m.focusedStyle.Width(m.rightWidth).Height(m.height - 2).Render(content.String())
+}
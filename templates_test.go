@@ -0,0 +1,59 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTopTemplates_NormalizesVariableParts(t *testing.T) {
+	entries := []LogEntry{
+		{Message: "request 550e8400-e29b-41d4-a716-446655440000 took 12ms"},
+		{Message: "request 6ba7b810-9dad-11d1-80b4-00c04fd430c8 took 3.5ms"},
+		{Message: "request 6ba7b811-9dad-11d1-80b4-00c04fd430c8 took 7ms"},
+		{Message: "commit 9f86d081884c done"},
+		{Message: "commit 0x1f done"},
+		{Message: "deadline exceeded"},
+	}
+
+	templates := topTemplates(entries, 2)
+	expected := []TemplateCount{
+		{Template: "request <id> took <num>ms", Count: 3},
+		{Template: "commit <id> done", Count: 2},
+	}
+	if len(templates) != len(expected) {
+		t.Fatalf("Expected %d templates, got %v", len(expected), templates)
+	}
+	for i := range expected {
+		if templates[i] != expected[i] {
+			t.Errorf("Template %d: expected %v, got %v", i, expected[i], templates[i])
+		}
+	}
+
+	if got := messageTemplate("deadline exceeded"); got != "deadline exceeded" {
+		t.Errorf("Expected words to be kept, got %q", got)
+	}
+}
+
+func TestTemplatesView_RanksFilteredEntries(t *testing.T) {
+	lines := []string{
+		`{"time":"2023-12-23T15:30:45Z","level":"info","msg":"user 1 logged in"}`,
+		`{"time":"2023-12-23T15:30:46Z","level":"info","msg":"user 2 logged in"}`,
+		`{"time":"2023-12-23T15:30:47Z","level":"error","msg":"disk 3 full"}`,
+		`{"time":"2023-12-23T15:30:48Z","level":"info","msg":"user 3 logged in"}`,
+	}
+	model := newIndexedTestModel(t, lines, 120, 30)
+
+	model.Update(keyMsg("a"))
+	if model.viewMode != TemplatesView {
+		t.Fatalf("Expected the templates view, got %v", model.viewMode)
+	}
+	view := model.View()
+	if !strings.Contains(view, "3  user <num> logged in") {
+		t.Errorf("Expected the ranked template, got:\n%s", view)
+	}
+
+	model.Update(keyMsg("esc"))
+	if model.viewMode != LogStreamView {
+		t.Errorf("Expected esc to return to the log stream, got %v", model.viewMode)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Layouts accepted for absolute --since/--until values, in the display timezone
+var timeBoundLayouts = []string{
+	"2006-01-02 15:04:05",
+	time.RFC3339,
+}
+
+// timeRange is the --since/--until window; a zero bound is open
+type timeRange struct {
+	since time.Time
+	until time.Time
+}
+
+// parseTimeBound parses an absolute timestamp or a relative duration
+// such as "-10m", which is resolved against now
+func parseTimeBound(value string, loc *time.Location, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(d), nil
+	}
+
+	for _, layout := range timeBoundLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time %q: want a duration like -10m or 2006-01-02 15:04:05", value)
+}
+
+// isOpen reports whether the range lets every entry through
+func (r timeRange) isOpen() bool {
+	return r.since.IsZero() && r.until.IsZero()
+}
+
+// contains reports whether an entry's time falls inside the window. Entries
+// without a parsed time (zero) are kept so no data is silently dropped
+func (r timeRange) contains(t time.Time) bool {
+	if r.isOpen() || t.IsZero() {
+		return true
+	}
+
+	if !r.since.IsZero() && t.Before(r.since) {
+		return false
+	}
+	if !r.until.IsZero() && t.After(r.until) {
+		return false
+	}
+	return true
+}
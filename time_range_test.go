@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeBound(t *testing.T) {
+	now := time.Date(2023, 12, 23, 16, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		input    string
+		expected time.Time
+		wantErr  bool
+	}{
+		{"", time.Time{}, false},
+		{"-10m", now.Add(-10 * time.Minute), false},
+		{"2023-12-23 15:30:00", time.Date(2023, 12, 23, 15, 30, 0, 0, time.UTC), false},
+		{"2023-12-23T15:30:00Z", time.Date(2023, 12, 23, 15, 30, 0, 0, time.UTC), false},
+		{"yesterday", time.Time{}, true},
+	}
+
+	for _, test := range tests {
+		got, err := parseTimeBound(test.input, time.UTC, now)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("parseTimeBound(%q): expected error", test.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseTimeBound(%q): unexpected error %v", test.input, err)
+			continue
+		}
+		if !got.Equal(test.expected) {
+			t.Errorf("parseTimeBound(%q) = %v, expected %v", test.input, got, test.expected)
+		}
+	}
+}
+
+func TestTimeRangeContains(t *testing.T) {
+	window := timeRange{
+		since: time.Date(2023, 12, 23, 15, 30, 0, 0, time.UTC),
+		until: time.Date(2023, 12, 23, 15, 45, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		time     time.Time
+		expected bool
+	}{
+		{time.Date(2023, 12, 23, 15, 29, 59, 0, time.UTC), false},
+		{time.Date(2023, 12, 23, 15, 30, 0, 0, time.UTC), true},
+		{time.Date(2023, 12, 23, 16, 40, 0, 0, time.FixedZone("CET", 3600)), true},
+		{time.Date(2023, 12, 23, 15, 45, 1, 0, time.UTC), false},
+		{time.Time{}, true}, // entries without a parsed time are kept
+	}
+
+	for _, test := range tests {
+		if got := window.contains(test.time); got != test.expected {
+			t.Errorf("contains(%v) = %v, expected %v", test.time, got, test.expected)
+		}
+	}
+}
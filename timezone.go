@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// parseSourceTimezones parses --source-timezone values: a zone for every
+// source, or "source=zone" for one of them
+func parseSourceTimezones(values []string) (*time.Location, map[string]*time.Location, error) {
+	var zone *time.Location
+	zones := make(map[string]*time.Location)
+	for _, value := range values {
+		source, name, scoped := strings.Cut(value, "=")
+		if !scoped {
+			name = source
+		}
+		loc, err := time.LoadLocation(strings.TrimSpace(name))
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid source timezone %q: %w", value, err)
+		}
+		if scoped {
+			zones[strings.TrimSpace(source)] = loc
+		} else {
+			zone = loc
+		}
+	}
+	return zone, zones, nil
+}
+
+// zoneFor returns the zone timestamps without an offset are read in for source
+func (p *LogParser) zoneFor(source string) *time.Location {
+	for name, zone := range p.sourceZones {
+		if sourceMatches(name, source) {
+			return zone
+		}
+	}
+	return p.sourceZone
+}
+
+// setEntryTime records a parsed timestamp, kept in UTC so entries don't
+// depend on the display zone
+func setEntryTime(entry *LogEntry, t time.Time) {
+	entry.Time = t
+	entry.Timestamp = t.UTC().Format(time.RFC3339)
+}
+
+// nowTimestamp stands in for the timestamp of lines that have none
+func nowTimestamp() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+// timePrecisions maps --time-precision values to the fractional second
+// digits shown
+var timePrecisions = map[string]int{"s": 0, "ms": 3, "us": 6, "ns": 9}
+
+// parseTimePrecision parses a --time-precision value into its digits
+func parseTimePrecision(name string) (int, error) {
+	digits, ok := timePrecisions[strings.ToLower(name)]
+	if !ok {
+		return 0, fmt.Errorf("invalid time precision %q (s, ms, us or ns)", name)
+	}
+	return digits, nil
+}
+
+// timeLayout is RFC3339 with as many fractional second digits as
+// --time-precision asks for. Parsed times always keep full precision
+func (m *UnifiedModel) timeLayout() string {
+	if m.config.TimePrecision == 0 {
+		return time.RFC3339
+	}
+	return "2006-01-02T15:04:05." + strings.Repeat("0", m.config.TimePrecision) + "Z07:00"
+}
+
+// timeColumnWidth is the width of the TIME column, wide enough for an
+// offset and the fractional digits shown
+func (m *UnifiedModel) timeColumnWidth() int {
+	if m.config.TimePrecision == 0 {
+		return 26
+	}
+	return 27 + m.config.TimePrecision
+}
+
+// displayTimestamp formats an entry's time in the display zone. Entries
+// without a parsed time show their Timestamp as is
+func (m *UnifiedModel) displayTimestamp(entry LogEntry) string {
+	if entry.Time.IsZero() {
+		return entry.Timestamp
+	}
+	return entry.Time.In(m.displayZone).Format(m.timeLayout())
+}
+
+// columnTimestamp is the TIME column of an entry: how long ago it happened
+// when relative times are on, otherwise its displayed timestamp
+func (m *UnifiedModel) columnTimestamp(entry LogEntry) string {
+	if !m.relativeTime || entry.Time.IsZero() {
+		return m.displayTimestamp(entry)
+	}
+	return relativeAge(time.Since(entry.Time))
+}
+
+// relativeAge formats an age in its largest whole unit, e.g. 3s, 2m, 1h or
+// 4d. Times ahead of the clock read "in 3s"
+func relativeAge(age time.Duration) string {
+	prefix := ""
+	if age < 0 {
+		prefix, age = "in ", -age
+	}
+	switch {
+	case age < time.Minute:
+		return fmt.Sprintf("%s%ds", prefix, int(age/time.Second))
+	case age < time.Hour:
+		return fmt.Sprintf("%s%dm", prefix, int(age/time.Minute))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%s%dh", prefix, int(age/time.Hour))
+	default:
+		return fmt.Sprintf("%s%dd", prefix, int(age/(24*time.Hour)))
+	}
+}
+
+// cycleTimeColumn steps the TIME column through absolute times, relative
+// times and hidden
+func (m *UnifiedModel) cycleTimeColumn() {
+	switch {
+	case !m.showTime:
+		m.showTime = true
+	case !m.relativeTime:
+		m.relativeTime = true
+	default:
+		m.showTime, m.relativeTime = false, false
+	}
+}
+
+// displayZones lists the zones Z cycles through: the configured one, UTC
+// and the local zone
+func (m *UnifiedModel) displayZones() []*time.Location {
+	candidates := []*time.Location{time.UTC, time.Local}
+	if configured, err := time.LoadLocation(m.config.Timezone); err == nil {
+		candidates = append([]*time.Location{configured}, candidates...)
+	}
+
+	var zones []*time.Location
+	seen := make(map[string]bool)
+	for _, zone := range candidates {
+		if !seen[zone.String()] {
+			seen[zone.String()] = true
+			zones = append(zones, zone)
+		}
+	}
+	return zones
+}
+
+// cycleDisplayZone switches to the next display zone. Entries keep their
+// parsed time, so nothing is parsed again
+func (m *UnifiedModel) cycleDisplayZone() {
+	zones := m.displayZones()
+	next := zones[0]
+	for i, zone := range zones {
+		if zone.String() == m.displayZone.String() {
+			next = zones[(i+1)%len(zones)]
+			break
+		}
+	}
+	m.displayZone = next
+	m.notice = "Timezone: " + next.String()
+}
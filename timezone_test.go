@@ -0,0 +1,101 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSourceTimezones(t *testing.T) {
+	zone, zones, err := parseSourceTimezones([]string{"America/New_York", "db.log=Asia/Tokyo"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if zone.String() != "America/New_York" || zones["db.log"].String() != "Asia/Tokyo" {
+		t.Errorf("Unexpected zones %v and %v", zone, zones)
+	}
+
+	if _, _, err := parseSourceTimezones([]string{"db.log=Mars/Olympus"}); err == nil {
+		t.Error("Expected an error for an unknown zone")
+	}
+}
+
+func TestLogParser_SourceTimezone(t *testing.T) {
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	parser := newLogParser(nil, map[string]*time.Location{"db": tokyo})
+
+	// Timestamps without an offset are read in the source's zone
+	entry := parser.ParseLogLine("2023-12-23 15:30:45 INFO: query", "/var/log/db.log")
+	if !entry.Time.Equal(time.Date(2023, 12, 23, 6, 30, 45, 0, time.UTC)) || entry.Timestamp != "2023-12-23T06:30:45Z" {
+		t.Errorf("Expected the Tokyo time in UTC, got %v (%s)", entry.Time, entry.Timestamp)
+	}
+
+	// Other sources default to UTC, and offsets always win
+	entry = parser.ParseLogLine("2023-12-23 15:30:45 INFO: request", "/var/log/app.log")
+	if entry.Timestamp != "2023-12-23T15:30:45Z" {
+		t.Errorf("Expected UTC for other sources, got %s", entry.Timestamp)
+	}
+	entry = parser.ParseLogLine("2023-12-23T15:30:45+02:00 INFO: query", "/var/log/db.log")
+	if entry.Timestamp != "2023-12-23T13:30:45Z" {
+		t.Errorf("Expected the written offset to be used, got %s", entry.Timestamp)
+	}
+
+	// Lines without a timestamp have no parsed time
+	if entry := parser.ParseLogLine("no timestamp here", "/var/log/db.log"); !entry.Time.IsZero() {
+		t.Errorf("Expected no parsed time, got %v", entry.Time)
+	}
+}
+
+func TestDisplayZone_CyclesWithoutReparsing(t *testing.T) {
+	model := newIndexedTestModel(t, []string{"2023-12-23 15:30:45 INFO: hello"}, 120, 40)
+	model.config.Timezone = "Asia/Tokyo"
+	model.displayZone, _ = time.LoadLocation("Asia/Tokyo")
+
+	if view := model.renderLogStream(); !strings.Contains(view, "12-24T00:30:45+09:00") {
+		t.Fatalf("Expected the time in Tokyo, got:\n%s", view)
+	}
+	cached := model.visibleEntries[0]
+
+	model.Update(keyMsg("Z"))
+	if model.displayZone != time.UTC {
+		t.Fatalf("Expected Z to switch to UTC, got %v", model.displayZone)
+	}
+	if view := model.renderLogStream(); !strings.Contains(view, "12-23T15:30:45Z") {
+		t.Errorf("Expected the time in UTC, got:\n%s", view)
+	}
+	if model.visibleEntries[0].Timestamp != cached.Timestamp {
+		t.Errorf("Expected entries to be left as parsed, got %s", model.visibleEntries[0].Timestamp)
+	}
+
+	model.Update(keyMsg("Z"))
+	model.Update(keyMsg("Z"))
+	if model.displayZone.String() != "Asia/Tokyo" {
+		t.Errorf("Expected Z to cycle back to the configured zone, got %v", model.displayZone)
+	}
+}
+
+func TestRelativeAge(t *testing.T) {
+	testCases := []struct {
+		age      time.Duration
+		expected string
+	}{
+		{3 * time.Second, "3s"},
+		{2*time.Minute + 30*time.Second, "2m"},
+		{time.Hour, "1h"},
+		{50 * time.Hour, "2d"},
+		{-3 * time.Second, "in 3s"},
+	}
+
+	for _, tc := range testCases {
+		if age := relativeAge(tc.age); age != tc.expected {
+			t.Errorf("Expected %q for %v, got %q", tc.expected, tc.age, age)
+		}
+	}
+
+	// Lines without a parsed time show their timestamp as is
+	model := NewUnifiedModel(&Config{Timezone: "UTC"})
+	model.relativeTime = true
+	if ts := model.columnTimestamp(LogEntry{Timestamp: "2023-12-23T15:30:45Z"}); ts != "2023-12-23T15:30:45Z" {
+		t.Errorf("Expected the raw timestamp, got %q", ts)
+	}
+}
@@ -1,16 +1,115 @@
 package main
 
 import (
+	"time"
+
 	"github.com/charmbracelet/lipgloss"
 )
 
 type Config struct {
 	MaxLines    int
 	Files       []string
-	RefreshRate int
+	RefreshRate float64 // Seconds between file checks; the redraw tick is a twentieth of it, see tickInterval
+	FlushInterval time.Duration // Longest a streamed line waits for its batch (0 = 20ms)
 	Include     string
 	Exclude     string
-	Timezone    string
+	SourceFilter string // Only entries whose source matches, comma-separated
+	
+	// Filter options given on the command line, over the saved ones.
+	// Levels are the levels shown (nil = not given)
+	UseRegex      bool
+	Fuzzy         bool // Patterns match their characters in order, see fuzzyMatch
+	CaseSensitive bool
+	MatchAll      bool
+	MatchOnly     bool // Drop the lines no include pattern matched, instead of keeping them unmarked
+	Levels        []LogLevel
+	Timezone    string // Display timezone
+	
+	// SourceZone reads timestamps written without an offset (nil = UTC),
+	// SourceZones overrides it per source name
+	SourceZone  *time.Location
+	SourceZones map[string]*time.Location
+	
+	// LevelKeywords set the level of plain text lines they start (nil = built-in detection)
+	LevelKeywords []levelKeyword
+	
+	// Format forces a parser; with ParserAuto the format of each source is
+	// settled from its first FormatSample lines (0 = try every parser always)
+	Format       ParserKind
+	FormatSample int
+	
+	// Since and Until bound entry timestamps; absolute or relative like -10m
+	Since string
+	Until string
+
+	// NoTime hides the TIME column
+	NoTime bool
+
+	// TimePrecision is how many fractional second digits times show (0, 3,
+	// 6 or 9)
+	TimePrecision int
+
+	// ShowComponent adds the COMPONENT column
+	ShowComponent bool
+
+	// NoStats skips counting entries per level, hiding the counts and summary
+	NoStats bool
+
+	// WrapMarkers marks rows that continue a wrapped message with ↳
+	WrapMarkers bool
+
+	// KeepColors shows the ANSI colors of plain text lines, which are still
+	// matched without them
+	KeepColors bool
+
+	// NoFollow stops watching the file for appended lines
+	NoFollow bool
+
+	// MaxLineBytes cuts longer messages; the detail view reads indexed lines
+	// again in full (0 = no limit)
+	MaxLineBytes int
+
+	// AlertOn flashes the header, and rings the bell unless NoBell, when an
+	// entry at AlertLevel or above arrives while tailing
+	AlertOn    bool
+	AlertLevel LogLevel
+	NoBell     bool
+
+	// Summary writes line, level and message counts to stderr on exit
+	Summary bool
+
+	// Tail starts on the last Tail lines and follows new ones, like tail -n.
+	// 0 starts at the first line
+	Tail int
+
+	// Merge shows several files as one timeline ordered by timestamp
+	Merge bool
+
+	// Follow merges the files live, adding new files in FollowDirs
+	Follow     bool
+	FollowDirs []string
+
+	// StdinFileList means stdin held the list of files, not log lines
+	StdinFileList bool
+
+	// AlsoTail files are followed from their end and streamed in with
+	// piped input, each line tagged with its file
+	AlsoTail []string
+
+	// JournalUnit reads this systemd unit's journal instead of files or stdin
+	JournalUnit string
+
+	// StatePath is where filters are saved between sessions (empty = disabled)
+	StatePath string
+
+	// Redactor scrubs sensitive data from displayed messages (nil = disabled)
+	Redactor *Redactor
+
+	// ErrorCatalog describes error codes in the detail view (nil = disabled)
+	ErrorCatalog *ErrorCatalog
+
+	// MaxIndexMemory caps the line index size in bytes (0 = unlimited)
+	MaxIndexMemory int64
 }
 
 type LogLevel int
@@ -37,28 +136,41 @@ func (l LogLevel) String() string {
 	}
 }
 
-func (l LogLevel) Color() lipgloss.Color {
+// Color returns the level color, with a darker variant for light terminal backgrounds
+func (l LogLevel) Color() lipgloss.AdaptiveColor {
 	switch l {
 	case DEBUG:
-		return lipgloss.Color("8") // Gray
+		return lipgloss.AdaptiveColor{Light: "244", Dark: "8"} // Gray
 	case INFO:
-		return lipgloss.Color("12") // Light Blue
+		return lipgloss.AdaptiveColor{Light: "26", Dark: "12"} // Blue
 	case WARN:
-		return lipgloss.Color("11") // Yellow
+		return lipgloss.AdaptiveColor{Light: "136", Dark: "11"} // Yellow
 	case ERROR:
-		return lipgloss.Color("9") // Red
+		return lipgloss.AdaptiveColor{Light: "160", Dark: "9"} // Red
 	default:
-		return lipgloss.Color("15") // White
+		return lipgloss.AdaptiveColor{Light: "0", Dark: "15"} // Black/White
 	}
 }
 
 type LogEntry struct {
+	// Timestamp is the parsed time in UTC, RFC3339, or the time the line was
+	// read when it has none. Time is zero then; the display zone is applied
+	// when rendering
 	Timestamp string
+	Time      time.Time
 	Level     LogLevel
 	Message   string
 	Source    string
+	Component string // Logger or module that emitted the entry, unlike the file in Source
 	Raw       string
 	Metadata  map[string]interface{}
+	
+	// Where the line lies in its source file; Length is 0 when not read from a file
+	Offset    int64
+	Length    int
+	
+	// Truncated is how many bytes were cut from Message, see --max-line-bytes
+	Truncated int
 }
 
 type CircularBuffer struct {
@@ -67,6 +179,7 @@ type CircularBuffer struct {
 	tail    int
 	size    int
 	maxSize int
+	evicted int // Entries overwritten while the buffer was full, kept by Clear
 }
 
 func NewCircularBuffer(maxSize int) *CircularBuffer {
@@ -84,6 +197,7 @@ func (cb *CircularBuffer) Add(entry LogEntry) {
 		cb.size++
 	} else {
 		cb.tail = (cb.tail + 1) % cb.maxSize
+		cb.evicted++
 	}
 }
 
@@ -100,12 +214,28 @@ func (cb *CircularBuffer) GetAll() []LogEntry {
 	return result
 }
 
+func (cb *CircularBuffer) Len() int {
+	return cb.size
+}
+
+// Clear empties the buffer, keeping its storage
+func (cb *CircularBuffer) Clear() {
+	cb.head, cb.tail, cb.size = 0, 0, 0
+}
+
+// EvictedCount returns how many entries were overwritten by newer ones since
+// the buffer was created, entries that were never read
+func (cb *CircularBuffer) EvictedCount() int {
+	return cb.evicted
+}
+
 // Panel focus types
 type PanelFocus int
 
 const (
 	LeftPanel PanelFocus = iota
 	RightPanel
+	PreviewPanel
 )
 
 // View modes
@@ -114,6 +244,8 @@ type ViewMode int
 const (
 	LogStreamView ViewMode = iota
 	DetailView
+	CopyView
+	TemplatesView
 )
 
 // Input fields
@@ -124,6 +256,73 @@ const (
 	excludeInput
 )
 
+// Left panel items, in navigation order
+const (
+	includeItem = iota
+	excludeItem
+	sourceFilterItem
+	sinceItem
+	untilItem
+	regexItem
+	fuzzyItem
+	caseItem
+	matchAllItem
+	matchOnlyItem
+	rowColorItem
+	minLevelItem
+	errorItem
+	warnItem
+	infoItem
+	debugItem
+	liveItem
+	sourceItem
+	leftPanelItemCount
+)
+
 // Messages for TUI
 type LogEntryMsg LogEntry
-type LogBatchMsg []LogEntry
\ No newline at end of file
+
+// streamReadyMsg tells the model that streamed entries are waiting in its
+// streamBuffer
+type streamReadyMsg struct{}
+
+// shutdownMsg asks the model to quit cleanly, e.g. on SIGTERM
+type shutdownMsg struct{}
+
+// Indexing runs off the event loop, so what it finds reaches the model as
+// messages rather than writes to its fields, which Update and View read
+
+// indexingMsg shows the progress of an indexer starting on filename, nil
+// while merged files are ordered
+type indexingMsg struct {
+	indexer  *FastIndexer
+	filename string
+}
+
+// indexedMsg hands a finished indexer to the model, which closes the one it
+// replaces after a re-index
+type indexedMsg struct {
+	indexer  LineIndexer
+	filename string
+	took     time.Duration
+	replaced LineIndexer
+}
+
+// followIndexedMsg hands a file that joined the merged timeline while
+// following to the model, indexed off the event loop
+type followIndexedMsg struct {
+	merged   *MergedIndexer
+	indexer  *FastIndexer
+	filename string
+	err      error
+}
+
+// followingMsg tells the model its file is watched with fsnotify, so it
+// doesn't need polling
+type followingMsg struct{}
+
+// loadFailedMsg reports a file that couldn't be indexed
+type loadFailedMsg struct {
+	filename string
+	err      error
+}
\ No newline at end of file
@@ -2,106 +2,270 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
 )
 
+// tailFallbackBytes is how much of the end of a file is shown when indexing
+// is cancelled on a slow filesystem
+const tailFallbackBytes = 4 * 1024 * 1024
+
+// shutdownTimeout bounds a graceful shutdown after a signal before the
+// program is killed outright
+const shutdownTimeout = 3 * time.Second
+
 // UnifiedApp is the single fast version with all features
 type UnifiedApp struct {
 	config  *Config
 	model   *UnifiedModel
 	program *tea.Program
+	watcher *fsnotify.Watcher
+	
+	// journalCursor is where the last session stopped reading JournalUnit
+	journalCursor string
 }
 
 func NewUnifiedApp(config *Config) *UnifiedApp {
 	model := NewUnifiedModel(config)
+	
+	// Restore the last session's filters; filters given on the command line win
+	if config.StatePath != "" {
+		model.setFilterState(config.applyFilterFlags(LoadConfig(config.StatePath)))
+	}
+	
 	return &UnifiedApp{
-		config: config,
-		model:  model,
+		config:        config,
+		model:         model,
+		journalCursor: model.journalCursors[config.JournalUnit],
 	}
 }
 
 func (a *UnifiedApp) Run() error {
-	// Create the Bubbletea program
-	a.program = tea.NewProgram(a.model, tea.WithAltScreen())
+	// Create the Bubbletea program. Signals are handled here so they go
+	// through the same shutdown path as pressing q
+	a.program = tea.NewProgram(a.model, tea.WithAltScreen(), tea.WithMouseCellMotion(), tea.WithoutSignalHandler())
+	return a.runProgram()
+}
+
+// runProgram runs the program set up by Run until it quits, shutting it down
+// on SIGINT, SIGTERM or SIGHUP
+func (a *UnifiedApp) runProgram() error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(signals)
+	done := make(chan struct{})
+	defer close(done)
+	go a.handleSignals(signals, done)
 	
 	// Start processing input in background
 	go a.processInput()
 	
 	// Run the program
-	if _, err := a.program.Run(); err != nil {
+	_, err := a.program.Run()
+	if a.watcher != nil {
+		a.watcher.Close()
+	}
+	if a.model.summary != nil {
+		a.model.summary.write(summaryOut, a.model.displayZone)
+	}
+	if err != nil {
 		return fmt.Errorf("failed to run program: %w", err)
 	}
 	
 	return nil
 }
 
+// handleSignals asks the model to shut down on the first signal, closing files
+// and saving state, and kills the program if that takes too long. It gives up
+// once done is closed, when the program has returned
+func (a *UnifiedApp) handleSignals(signals <-chan os.Signal, done <-chan struct{}) {
+	select {
+	case <-signals:
+	case <-done:
+		return
+	}
+	timeout := time.NewTimer(shutdownTimeout)
+	defer timeout.Stop()
+	a.program.Send(shutdownMsg{})
+	
+	select {
+	case <-done:
+	case <-timeout.C:
+		// Kill restores the terminal once the event loop notices; if the
+		// loop itself is stuck, give up and exit
+		a.program.Kill()
+		select {
+		case <-done:
+		case <-time.After(shutdownTimeout / 10):
+			os.Exit(1)
+		}
+	}
+}
+
 func (a *UnifiedApp) processInput() {
 	// Small delay to ensure program is initialized
 	time.Sleep(10 * time.Millisecond)
 	
-	// Check if we have piped input
+	// Read the journal instead of stdin or files
+	if unit := a.config.JournalUnit; unit != "" {
+		if err := a.streamJournal(unit, a.journalCursor); err != nil {
+			a.send(loadFailedMsg{filename: "journal for " + unit, err: err})
+		}
+		return
+	}
+	
+	// Check if we have piped input, unless it listed the files
 	stat, err := os.Stdin.Stat()
-	if err == nil && (stat.Mode()&os.ModeCharDevice) == 0 {
-		// Piped input - use streaming mode
-		a.streamFromStdin()
+	piped := err == nil && (stat.Mode()&os.ModeCharDevice) == 0 && !a.config.StdinFileList
+	
+	// Files given with --also-tail stream in next to piped input, merged by arrival
+	if piped || (len(a.config.AlsoTail) > 0 && len(a.config.Files) == 0) {
+		for _, file := range a.config.AlsoTail {
+			go a.tailInto(file)
+		}
+		if piped {
+			// Piped input - use streaming mode
+			a.streamFromStdin()
+		}
+		return
+	}
+	
+	// Named pipes are streamed, they can't be indexed. The other files are
+	// indexed as usual, with the piped lines shown after theirs
+	files := a.config.Files
+	if pipes := namedPipes(files); len(pipes) > 0 {
+		a.streamPipes(pipes)
+		files = regularFiles(files)
+		if len(files) == 0 {
+			return
+		}
+	}
+	
+	// Show several files as one timeline, live with --follow
+	if a.config.Follow || (a.config.Merge && len(files) > 1) {
+		if a.mergeFiles(files) && a.config.Follow {
+			if err := a.followDirs(); err == nil {
+				a.send(followingMsg{})
+			}
+		}
 		return
 	}
 	
 	// Process files if specified
-	if len(a.config.Files) > 0 {
-		for _, file := range a.config.Files {
-			a.indexFile(file)
+	for _, file := range files {
+		a.indexFile(file)
+	}
+	
+	// Follow a single file for appended lines unless --no-follow
+	if len(files) == 1 && !a.config.NoFollow {
+		if err := a.followFile(files[0]); err == nil {
+			a.send(followingMsg{})
 		}
 	}
 }
 
 func (a *UnifiedApp) indexFile(filename string) {
+	start := time.Now()
+	indexer := a.buildIndex(filename)
+	if indexer == nil {
+		return
+	}
+	
+	a.send(indexedMsg{indexer: indexer, filename: filename, took: time.Since(start)})
+}
+
+// mergeFiles indexes each file on its own and shows their lines as a single
+// timeline. Files that can't be indexed are left out. Followed directories
+// may start out empty. It reports whether the model got a timeline
+func (a *UnifiedApp) mergeFiles(files []string) bool {
+	start := time.Now()
+	var indexers []*FastIndexer
+	for _, file := range files {
+		if indexer := a.buildIndex(file); indexer != nil {
+			indexers = append(indexers, indexer)
+		}
+	}
+	name := ""
+	if len(indexers) > 0 {
+		name = indexers[0].filename
+	} else if a.config.Follow && len(a.config.FollowDirs) > 0 {
+		name = a.config.FollowDirs[0]
+	} else {
+		return false
+	}
+	
+	// Ordering parses every line, so keep showing the indexing status
+	a.send(indexingMsg{filename: name})
+	merged := NewMergedIndexer(indexers)
+	
+	a.send(indexedMsg{indexer: merged, filename: name, took: time.Since(start)})
+	return true
+}
+
+// buildIndex indexes a file while the model shows its progress. It returns
+// nil when the file doesn't exist or couldn't be read, reporting read errors
+// to the model
+func (a *UnifiedApp) buildIndex(filename string) *FastIndexer {
 	// Check if file exists
 	if _, err := os.Stat(filename); err != nil {
-		return
+		return nil
 	}
 	
 	// Create fast indexer
 	indexer, err := NewFastIndexer(filename, a.model.parser)
 	if err != nil {
-		return
+		return nil
 	}
+	indexer.SetMemoryLimit(a.config.MaxIndexMemory)
 	
-	// Update model state
-	a.model.indexing = true
-	a.model.loadingFile = filename
+	a.send(indexingMsg{indexer: indexer, filename: filename})
 	
-	// Start indexing
-	start := time.Now()
-	if err := indexer.IndexFileUltraFast(); err != nil {
+	// Start indexing. Cancelling falls back to the end of the file only
+	err = indexer.IndexFileUltraFast()
+	if errors.Is(err, ErrIndexCancelled) {
+		err = indexer.IndexTail(tailFallbackBytes)
+	}
+	if err != nil {
 		indexer.Close()
-		return
+		a.send(loadFailedMsg{filename: filename, err: err})
+		return nil
 	}
-	
-	// Update model with indexer
-	a.model.indexTime = time.Since(start)
-	a.model.SetIndexer(indexer, filename)
+	return indexer
 }
 
 func (a *UnifiedApp) streamFromStdin() {
-	scanner := bufio.NewScanner(os.Stdin)
-	buf := make([]byte, 0, 64*1024)
-	scanner.Buffer(buf, 1024*1024)
+	a.streamFrom(os.Stdin, "stdin")
+}
+
+// streamFrom parses the lines read from r as entries of source and sends them
+// to the model in batches
+func (a *UnifiedApp) streamFrom(r io.Reader, source string) {
+	reader := bufio.NewReaderSize(r, 64*1024)
 	
 	batch := make([]LogEntry, 0, 100)
 	lastSend := time.Now()
+	flush := flushInterval(a.config.FlushInterval)
 	
-	for scanner.Scan() {
-		line := scanner.Text()
-		entry := a.model.parser.ParseLogLine(line, "stdin")
+	// Only the part of a huge line that's shown is kept, a stream can't be
+	// read again for the detail view
+	for {
+		line, dropped, err := readLongLine(reader, a.config.MaxLineBytes)
+		if err != nil {
+			break
+		}
+		entry := a.model.parser.parseCut(line, source, dropped)
 		batch = append(batch, entry)
 		
 		// Send batch
-		if len(batch) >= 100 || time.Since(lastSend) > 20*time.Millisecond {
+		if len(batch) >= 100 || time.Since(lastSend) > flush {
 			a.sendBatch(batch)
 			batch = batch[:0]
 			lastSend = time.Now()
@@ -114,8 +278,21 @@ func (a *UnifiedApp) streamFromStdin() {
 	}
 }
 
+// send hands a message to the model through the program, so that only
+// Update changes the model while View may be reading it. Without a program,
+// as in tests, the model takes it right away
+func (a *UnifiedApp) send(msg tea.Msg) {
+	if a.program != nil {
+		a.program.Send(msg)
+		return
+	}
+	a.model.Update(msg)
+}
+
+// sendBatch queues entries for the model, waking it only if it has drained
+// the previous batch so a fast pipe can't flood the event loop
 func (a *UnifiedApp) sendBatch(entries []LogEntry) {
-	if a.program != nil && len(entries) > 0 {
-		a.program.Send(LogBatchMsg(entries))
+	if len(entries) > 0 && a.model.stream.Push(entries) && a.program != nil {
+		a.program.Send(streamReadyMsg{})
 	}
 }
\ No newline at end of file
@@ -1,9 +1,12 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -11,13 +14,14 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 // UnifiedModel combines fast indexing with full feature set
 type UnifiedModel struct {
 	config  *Config
 	parser  *LogParser
-	indexer *FastIndexer
+	indexer LineIndexer
 	
 	// Virtual scrolling state
 	visibleEntries  []LogEntry
@@ -28,6 +32,8 @@ type UnifiedModel struct {
 	// Testing support
 	entries         []LogEntry     // All entries (for testing)
 	filteredEntries []LogEntry     // Filtered entries (for testing)
+	stream          *streamBuffer  // Entries piped in but not yet added
+	streamStarted   bool           // The first batch was shown, see startStream
 	
 	// UI state
 	focus           PanelFocus
@@ -37,44 +43,122 @@ type UnifiedModel struct {
 	scrollOffset    int
 	viewMode        ViewMode
 	leftWidth       int
+	leftPref        int // Filters panel width chosen with < and >, 0 = from the terminal width
 	rightWidth      int
 	tailing         bool
+	paused          bool // Frozen with p or space until resumed
+	pausedLines     int  // Lines received while paused
+	followMatches   bool // Jump to new lines matching the search or include patterns
 	lastGPress      int64
+	lastDPress      int64
+	lastYPress      int64
+	lastClick       int64 // Last click on an entry, a second one opens it
 	fullscreen      bool
-	
+	splitView       bool
+	previewScroll   int
+	showTime        bool
+	relativeTime    bool // TIME column shows ages like 3s instead of timestamps
+	displayZone     *time.Location // Zone timestamps are shown in, cycled with Z
+	showComponent   bool
+	showDuration    bool // DURATION column with duration_ms, toggled with O
+	sortMode        sortMode // Order of the filtered entries, cycled with o
+	collapse        bool // Runs of the same message show as one row, toggled with D
+	runs            []collapseRun // Run shown at each position while collapsed
+	collapsed       int // Entries folded into the rows of their runs
+	wrapList        bool // Wrap long messages over several rows instead of cutting them
+	showSummary     bool // Left panel charts the filtered entries per level
+	showHelp        bool // The key bindings overlay is open
+	wrapMarkers     bool
+	showUnredacted  bool
+	rowColorMode    bool
+	markLine        int // File line where a visual selection starts (-1 = none)
+	bookmarks       map[int]bool // Bookmarked file lines, toggled with m
+	suppressed      map[string]bool // Messages hidden with x, shown again with X
+	lineRate        rateMeter // How fast new lines arrive, shown in the header
+	alertUntil      time.Time // The header flashes until then for a new error
+	lastAlert       time.Time
+	full            *fullLine // Selected line read again in full, see fullEntry
+	summary         *runSummary // What was read, written on exit with --summary
+	copyOptions     []copyOption
+	copyIdx         int
+	templates       []TemplateCount // Ranked by the analysis view
+	templateTotal   int
+	templateScroll  int
+
 	// Filter inputs
 	includeInput    textinput.Model
 	excludeInput    textinput.Model
+	sourceInput     textinput.Model
+	sinceInput      textinput.Model
+	untilInput      textinput.Model
 	activeInput     *textinput.Model
+	searchInput     textinput.Model
+	labelInput      textinput.Model // Renames the shown source
+	searching       bool   // Search prompt is open
+	searchQuery     string
+	searchMatches   []int  // Positions in filteredIndices matching searchQuery
+	searchIdx       int
+	gotoInput       textinput.Model
+	gotoOpen        bool      // Line number prompt is open
+	gotoTime        bool      // The prompt asks for a time instead, opened with @
+	flashLine       int       // File line highlighted after a jump
+	flashUntil      time.Time
 	useRegex        bool
+	fuzzy           bool // Patterns match fuzzily, never together with useRegex
 	caseSensitive   bool
+	matchAll        bool // Every include pattern must match instead of any
+	matchOnly       bool // Only lines an include pattern matched are shown
 	
 	// Left panel navigation
-	leftPanelItem   int
-	editMode        bool
+	leftPanelItem    int
+	leftScrollOffset int
+	leftItemLines    map[int]int     // Content line of each item at the last render, for clicks
+	editMode         bool
+	editStart        filterPatterns  // Patterns when the current edit started
+	clearedPatterns  *filterPatterns // Patterns before one was cleared, restored with u
+	includeHistory   []string        // Applied include patterns, oldest first
+	excludeHistory   []string        // Applied exclude patterns, oldest first
+	historyPos       int             // History entry shown while editing (-1 = none)
+	historyDraft     string          // What was typed before browsing the history
 	
 	// Log level filters
 	showDebug       bool
 	showInfo        bool
 	showWarn        bool
 	showError       bool
+	minLevel        LogLevel // Least severe level shown while levelThreshold is on
+	levelThreshold  bool     // Levels follow minLevel (+/-) rather than the toggles
 	
 	// Filtered indices for search
 	filteredIndices []int
 	matchedIndices  []int
+	filterStats     *filterStats // Last filter pass, nil until filters are applied
+	streamLevels    levelCounts  // Streamed entries at each level, kept as they arrive
+	streamShown     levelCounts  // Streamed entries at each level that pass the filters
+	journalCursors  map[string]string
+	sourceLabels    map[string]string // Names given to sources, by path
 	currentMatchIdx int
 	
 	// Status
 	indexing        bool
+	reindexing      bool // The indexing run replaces a loaded index, reloaded with r
 	indexTime       time.Duration
+	indexStart      time.Time
 	loadingFile     string
+	loadingIndexer  *FastIndexer
+	loadError       string
+	following       bool
+	followIndexing  map[string]bool // Files being indexed to join the merged timeline
+	pipes           []string // Named pipes being streamed, noted in the header
+	notice          string
 	lastModTime     time.Time
-	tickCounter     int
+	lastPoll        time.Time // When an unfollowed file was last checked for changes
 	
 	// Styles
 	focusedStyle    lipgloss.Style
 	blurredStyle    lipgloss.Style
 	selectedStyle   lipgloss.Style
+	flashStyle      lipgloss.Style
 	headerStyle     lipgloss.Style
 	levelStyles     map[LogLevel]lipgloss.Style
 	
@@ -96,10 +180,50 @@ func NewUnifiedModel(config *Config) *UnifiedModel {
 		excludeInput.SetValue(config.Exclude)
 	}
 
+	sourceInput := textinput.New()
+	sourceInput.Placeholder = "Type a file or label..."
+	sourceInput.CharLimit = 256
+	sourceInput.SetValue(config.SourceFilter)
+
+	sinceInput := textinput.New()
+	sinceInput.Placeholder = "-10m or 2023-12-23 15:30:00"
+	sinceInput.CharLimit = 64
+	sinceInput.SetValue(config.Since)
+
+	untilInput := textinput.New()
+	untilInput.Placeholder = "-5m or 2023-12-23 15:45:00"
+	untilInput.CharLimit = 64
+	untilInput.SetValue(config.Until)
+
+	searchInput := textinput.New()
+	searchInput.Placeholder = "Search without filtering..."
+	searchInput.CharLimit = 256
+
+	displayZone, err := time.LoadLocation(config.Timezone)
+	if err != nil {
+		displayZone = time.UTC
+	}
+
+	labelInput := textinput.New()
+	labelInput.Placeholder = "Name this source..."
+	labelInput.CharLimit = 64
+
+	gotoInput := textinput.New()
+
+	parser := newLogParser(config.SourceZone, config.SourceZones)
+	parser.levelKeywords = config.LevelKeywords
+	parser.format = config.Format
+	parser.maxLineBytes = config.MaxLineBytes
+	if config.FormatSample > 0 {
+		parser.formats = newFormatCache(config.FormatSample)
+	}
+	
 	m := &UnifiedModel{
 		config:         config,
-		parser:         NewLogParser(config.Timezone),
+		parser:         parser,
+		displayZone:    displayZone,
 		visibleEntries: make([]LogEntry, 0),
+		stream:         newStreamBuffer(config.MaxLines),
 		focus:          RightPanel,
 		viewMode:       LogStreamView,
 		showDebug:      true,
@@ -108,36 +232,57 @@ func NewUnifiedModel(config *Config) *UnifiedModel {
 		showError:      true,
 		includeInput:   includeInput,
 		excludeInput:   excludeInput,
+		sourceInput:    sourceInput,
+		sinceInput:     sinceInput,
+		untilInput:     untilInput,
+		searchInput:    searchInput,
+		labelInput:     labelInput,
+		gotoInput:      gotoInput,
 		viewportHeight: 40,
 		tailing:        true,
+		showTime:       !config.NoTime,
+		showComponent:  config.ShowComponent,
+		wrapMarkers:    config.WrapMarkers,
+		markLine:       -1,
 		leftWidth:      40,
 		rightWidth:     100,
 	}
 
-	// Initialize styles
+	m.setFilterState(config.applyFilterFlags(m.filterState()))
+
+	// Initialize styles. Adaptive colors keep everything legible on both
+	// light and dark terminal backgrounds
 	m.focusedStyle = lipgloss.NewStyle().
 		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("69"))
+		BorderForeground(lipgloss.AdaptiveColor{Light: "25", Dark: "69"})
 
 	m.blurredStyle = lipgloss.NewStyle().
 		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("240"))
+		BorderForeground(lipgloss.AdaptiveColor{Light: "250", Dark: "240"})
 
 	m.selectedStyle = lipgloss.NewStyle().
-		Background(lipgloss.Color("235"))
+		Background(lipgloss.AdaptiveColor{Light: "254", Dark: "235"})
+
+	m.flashStyle = lipgloss.NewStyle().
+		Background(lipgloss.AdaptiveColor{Light: "229", Dark: "58"})
 
 	m.headerStyle = lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("229")).
-		Background(lipgloss.Color("57"))
+		Foreground(lipgloss.AdaptiveColor{Light: "255", Dark: "229"}).
+		Background(lipgloss.AdaptiveColor{Light: "62", Dark: "57"})
 
-	m.levelStyles = map[LogLevel]lipgloss.Style{
-		DEBUG: lipgloss.NewStyle().Foreground(lipgloss.Color("8")),
-		INFO:  lipgloss.NewStyle().Foreground(lipgloss.Color("12")),
-		WARN:  lipgloss.NewStyle().Foreground(lipgloss.Color("11")),
-		ERROR: lipgloss.NewStyle().Foreground(lipgloss.Color("9")),
+	// LogLevel.Color is the single source of truth for level colors
+	m.levelStyles = make(map[LogLevel]lipgloss.Style)
+	for _, level := range []LogLevel{DEBUG, INFO, WARN, ERROR} {
+		m.levelStyles[level] = lipgloss.NewStyle().Foreground(level.Color())
 	}
 
+	if config.Summary {
+		m.summary = newRunSummary()
+	}
+	m.pipes = namedPipes(config.Files)
+	m.lastPoll = time.Now()
+
 	return m
 }
 
@@ -150,7 +295,7 @@ func (m *UnifiedModel) Init() tea.Cmd {
 }
 
 func (m *UnifiedModel) tickCmd() tea.Cmd {
-	return tea.Tick(time.Millisecond*50, func(t time.Time) tea.Msg {
+	return tea.Tick(tickInterval(m.config.RefreshRate), func(t time.Time) tea.Msg {
 		return unifiedTickMsg(t)
 	})
 }
@@ -158,23 +303,26 @@ func (m *UnifiedModel) tickCmd() tea.Cmd {
 type unifiedTickMsg time.Time
 
 func (m *UnifiedModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	// Derived view state follows every change, so View only reads it
+	m.scrollLeftPanel()
+	m.loadFullEntry()
+	return model, cmd
+}
+
+func (m *UnifiedModel) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		m.viewportHeight = m.height - 10
-		
-		// Calculate fixed panel widths
-		m.leftWidth = m.width * 30 / 100
-		if m.leftWidth < 25 {
-			m.leftWidth = 25
-		}
-		if m.leftWidth > 40 {
-			m.leftWidth = 40
+		m.updateViewportHeight()
+		if !m.isSplit() && m.focus == PreviewPanel {
+			m.focus = RightPanel
 		}
-		m.rightWidth = m.width - m.leftWidth
+
+		m.layoutPanels()
 		
 		// Reload view for new size
 		m.loadVisibleLines()
@@ -186,16 +334,82 @@ func (m *UnifiedModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.loadVisibleLines()
 		}
 		
-		// Check for file changes every few ticks (based on RefreshRate)
-		m.tickCounter++
-		refreshInterval := m.config.RefreshRate * 20 // 20 ticks per second (50ms per tick)
-		if refreshInterval > 0 && m.tickCounter%refreshInterval == 0 && m.config.Files != nil && len(m.config.Files) > 0 {
-			m.checkFileChanges()
+		// Check for file changes every RefreshRate seconds, unless the
+		// file is already followed through fsnotify. A merged timeline
+		// isn't followed
+		now := time.Time(msg)
+		refreshInterval := pollInterval(m.config.RefreshRate)
+		polling := !m.following && !m.config.NoFollow && !m.config.Merge
+		var reindex tea.Cmd
+		if polling && refreshInterval > 0 && now.Sub(m.lastPoll) >= refreshInterval && len(m.config.Files) > 0 {
+			m.lastPoll = now
+			reindex = m.checkFileChanges()
 		}
 		
-		return m, m.tickCmd()
+		return m, tea.Batch(m.tickCmd(), reindex)
 		
+	case shutdownMsg:
+		return m, m.quit()
+
+	case indexingMsg:
+		m.startIndexing(msg.indexer, msg.filename)
+		return m, nil
+
+	case indexedMsg:
+		m.setIndexed(msg)
+		return m, nil
+
+	case followingMsg:
+		m.following = true
+		return m, nil
+
+	case loadFailedMsg:
+		m.SetLoadError(msg.filename, msg.err)
+		return m, nil
+
+	case fileChangedMsg:
+		first := m.totalLines
+		reindex := m.applyFileChange(msg)
+		return m, tea.Batch(reindex, m.alertForLines(first))
+
+	case followIndexedMsg:
+		first := m.totalLines
+		m.addFollowed(msg)
+		return m, m.alertForLines(first)
+
+	case alertClearMsg:
+		m.clearAlert(msg)
+		return m, nil
+
+	case streamReadyMsg:
+		entries := m.stream.Drain()
+		m.AddLogBatch(entries)
+		m.startStream()
+		return m, m.alertForEntries(entries)
+
+	case tea.MouseMsg:
+		return m.updateMouse(msg)
+
 	case tea.KeyMsg:
+		// Esc stops a slow indexing run and falls back to the end of the file
+		if m.indexing && m.loadingIndexer != nil && msg.String() == "esc" {
+			m.loadingIndexer.Cancel()
+			return m, nil
+		}
+
+		// The help overlay takes every key until it's closed
+		if m.showHelp {
+			switch msg.String() {
+			case "h", "esc", "q":
+				m.showHelp = false
+			}
+			return m, nil
+		}
+		if msg.String() == "h" && !m.editMode && !m.searching && !m.gotoOpen {
+			m.showHelp = true
+			return m, nil
+		}
+
 		// Handle detail view
 		if m.viewMode == DetailView {
 			switch msg.String() {
@@ -214,20 +428,71 @@ func (m *UnifiedModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		// Handle the copy menu
+		if m.viewMode == CopyView {
+			switch msg.String() {
+			case "esc", "q":
+				m.viewMode = LogStreamView
+			case "j", "down":
+				m.copyIdx = min(m.copyIdx+1, len(m.copyOptions)-1)
+			case "k", "up":
+				m.copyIdx = max(m.copyIdx-1, 0)
+			case "enter":
+				m.copySelected()
+			}
+			return m, nil
+		}
+
+		// Handle the analysis view
+		if m.viewMode == TemplatesView {
+			switch msg.String() {
+			case "esc", "q":
+				m.viewMode = LogStreamView
+			case "j", "down":
+				m.scrollTemplates(1)
+			case "k", "up":
+				m.scrollTemplates(-1)
+			}
+			return m, nil
+		}
+
+		// Handle the search prompt
+		if m.searching {
+			return m.updateSearch(msg)
+		}
+		
+		// Handle the line number prompt
+		if m.gotoOpen {
+			return m.updateGotoLine(msg)
+		}
+
 		// Handle edit mode
 		if m.editMode && m.activeInput != nil {
 			switch msg.String() {
 			case "esc":
+				m.rememberClearedPattern()
 				m.activeInput.Blur()
 				m.activeInput = nil
 				m.editMode = false
 				return m, nil
 			case "enter":
+				if m.activeInput == &m.labelInput {
+					m.setSourceLabel(m.loadingFile, m.labelInput.Value())
+				}
+				m.rememberClearedPattern()
+				m.rememberFilter()
 				m.activeInput.Blur()
 				m.activeInput = nil
 				m.editMode = false
 				m.applyFilters()
 				return m, nil
+			// A single line input has no use for up and down
+			case "up":
+				m.recallFilter(-1)
+				return m, nil
+			case "down":
+				m.recallFilter(1)
+				return m, nil
 			default:
 				var cmd tea.Cmd
 				*m.activeInput, cmd = m.activeInput.Update(msg)
@@ -240,39 +505,57 @@ func (m *UnifiedModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch msg.String() {
 		case "/":
 			m.focus = LeftPanel
-			m.leftPanelItem = 0
-			m.editMode = true
-			m.activeInput = &m.includeInput
-			m.includeInput.Focus()
-			return m, textinput.Blink
+			m.leftPanelItem = includeItem
+			return m, m.startEdit(&m.includeInput)
 			
 		case "\\":
 			m.focus = LeftPanel
-			m.leftPanelItem = 1
-			m.editMode = true
-			m.activeInput = &m.excludeInput
-			m.excludeInput.Focus()
-			return m, textinput.Blink
+			m.leftPanelItem = excludeItem
+			return m, m.startEdit(&m.excludeInput)
+			
+		case "u":
+			m.undoClear()
+			return m, nil
 			
 		case "f":
-			m.fullscreen = !m.fullscreen
-			// When entering fullscreen, focus on right panel and recalculate widths
-			if m.fullscreen {
-				m.focus = RightPanel
-				m.rightWidth = m.width
-			} else {
-				// When exiting fullscreen, restore original widths
-				m.leftWidth = 40
-				m.rightWidth = m.width - m.leftWidth
-			}
+			m.toggleLeftPanel()
+			return m, nil
+
+		case "<":
+			m.resizeLeftPanel(-leftWidthStep)
+			return m, nil
+
+		case ">":
+			m.resizeLeftPanel(leftWidthStep)
+			return m, nil
+
+		case "v":
+			m.toggleSplitView()
+			return m, nil
+
+		case "+":
+			m.stepMinLevel(1)
+			return m, nil
+
+		case "-":
+			m.stepMinLevel(-1)
 			return m, nil
 		}
 
 		// Navigation based on focus
 		if m.focus == LeftPanel {
 			return m.updateLeftPanel(msg)
+		} else if m.focus == PreviewPanel {
+			return m.updatePreviewPanel(msg)
 		} else {
-			return m.updateRightPanel(msg)
+			// The preview scroll belongs to the selected entry, so start
+			// from the top again whenever the selection moves
+			prevSelected := m.viewportStart + m.selectedIdx
+			model, cmd := m.updateRightPanel(msg)
+			if m.viewportStart+m.selectedIdx != prevSelected {
+				m.previewScroll = 0
+			}
+			return model, cmd
 		}
 	}
 
@@ -283,10 +566,7 @@ func (m *UnifiedModel) updateLeftPanel(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "q", "ctrl+c":
 		// Global quit - works from any panel
-		if m.indexer != nil {
-			m.indexer.Close()
-		}
-		return m, tea.Quit
+		return m, m.quit()
 		
 	case "tab":
 		m.focus = RightPanel
@@ -295,79 +575,193 @@ func (m *UnifiedModel) updateLeftPanel(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "j", "down":
 		if !m.editMode {
 			m.leftPanelItem++
-			if m.leftPanelItem > 8 {
+			if m.leftPanelItem >= leftPanelItemCount {
 				m.leftPanelItem = 0
 			}
 		}
 		return m, nil
-		
+
 	case "k", "up":
 		if !m.editMode {
 			m.leftPanelItem--
 			if m.leftPanelItem < 0 {
-				m.leftPanelItem = 8
+				m.leftPanelItem = leftPanelItemCount - 1
 			}
 		}
 		return m, nil
-		
+
 	case "i":
-		if m.leftPanelItem <= 1 {
-			m.editMode = true
-			if m.leftPanelItem == 0 {
-				m.activeInput = &m.includeInput
-				m.includeInput.Focus()
-			} else {
-				m.activeInput = &m.excludeInput
-				m.excludeInput.Focus()
-			}
-			return m, textinput.Blink
+		if input := m.inputForItem(m.leftPanelItem); input != nil {
+			return m, m.startEdit(input)
 		}
 		return m, nil
 		
+	case "c":
+		m.clearPatterns()
+		return m, nil
+
 	case " ", "enter":
 		switch m.leftPanelItem {
-		case 2:
+		case regexItem:
 			m.useRegex = !m.useRegex
+			m.fuzzy = m.fuzzy && !m.useRegex
+			m.applyFilters()
+		case fuzzyItem:
+			m.fuzzy = !m.fuzzy
+			m.useRegex = m.useRegex && !m.fuzzy
 			m.applyFilters()
-		case 3:
+		case caseItem:
 			m.caseSensitive = !m.caseSensitive
 			m.applyFilters()
-		case 4:
+		case matchAllItem:
+			m.matchAll = !m.matchAll
+			m.applyFilters()
+		case matchOnlyItem:
+			m.matchOnly = !m.matchOnly
+			m.applyFilters()
+		case rowColorItem:
+			m.rowColorMode = !m.rowColorMode
+		case minLevelItem:
+			// Cycles off, DEBUG and above, ... ERROR only, off
+			if m.levelThreshold && m.minLevel == ERROR {
+				m.useLevelToggles()
+			} else if m.levelThreshold {
+				m.setMinLevel(m.minLevel + 1)
+			} else {
+				m.setMinLevel(DEBUG)
+			}
+		case errorItem:
 			m.showError = !m.showError
+			m.useLevelToggles()
 			m.applyFilters()
-		case 5:
+		case warnItem:
 			m.showWarn = !m.showWarn
+			m.useLevelToggles()
 			m.applyFilters()
-		case 6:
+		case infoItem:
 			m.showInfo = !m.showInfo
+			m.useLevelToggles()
 			m.applyFilters()
-		case 7:
+		case debugItem:
 			m.showDebug = !m.showDebug
+			m.useLevelToggles()
 			m.applyFilters()
-		case 8:
-			m.tailing = !m.tailing
+		case liveItem:
 			if m.tailing {
-				m.scrollToBottom()
+				m.tailing = false
+			} else {
+				m.resumeTailing()
 			}
+		case sourceItem:
+			return m, m.startRenameSource()
+		}
+		return m, nil
+
+	case "r":
+		if m.leftPanelItem == sourceItem {
+			return m, m.startRenameSource()
 		}
 		return m, nil
 	}
-	
+
 	return m, nil
 }
 
+// quit releases the indexer and remembers the filter setup for next time
+func (m *UnifiedModel) quit() tea.Cmd {
+	m.summarizeIndex()
+	if m.indexer != nil {
+		m.indexer.Close()
+	}
+	if m.config.StatePath != "" {
+		SaveConfig(m.config.StatePath, m.filterState()) // Best effort, never blocks quitting
+	}
+	return tea.Quit
+}
+
+// filterState captures the current filter setup
+func (m *UnifiedModel) filterState() FilterState {
+	return FilterState{
+		Include:       m.includeInput.Value(),
+		Exclude:       m.excludeInput.Value(),
+		Source:        m.sourceInput.Value(),
+		UseRegex:      m.useRegex,
+		Fuzzy:         m.fuzzy,
+		CaseSensitive: m.caseSensitive,
+		MatchAll:      m.matchAll,
+		MatchOnly:     m.matchOnly,
+		ShowDebug:     m.showDebug,
+		ShowInfo:      m.showInfo,
+		ShowWarn:      m.showWarn,
+		ShowError:     m.showError,
+		
+		JournalCursors: m.journalCursors,
+		SourceLabels:   m.sourceLabels,
+		IncludeHistory: m.includeHistory,
+		ExcludeHistory: m.excludeHistory,
+		LeftWidth:      m.leftPref,
+	}
+}
+
+// setFilterState restores a filter setup, e.g. one saved by a previous session
+func (m *UnifiedModel) setFilterState(state FilterState) {
+	m.includeInput.SetValue(state.Include)
+	m.excludeInput.SetValue(state.Exclude)
+	m.sourceInput.SetValue(state.Source)
+	m.useRegex = state.UseRegex
+	m.fuzzy = state.Fuzzy && !state.UseRegex
+	m.caseSensitive = state.CaseSensitive
+	m.matchAll = state.MatchAll
+	m.matchOnly = state.MatchOnly
+	m.showDebug = state.ShowDebug
+	m.showInfo = state.ShowInfo
+	m.showWarn = state.ShowWarn
+	m.showError = state.ShowError
+	m.journalCursors = state.JournalCursors
+	m.sourceLabels = state.SourceLabels
+	m.includeHistory = state.IncludeHistory
+	m.excludeHistory = state.ExcludeHistory
+	m.leftPref = state.LeftWidth
+}
+
+// redact scrubs text for display unless redaction was toggled off with R
+func (m *UnifiedModel) redact(text string) string {
+	if m.showUnredacted {
+		return text
+	}
+	return m.config.Redactor.redact(text)
+}
+
+// inputForItem returns the text input behind a left panel item, or nil for toggles
+func (m *UnifiedModel) inputForItem(item int) *textinput.Model {
+	switch item {
+	case includeItem:
+		return &m.includeInput
+	case excludeItem:
+		return &m.excludeInput
+	case sourceFilterItem:
+		return &m.sourceInput
+	case sinceItem:
+		return &m.sinceInput
+	case untilItem:
+		return &m.untilInput
+	}
+	return nil
+}
+
 func (m *UnifiedModel) updateRightPanel(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "q", "ctrl+c":
-		if m.indexer != nil {
-			m.indexer.Close()
-		}
-		return m, tea.Quit
-		
+		return m, m.quit()
+
 	case "tab":
-		m.focus = LeftPanel
+		if m.isSplit() {
+			m.focus = PreviewPanel
+		} else {
+			m.focus = LeftPanel
+		}
 		return m, nil
-		
+
 	case "enter":
 		if m.selectedIdx >= 0 && m.selectedIdx < len(m.visibleEntries) {
 			m.viewMode = DetailView
@@ -409,29 +803,260 @@ func (m *UnifiedModel) updateRightPanel(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.lastGPress = now
 		return m, nil
 		
+	case "y":
+		m.lastYPress = time.Now().UnixNano()
+		return m, nil
+
+	case "c":
+		if time.Now().UnixNano()-m.lastYPress < 500000000 {
+			m.openCopyMenu()
+		} else {
+			m.clearPatterns()
+		}
+		m.lastYPress = 0
+		return m, nil
+
+	case "l":
+		if time.Now().UnixNano()-m.lastYPress < 500000000 {
+			m.copyLineReference()
+		}
+		m.lastYPress = 0
+		return m, nil
+		
+	case "?":
+		return m, m.startSearch()
+
+	case ":":
+		return m, m.startGotoLine()
+
+	case "@":
+		return m, m.startGotoTime()
+
+	case "esc":
+		if m.searchQuery != "" {
+			m.setSearchQuery("")
+		}
+		return m, nil
+		
 	case "n":
-		m.nextMatch()
+		if m.searchQuery != "" {
+			m.stepSearch(1)
+		} else {
+			m.nextMatch()
+		}
 		return m, nil
 		
 	case "N":
-		m.prevMatch()
+		if m.searchQuery != "" {
+			m.stepSearch(-1)
+		} else {
+			m.prevMatch()
+		}
 		return m, nil
 		
 	case "t":
-		m.tailing = !m.tailing
 		if m.tailing {
-			m.scrollToBottom()
+			m.tailing = false
+		} else {
+			m.resumeTailing()
+		}
+		return m, nil
+
+	case "p", " ":
+		m.togglePause()
+		return m, nil
+
+	case "F":
+		m.toggleFollowMatches()
+		return m, nil
+
+	case "]":
+		m.jumpToLevelChange(1)
+		return m, nil
+
+	case "[":
+		m.jumpToLevelChange(-1)
+		return m, nil
+
+	case "m":
+		m.toggleBookmark()
+		return m, nil
+
+	case "x":
+		m.suppressSelected()
+		return m, nil
+
+	case "d":
+		now := time.Now().UnixNano()
+		if now-m.lastDPress < 500000000 {
+			m.suppressSelected()
+			now = 0
+		}
+		m.lastDPress = now
+		return m, nil
+
+	case "X":
+		m.clearSuppressed()
+		return m, nil
+
+	case "r":
+		return m, m.reloadFile()
+
+	case "b":
+		m.jumpToBookmark(1)
+		return m, nil
+
+	case "B":
+		m.jumpToBookmark(-1)
+		return m, nil
+
+	case "T":
+		m.cycleTimeColumn()
+		return m, nil
+
+	case "C":
+		m.showComponent = !m.showComponent
+		return m, nil
+
+	case "O":
+		m.showDuration = !m.showDuration
+		return m, nil
+
+	case "D":
+		m.toggleCollapse()
+		return m, nil
+
+	case "o":
+		m.cycleSort()
+		return m, nil
+
+	case "s":
+		if m.config.NoStats {
+			m.notice = "No summary with --no-stats"
+			return m, nil
 		}
+		m.showSummary = !m.showSummary
+		return m, nil
+
+	case "a":
+		m.openTemplates()
+		return m, nil
+
+	case "Z":
+		m.cycleDisplayZone()
+		return m, nil
+
+	case "R":
+		m.showUnredacted = !m.showUnredacted
+		return m, nil
+
+	case "V":
+		m.toggleMark()
+		return m, nil
+
+	case "E":
+		m.exportRange()
+		return m, nil
+
+	case "W":
+		m.toggleListWrap()
+		return m, nil
+
+	case "Y":
+		m.copyCommandLine()
+		return m, nil
+
+	case "J":
+		m.exportJSONView()
+		return m, nil
+
+	case "M":
+		m.exportMarkdownView()
 		return m, nil
 	}
-	
+
+	return m, nil
+}
+
+func (m *UnifiedModel) updatePreviewPanel(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, m.quit()
+
+	case "tab":
+		m.focus = LeftPanel
+		return m, nil
+
+	case "esc":
+		m.focus = RightPanel
+		return m, nil
+
+	case "j", "down":
+		m.previewScroll++
+		return m, nil
+
+	case "k", "up":
+		if m.previewScroll > 0 {
+			m.previewScroll--
+		}
+		return m, nil
+	}
+
 	return m, nil
 }
 
+// minSplitHeight is the smallest terminal height that still leaves room for
+// both the log list and the preview below it
+const minSplitHeight = 24
+
+// isSplit reports whether the right panel is currently split into list and preview
+func (m *UnifiedModel) isSplit() bool {
+	return m.splitView && m.height >= minSplitHeight
+}
+
+// previewHeight returns the content height of the preview, the lower third of the right panel
+func (m *UnifiedModel) previewHeight() int {
+	return (m.height - 2) / 3
+}
+
+// updateViewportHeight sizes the viewport to the rows available for log entries
+func (m *UnifiedModel) updateViewportHeight() {
+	m.viewportHeight = m.height - 10
+	if m.isSplit() {
+		m.viewportHeight -= m.previewHeight() + 2
+	}
+}
+
+// toggleSplitView turns the preview split on or off, refusing to split when
+// the terminal is too short to show both parts
+func (m *UnifiedModel) toggleSplitView() {
+	if !m.splitView && m.height < minSplitHeight {
+		return
+	}
+
+	selected := m.viewportStart + m.selectedIdx
+	m.splitView = !m.splitView
+	m.previewScroll = 0
+	if !m.splitView && m.focus == PreviewPanel {
+		m.focus = RightPanel
+	}
+	m.updateViewportHeight()
+
+	// Keep the selected entry inside the (possibly smaller) viewport
+	if m.selectedIdx >= m.viewportHeight {
+		m.viewportStart = max(0, selected-m.viewportHeight+1)
+		m.selectedIdx = selected - m.viewportStart
+	}
+	m.loadVisibleLines()
+}
+
 func (m *UnifiedModel) View() string {
 	if m.width == 0 || m.height == 0 {
 		return "Initializing..."
 	}
+	if m.showHelp {
+		return m.renderHelp()
+	}
 
 	// Build header
 	header := m.renderHeader()
@@ -440,6 +1065,12 @@ func (m *UnifiedModel) View() string {
 	var rightPanel string
 	if m.viewMode == DetailView {
 		rightPanel = m.renderDetailPanel()
+	} else if m.viewMode == CopyView {
+		rightPanel = m.renderCopyPanel()
+	} else if m.viewMode == TemplatesView {
+		rightPanel = m.renderTemplatesPanel()
+	} else if m.isSplit() {
+		rightPanel = m.renderSplitPanel()
 	} else {
 		rightPanel = m.renderRightPanel()
 	}
@@ -463,41 +1094,145 @@ func (m *UnifiedModel) renderHeader() string {
 	
 	status := ""
 	if m.indexing {
-		status = fmt.Sprintf("Indexing %s...", m.loadingFile)
+		verb := "Indexing"
+		if m.reindexing {
+			verb = "Re-indexing"
+		}
+		status = fmt.Sprintf("%s %s...", verb, m.sourceLabel(m.loadingFile))
+		if progress := m.indexProgress(); progress != "" {
+			status += " " + progress + " (Esc to cancel)"
+		}
+	} else if m.loadError != "" {
+		status = m.loadError
 	} else if m.totalLines > 0 {
 		status = fmt.Sprintf("Lines: %d/%d", len(m.filteredIndices), m.totalLines)
 		if m.indexTime > 0 {
 			status += fmt.Sprintf(" | Loaded in %v", m.indexTime)
 		}
+		if m.indexer != nil && m.indexer.IndexStride() > 1 {
+			status += fmt.Sprintf(" | Sparse index (1/%d)", m.indexer.IndexStride())
+		}
+		if m.indexer != nil && m.indexer.TailOffset() > 0 {
+			status += " | Tail only"
+		}
+		if merged, ok := m.indexer.(*MergedIndexer); ok {
+			status += fmt.Sprintf(" | %d files merged", merged.Files())
+		}
+		if source := m.sourceInput.Value(); source != "" {
+			status += " | Source: " + source
+		}
+		if m.sortMode != sortNone {
+			status += " | Sorted by " + m.sortMode.String()
+		}
+		if m.collapsed > 0 {
+			status += fmt.Sprintf(" | %d repeats collapsed", m.collapsed)
+		}
+		if len(m.bookmarks) > 0 {
+			status += fmt.Sprintf(" | ★ %d", len(m.bookmarks))
+		}
+		if m.filterStats != nil && m.filterStats.suppressed > 0 {
+			status += fmt.Sprintf(" | %d hidden", m.filterStats.suppressed)
+		}
+		if m.notice != "" {
+			status += " | " + m.notice
+		}
+	} else if len(m.entries) > 0 {
+		status = fmt.Sprintf("Lines: %d/%d", len(m.filteredEntries), len(m.entries))
+	}
+	if len(m.pipes) > 0 {
+		labels := make([]string, len(m.pipes))
+		for i, pipe := range m.pipes {
+			labels[i] = m.sourceLabel(pipe)
+		}
+		if status != "" {
+			status += " | "
+		}
+		status += "Reading pipe " + strings.Join(labels, ", ")
+	}
+	if stats := m.stream.Stats(); stats != "" {
+		if status != "" {
+			status += " | "
+		}
+		status += stats
+	}
+	if rate := m.lineRate.perSecond(time.Now()); rate > 0 {
+		if status != "" {
+			status += " | "
+		}
+		status += "Rate: " + formatRate(rate)
 	}
 	
 	liveIndicator := ""
-	if m.tailing {
+	if m.paused {
+		liveIndicator = fmt.Sprintf(" | PAUSED (%d new)", m.pausedLines)
+	} else if m.tailing {
 		liveIndicator = " | Live ●"
+	} else if m.followMatches {
+		liveIndicator = " | Following matches ●"
 	}
 	
-	padding := m.width - len(title) - len(status) - len(liveIndicator)
+	padding := m.width - ansi.StringWidth(title) - ansi.StringWidth(status) - ansi.StringWidth(liveIndicator)
 	if padding < 0 {
 		padding = 0
 	}
 	
 	headerText := title + strings.Repeat(" ", padding) + status + liveIndicator
-	return m.headerStyle.Width(m.width).Render(headerText)
+	style := m.headerStyle
+	if time.Now().Before(m.alertUntil) {
+		style = style.Background(alertBackground)
+	}
+	return style.Width(m.width).Render(headerText)
+}
+
+// indexProgress describes how far indexing got and the current read throughput
+func (m *UnifiedModel) indexProgress() string {
+	if m.loadingIndexer == nil {
+		return ""
+	}
+	read, total := m.loadingIndexer.Progress()
+	const mb = 1024 * 1024
+
+	progress := fmt.Sprintf("%d MB", read/mb)
+	if total > 0 {
+		progress = fmt.Sprintf("%d%%", read*100/total)
+	}
+	if elapsed := time.Since(m.indexStart).Seconds(); elapsed > 0 {
+		progress += fmt.Sprintf(" %.1f MB/s", float64(read)/mb/elapsed)
+	}
+	return progress
 }
 
 func (m *UnifiedModel) renderLeftPanel() string {
+	style := m.blurredStyle
+	if m.focus == LeftPanel {
+		style = m.focusedStyle
+	}
+	
+	lines, _ := m.leftPanelLines()
+	if visible := m.height - 2; visible > 0 && len(lines) > visible {
+		offset := max(0, min(m.leftScrollOffset, len(lines)-visible))
+		lines = lines[offset : offset+visible]
+	}
+	return style.Width(m.leftWidth).Height(m.height-2).Render(strings.Join(lines, "\n"))
+}
+
+// leftPanelLines builds the left panel content along with the line each
+// item lands on, used for scrolling and clicks
+func (m *UnifiedModel) leftPanelLines() ([]string, map[int]int) {
 	var content strings.Builder
 	
+	itemLines := make(map[int]int, leftPanelItemCount)
+	cursor := func(item int) string {
+		itemLines[item] = strings.Count(content.String(), "\n")
+		return m.leftCursor(item)
+	}
+	
 	content.WriteString("🔍 SEARCH & FILTERS\n\n")
 	
 	// Include filter
-	if m.leftPanelItem == 0 && m.focus == LeftPanel && !m.editMode {
-		content.WriteString("▶ ")
-	} else {
-		content.WriteString("  ")
-	}
+	content.WriteString(cursor(includeItem))
 	content.WriteString("Include Pattern:\n   ")
-	if m.leftPanelItem == 0 && m.editMode {
+	if m.leftPanelItem == includeItem && m.editMode {
 		content.WriteString(m.includeInput.View())
 	} else {
 		value := m.includeInput.Value()
@@ -509,13 +1244,9 @@ func (m *UnifiedModel) renderLeftPanel() string {
 	content.WriteString("\n\n")
 	
 	// Exclude filter
-	if m.leftPanelItem == 1 && m.focus == LeftPanel && !m.editMode {
-		content.WriteString("▶ ")
-	} else {
-		content.WriteString("  ")
-	}
+	content.WriteString(cursor(excludeItem))
 	content.WriteString("Exclude Pattern:\n   ")
-	if m.leftPanelItem == 1 && m.editMode {
+	if m.leftPanelItem == excludeItem && m.editMode {
 		content.WriteString(m.excludeInput.View())
 	} else {
 		value := m.excludeInput.Value()
@@ -525,86 +1256,224 @@ func (m *UnifiedModel) renderLeftPanel() string {
 		content.WriteString(value)
 	}
 	content.WriteString("\n\n")
-	
-	// Options
-	content.WriteString("Options:\n")
-	if m.leftPanelItem == 2 && m.focus == LeftPanel {
-		content.WriteString("▶ ")
+
+	// Source filter
+	content.WriteString(cursor(sourceFilterItem))
+	content.WriteString("Source Filter:\n   ")
+	if m.leftPanelItem == sourceFilterItem && m.editMode {
+		content.WriteString(m.sourceInput.View())
 	} else {
-		content.WriteString("  ")
+		value := m.sourceInput.Value()
+		if value == "" {
+			value = "Any source"
+		}
+		content.WriteString(value)
+	}
+	content.WriteString("\n\n")
+
+	// Time range
+	content.WriteString("Time Range:\n")
+	for _, bound := range []struct {
+		label string
+		item  int
+		input *textinput.Model
+	}{
+		{"Since", sinceItem, &m.sinceInput},
+		{"Until", untilItem, &m.untilInput},
+	} {
+		content.WriteString(cursor(bound.item))
+		content.WriteString(bound.label + ": ")
+		if m.leftPanelItem == bound.item && m.editMode {
+			content.WriteString(bound.input.View())
+		} else if bound.input.Value() == "" {
+			content.WriteString("any")
+		} else {
+			content.WriteString(bound.input.Value())
+			if _, err := parseTimeBound(bound.input.Value(), m.displayZone, time.Now()); err != nil {
+				content.WriteString(" (invalid)")
+			}
+		}
+		content.WriteString("\n")
 	}
+	content.WriteString("\n")
+
+	// Options
+	content.WriteString("Options:\n")
+	content.WriteString(cursor(regexItem))
 	content.WriteString(fmt.Sprintf("[%s] Use Regex\n", checkbox(m.useRegex)))
-	
-	if m.leftPanelItem == 3 && m.focus == LeftPanel {
-		content.WriteString("▶ ")
+
+	content.WriteString(cursor(fuzzyItem))
+	content.WriteString(fmt.Sprintf("[%s] Fuzzy\n", checkbox(m.fuzzy)))
+
+	content.WriteString(cursor(caseItem))
+	content.WriteString(fmt.Sprintf("[%s] Case Sensitive\n", checkbox(m.caseSensitive)))
+
+	content.WriteString(cursor(matchAllItem))
+	content.WriteString(fmt.Sprintf("[%s] Match All\n", checkbox(m.matchAll)))
+
+	content.WriteString(cursor(matchOnlyItem))
+	content.WriteString(fmt.Sprintf("[%s] Match Only\n", checkbox(m.matchOnly)))
+
+	content.WriteString(cursor(rowColorItem))
+	content.WriteString(fmt.Sprintf("[%s] Color Rows by Level\n\n", checkbox(m.rowColorMode)))
+
+	// Log levels, either from a minimum level or toggled one by one
+	content.WriteString("Log Levels:\n")
+	content.WriteString(cursor(minLevelItem))
+	if m.levelThreshold {
+		content.WriteString(fmt.Sprintf("Minimum: %s and above\n", m.levelStyles[m.minLevel].Render(m.minLevel.String())))
 	} else {
-		content.WriteString("  ")
+		content.WriteString("Minimum: off, toggles below\n")
 	}
-	content.WriteString(fmt.Sprintf("[%s] Case Sensitive\n\n", checkbox(m.caseSensitive)))
-	
-	// Log levels
-	content.WriteString("Log Levels:\n")
 	levels := []struct {
-		name    string
+		level   LogLevel
 		enabled bool
 		index   int
 	}{
-		{"ERROR", m.showError, 4},
-		{"WARN", m.showWarn, 5},
-		{"INFO", m.showInfo, 6},
-		{"DEBUG", m.showDebug, 7},
+		{ERROR, m.showError, errorItem},
+		{WARN, m.showWarn, warnItem},
+		{INFO, m.showInfo, infoItem},
+		{DEBUG, m.showDebug, debugItem},
 	}
-	
+
+	counts, counted := m.levelTotals()
 	for _, level := range levels {
-		if m.leftPanelItem == level.index && m.focus == LeftPanel {
-			content.WriteString("▶ ")
-		} else {
-			content.WriteString("  ")
+		content.WriteString(cursor(level.index))
+		content.WriteString(fmt.Sprintf("[%s] %s", checkbox(level.enabled), m.levelStyles[level.level].Render(level.level.String())))
+		if counted {
+			content.WriteString(fmt.Sprintf(" (%d)", counts[level.level]))
 		}
-		content.WriteString(fmt.Sprintf("[%s] %s\n", checkbox(level.enabled), level.name))
+		content.WriteString("\n")
 	}
-	
+
+	// Level chart of the filtered entries, toggled with s
+	if m.showSummary && !m.config.NoStats {
+		content.WriteString("\nSummary:\n")
+		content.WriteString(m.renderLevelSummary(m.leftWidth - 4))
+	}
+
 	// Live streaming toggle
 	content.WriteString("\nStreaming:\n")
-	if m.leftPanelItem == 8 && m.focus == LeftPanel {
-		content.WriteString("▶ ")
-	} else {
-		content.WriteString("  ")
-	}
+	content.WriteString(cursor(liveItem))
 	liveIcon := "🔴"
 	if m.tailing {
 		liveIcon = "🟢"
 	}
 	content.WriteString(fmt.Sprintf("[%s] %s Live Stream\n", checkbox(m.tailing), liveIcon))
 	
-	// Files section at the bottom
-	if m.loadingFile != "" {
-		content.WriteString("\n📁 Files:\n")
-		content.WriteString(fmt.Sprintf("  • %s\n", m.loadingFile))
+	// Files section at the bottom, r renames the file shown
+	content.WriteString("\n📁 Files:\n")
+	content.WriteString(cursor(sourceItem))
+	if m.leftPanelItem == sourceItem && m.editMode {
+		content.WriteString(m.labelInput.View())
+	} else if m.loadingFile != "" {
+		content.WriteString(m.sourceLabel(m.loadingFile))
+	} else {
+		content.WriteString("none")
+	}
+	content.WriteString("\n")
+	
+	return strings.Split(strings.TrimRight(content.String(), "\n"), "\n"), itemLines
+}
+
+// scrollLeftPanel scrolls the left panel so the selected item stays in view
+// on a short terminal. The first and last items scroll to the ends so the
+// headings and sections around the items stay reachable
+func (m *UnifiedModel) scrollLeftPanel() {
+	lines, itemLines := m.leftPanelLines()
+	m.leftItemLines = itemLines
+	visible := m.height - 2 // panel content height
+	if visible <= 0 || len(lines) <= visible {
+		m.leftScrollOffset = 0
+		return
 	}
 	
+	itemLine := itemLines[m.leftPanelItem]
+	maxOffset := len(lines) - visible
+	// Pattern items take two lines, label and value
+	itemEnd := min(itemLine+1, len(lines)-1)
+	if m.leftPanelItem == 0 {
+		m.leftScrollOffset = 0
+	} else if m.leftPanelItem == leftPanelItemCount-1 {
+		m.leftScrollOffset = maxOffset
+	} else if itemLine < m.leftScrollOffset {
+		m.leftScrollOffset = itemLine
+	} else if itemEnd >= m.leftScrollOffset+visible {
+		m.leftScrollOffset = itemEnd - visible + 1
+	}
+	m.leftScrollOffset = max(0, min(m.leftScrollOffset, maxOffset))
+}
+
+// leftCursor returns the selection marker prefix for a left panel item
+func (m *UnifiedModel) leftCursor(item int) string {
+	if m.leftPanelItem == item && m.focus == LeftPanel && !m.editMode {
+		return "▶ "
+	}
+	return "  "
+}
+
+func (m *UnifiedModel) renderRightPanel() string {
 	style := m.blurredStyle
-	if m.focus == LeftPanel {
+	if m.focus == RightPanel {
 		style = m.focusedStyle
 	}
 	
-	return style.Width(m.leftWidth).Height(m.height-2).Render(content.String())
+	return style.Width(m.rightWidth).Height(m.height-2).Render(m.renderLogStream())
 }
 
-func (m *UnifiedModel) renderRightPanel() string {
+// renderSplitPanel stacks the log stream above a preview of the selected entry
+func (m *UnifiedModel) renderSplitPanel() string {
+	previewHeight := m.previewHeight()
+	listHeight := m.height - 4 - previewHeight
+
+	listStyle := m.blurredStyle
+	if m.focus == RightPanel {
+		listStyle = m.focusedStyle
+	}
+	list := listStyle.Width(m.rightWidth).Height(listHeight).MaxHeight(listHeight + 2).Render(m.renderLogStream())
+
+	var content strings.Builder
+	content.WriteString("📄 PREVIEW\n")
+	m.mutex.RLock()
+	if m.selectedIdx < 0 || m.selectedIdx >= len(m.visibleEntries) {
+		content.WriteString("No entry selected\n")
+	} else {
+		content.WriteString(m.renderEntryDetail(m.visibleEntries[m.selectedIdx], m.previewScroll, previewHeight))
+	}
+	m.mutex.RUnlock()
+
+	previewStyle := m.blurredStyle
+	if m.focus == PreviewPanel {
+		previewStyle = m.focusedStyle
+	}
+	preview := previewStyle.Width(m.rightWidth).Height(previewHeight).MaxHeight(previewHeight + 2).Render(content.String())
+
+	return lipgloss.JoinVertical(lipgloss.Left, list, preview)
+}
+
+// renderLogStream renders the log list content shared by the full and split layouts
+func (m *UnifiedModel) renderLogStream() string {
 	var content strings.Builder
 	
 	content.WriteString("📜 LOG STREAM\n")
 	
 	// Position indicator
 	position := ""
-	if len(m.matchedIndices) > 0 {
+	if m.searchQuery != "" {
+		position = fmt.Sprintf("(%d/%d found)", min(m.searchIdx+1, len(m.searchMatches)), len(m.searchMatches))
+	} else if len(m.matchedIndices) > 0 {
 		position = fmt.Sprintf("(%d/%d matches)", m.currentMatchIdx+1, len(m.matchedIndices))
 	} else if m.totalLines > 0 {
 		position = fmt.Sprintf("(%d/%d)", m.viewportStart+m.selectedIdx+1, len(m.filteredIndices))
 	}
 	
-	if position != "" {
+	if m.searching {
+		content.WriteString("  Search: " + m.searchInput.View() + " " + position + "\n")
+	} else if m.gotoOpen && m.gotoTime {
+		content.WriteString("  Go to time: " + m.gotoInput.View() + " " + position + "\n")
+	} else if m.gotoOpen {
+		content.WriteString("  Go to line: " + m.gotoInput.View() + " " + position + "\n")
+	} else if position != "" {
 		padding := m.rightWidth - 15 - len(position)
 		if padding > 0 {
 			content.WriteString(strings.Repeat(" ", padding))
@@ -615,25 +1484,69 @@ func (m *UnifiedModel) renderRightPanel() string {
 	}
 	
 	// Column headers
-	content.WriteString("TIME                       LEVEL    MESSAGE\n")
+	if m.showTime {
+		content.WriteString(fmt.Sprintf("%-*s ", m.timeColumnWidth(), "TIME"))
+	}
+	content.WriteString("LEVEL    ")
+	if m.showSource() {
+		content.WriteString(fmt.Sprintf("%-*s ", sourceWidth, "SOURCE"))
+	}
+	if m.showComponent {
+		content.WriteString(fmt.Sprintf("%-*s ", componentWidth, "COMPONENT"))
+	}
+	if m.showDuration {
+		content.WriteString(fmt.Sprintf("%*s ", durationWidth, "DURATION"))
+	}
+	content.WriteString("MESSAGE\n")
 	content.WriteString("───────────────────────────────────────────\n")
 	
-	// Render visible entries
+	// Render visible entries, as many as fit when they're wrapped
 	m.mutex.RLock()
+	rowsLeft := m.viewportHeight
 	for i, entry := range m.visibleEntries {
+		if rowsLeft <= 0 {
+			break
+		}
 		isSelected := i == m.selectedIdx
 		isMatch := m.isEntryMatch(m.viewportStart + i)
-		line := m.formatColumnLogEntry(entry, isSelected, isMatch)
-		content.WriteString(line + "\n")
+		if run := m.runAt(m.viewportStart + i); run.count > 1 {
+			entry.Message = fmt.Sprintf("(×%d) %s", run.count, entry.Message)
+		}
+		rows := strings.Split(m.formatColumnLogEntry(entry, isSelected, isMatch), "\n")
+		if len(rows) > rowsLeft {
+			rows = rows[:rowsLeft]
+		}
+		rowsLeft -= len(rows)
+		if !isSelected && m.viewportStart+i < len(m.filteredIndices) {
+			line := m.filteredIndices[m.viewportStart+i]
+			if m.inMarkedRange(line) {
+				for j := range rows {
+					rows[j] = "┃ " + strings.TrimPrefix(rows[j], "  ")
+				}
+			}
+			// The star takes the gutter of the first row, even inside a range
+			if m.bookmarks[line] {
+				rows[0] = "★ " + strings.TrimPrefix(strings.TrimPrefix(rows[0], "┃ "), "  ")
+			}
+		}
+		content.WriteString(strings.Join(rows, "\n") + "\n")
 	}
 	m.mutex.RUnlock()
 	
-	style := m.blurredStyle
-	if m.focus == RightPanel {
-		style = m.focusedStyle
+	// Explain an empty list instead of leaving it blank
+	if m.indexer != nil && m.totalLines == 0 {
+		content.WriteString(fmt.Sprintf("\n%s is empty", m.sourceLabel(m.loadingFile)))
+		if m.following {
+			content.WriteString(", waiting for new lines")
+		}
+		content.WriteString("\n")
+	} else if len(m.visibleEntries) == 0 && m.filterStats != nil {
+		if diagnostic := m.filterStats.diagnostic(m.includeInput.Value(), m.excludeInput.Value(), m.sourceInput.Value()); diagnostic != "" {
+			content.WriteString("\n" + diagnostic + "\n")
+		}
 	}
 	
-	return style.Width(m.rightWidth).Height(m.height-2).Render(content.String())
+	return content.String()
 }
 
 func (m *UnifiedModel) renderDetailPanel() string {
@@ -645,38 +1558,16 @@ func (m *UnifiedModel) renderDetailPanel() string {
 	
 	if m.selectedIdx < 0 || m.selectedIdx >= len(m.visibleEntries) {
 		content.WriteString("\nNo entry selected\n")
-	} else {
-		entry := m.visibleEntries[m.selectedIdx]
-		
-		// Entry details
-		content.WriteString(fmt.Sprintf("\nTimestamp: %s\n", entry.Timestamp))
-		content.WriteString(fmt.Sprintf("Level:     %s\n", entry.Level))
-		if entry.Source != "" {
-			content.WriteString(fmt.Sprintf("Source:    %s\n", entry.Source))
-		}
-		content.WriteString("\nMessage:\n")
-		content.WriteString("────────\n")
-		
-		// Wrap message
-		lines := strings.Split(entry.Message, "\n")
-		visibleLines := len(lines) - m.scrollOffset
-		maxLines := m.height - 15
-		if visibleLines > maxLines {
-			visibleLines = maxLines
-		}
-		
-		for i := m.scrollOffset; i < m.scrollOffset+visibleLines && i < len(lines); i++ {
-			content.WriteString(lines[i] + "\n")
+	} else {
+		content.WriteString("\n")
+		if reference, ok := m.lineReference(); ok {
+			content.WriteString(fmt.Sprintf("Line:      %s (yl to copy)\n", reference))
 		}
-		
-		// Metadata if present
-		if len(entry.Metadata) > 0 {
-			content.WriteString("\nMetadata:\n")
-			content.WriteString("─────────\n")
-			for k, v := range entry.Metadata {
-				content.WriteString(fmt.Sprintf("%s: %v\n", k, v))
-			}
+		if run := m.runAt(m.viewportStart + m.selectedIdx); run.count > 1 {
+			content.WriteString(fmt.Sprintf("Repeated:  ×%d in a row, the last on line %d\n", run.count, run.last+1))
 		}
+		// A message cut in the list is shown whole here
+		content.WriteString(m.renderEntryDetail(m.fullEntry(m.visibleEntries[m.selectedIdx]), m.scrollOffset, m.height-15))
 	}
 	
 	style := m.blurredStyle
@@ -687,52 +1578,298 @@ func (m *UnifiedModel) renderDetailPanel() string {
 	return style.Width(m.rightWidth).Height(m.height-2).Render(content.String())
 }
 
+// renderEntryDetail renders the fields, message and metadata of an entry,
+// showing at most maxLines message lines starting at line scroll
+func (m *UnifiedModel) renderEntryDetail(entry LogEntry, scroll, maxLines int) string {
+	var content strings.Builder
+
+	content.WriteString(fmt.Sprintf("Timestamp: %s\n", m.displayTimestamp(entry)))
+	content.WriteString(fmt.Sprintf("Level:     %s\n", m.levelStyles[entry.Level].Render(entry.Level.String())))
+	if entry.Source != "" {
+		source := m.sourceLabel(entry.Source)
+		if source != entry.Source {
+			source += " (" + entry.Source + ")"
+		}
+		content.WriteString(fmt.Sprintf("Source:    %s\n", source))
+	}
+	if entry.Component != "" {
+		content.WriteString(fmt.Sprintf("Component: %s\n", entry.Component))
+	}
+	if entry.Length > 0 {
+		content.WriteString(fmt.Sprintf("Offset:    %d (%d bytes)\n", entry.Offset, entry.Length))
+	}
+
+	content.WriteString("\nMessage:\n")
+	content.WriteString("────────\n")
+	
+	// Wrap message
+	lines := strings.Split(m.redact(entry.Message), "\n")
+	if colored, ok := m.coloredMessage(entry); ok {
+		lines = []string{colored + ansiReset}
+	}
+	if m.wrapMarkers {
+		lines = wrapWithMarkers(lines, m.rightWidth)
+	}
+	visibleLines := len(lines) - scroll
+	if visibleLines > maxLines {
+		visibleLines = maxLines
+	}
+	
+	for i := scroll; i < scroll+visibleLines && i < len(lines); i++ {
+		content.WriteString(lines[i] + "\n")
+	}
+	
+	// Known error codes
+	if codes := m.config.ErrorCatalog.Lookup(entry); len(codes) > 0 {
+		content.WriteString("\nError Codes:\n")
+		content.WriteString("────────────\n")
+		for _, code := range codes {
+			if code.Description == "" {
+				content.WriteString(code.Code + "\n")
+			} else {
+				content.WriteString(fmt.Sprintf("%s: %s\n", code.Code, code.Description))
+			}
+		}
+	}
+
+	// Metadata if present
+	if len(entry.Metadata) > 0 {
+		content.WriteString("\nMetadata:\n")
+		content.WriteString("─────────\n")
+		keys := make([]string, 0, len(entry.Metadata))
+		for k := range entry.Metadata {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var lines []string
+		for _, k := range keys {
+			value := m.redact(metadataValue(entry.Metadata[k]))
+			lines = append(lines, strings.Split(metadataKeyStyle.Render(k)+": "+value, "\n")...)
+		}
+		if m.wrapMarkers {
+			lines = wrapWithMarkers(lines, m.rightWidth)
+		}
+		for _, line := range lines {
+			content.WriteString(line + "\n")
+		}
+	}
+
+	return content.String()
+}
+
+// metadataValue formats a metadata value for the detail view: nested maps
+// and lists, like OTLP attributes, as indented JSON
+func metadataValue(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		if data, err := json.MarshalIndent(value, "", "  "); err == nil {
+			return string(data)
+		}
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// wrapMarker starts a row that continues the message line above it
+const wrapMarker = "↳ "
+
+// wrapWithMarkers wraps lines to width itself, instead of leaving it to the
+// panel, so rows that continue a line can be told apart from new lines
+func wrapWithMarkers(lines []string, width int) []string {
+	limit := width - ansi.StringWidth(wrapMarker)
+	if limit < 1 {
+		return lines
+	}
+	rows := make([]string, 0, len(lines))
+	for _, line := range lines {
+		for i, row := range strings.Split(ansi.Wrap(line, limit, ""), "\n") {
+			if i > 0 {
+				row = wrapMarker + row
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
 // Keep old function for compatibility but unused
 func (m *UnifiedModel) renderDetailView() string {
 	return ""
 }
 
+// componentWidth is the width of the COMPONENT column
+const componentWidth = 16
+
+// sourceWidth is the width of the SOURCE column of a merged timeline
+const sourceWidth = 16
+
 func (m *UnifiedModel) formatColumnLogEntry(entry LogEntry, selected, isMatch bool) string {
-	// Time column (26 chars)
-	timeStr := entry.Timestamp
-	if len(timeStr) > 26 {
-		timeStr = timeStr[:26]
-	} else if len(timeStr) < 26 {
-		timeStr = timeStr + strings.Repeat(" ", 26-len(timeStr))
+	// Time column (26 chars plus separator, wider with --time-precision),
+	// hidden with --no-time or T
+	timeStr := ""
+	if m.showTime {
+		timeWidth := m.timeColumnWidth()
+		timeStr = m.columnTimestamp(entry)
+		if len(timeStr) > timeWidth {
+			timeStr = timeStr[:timeWidth]
+		} else if len(timeStr) < timeWidth {
+			timeStr = timeStr + strings.Repeat(" ", timeWidth-len(timeStr))
+		}
+		timeStr += " "
 	}
 	
-	// Level column (8 chars)
+	// Level column (8 chars). A tinted row is styled as a whole instead,
+	// but the selection highlight always wins
+	tintRow := m.rowColorMode && !selected
 	levelStr := fmt.Sprintf("[%s]", entry.Level.String())
-	levelStyled := m.levelStyles[entry.Level].Render(levelStr)
+	levelStyled := levelStr
+	if !tintRow {
+		levelStyled = m.levelStyles[entry.Level].Render(levelStr)
+	}
 	levelPadding := 8 - len(levelStr)
 	if levelPadding > 0 {
 		levelStyled += strings.Repeat(" ", levelPadding)
 	}
 	
+	// Source column (16 chars plus separator), colored per file when merged
+	sourceStr, sourceStyled := "", ""
+	if m.showSource() {
+		sourceStr = fitColumn(m.sourceLabel(entry.Source), sourceWidth) + " "
+		sourceStyled = sourceStr
+		if !tintRow {
+			sourceStyled = lipgloss.NewStyle().Foreground(sourceColor(entry.Source)).Render(sourceStr)
+		}
+	}
+	
+	// Component column (16 chars plus separator), shown with --component or C
+	componentStr := ""
+	if m.showComponent {
+		componentStr = fitColumn(entry.Component, componentWidth) + " "
+	}
+	
+	// Duration column (9 chars plus separator, right-aligned), shown with D
+	durationStr := ""
+	if m.showDuration {
+		text := ""
+		if ms, ok := entryDuration(entry); ok {
+			text = formatDuration(ms)
+		}
+		durationStr = fmt.Sprintf("%*s ", durationWidth, text)
+	}
+	
 	// Message column (remaining width)
-	maxMsgLen := m.rightWidth - 40
+	prefixWidth := ansi.StringWidth(timeStr) + ansi.StringWidth(sourceStr) + ansi.StringWidth(componentStr) + len(durationStr)
+	maxMsgLen := m.rightWidth - 13 - prefixWidth
 	if maxMsgLen < 20 {
 		maxMsgLen = 20
 	}
 	
-	message := strings.ReplaceAll(entry.Message, "\n", " ")
+	message := strings.ReplaceAll(m.displayMessage(entry), "\n", " ")
 	message = strings.ReplaceAll(message, "\t", " ")
 	
-	if isMatch {
-		message = m.highlightMatches(message)
+	// A search match takes the highlight over include matches
+	highlighted := false
+	if search, ok := m.highlightPattern(message, m.searchQuery); ok {
+		message, highlighted = search, true
+	} else if isMatch {
+		include := m.highlightMatches(message, entry.Source)
+		message, highlighted = include, include != message
+	}
+	
+	// The source's own colors, unless the highlight or the row's style
+	// needs the plain text
+	colored := false
+	if !highlighted && !selected && !tintRow {
+		if text, ok := m.coloredMessage(entry); ok {
+			message, colored = strings.ReplaceAll(text, "\t", " "), true
+		}
 	}
 	
-	if len(message) > maxMsgLen {
-		message = message[:maxMsgLen-3] + "..."
+	// Wrapped with W, the rest of the message continues under its column
+	var continuation []string
+	if m.wrapList {
+		rows := strings.Split(ansi.Wrap(message, maxMsgLen, ""), "\n")
+		if m.wrapMarkers {
+			rows = wrapWithMarkers([]string{message}, maxMsgLen)
+		}
+		message, continuation = rows[0], rows[1:]
+	} else {
+		message = ansi.Truncate(message, maxMsgLen, "...")
+	}
+	if message == noMessage && !selected && !tintRow {
+		message = noMessageStyle.Render(message)
+	}
+	if colored {
+		message += ansiReset
+		for i := range continuation {
+			continuation[i] += ansiReset
+		}
 	}
 	
 	// Build line
-	line := fmt.Sprintf("%s %s %s", timeStr, levelStyled, message)
+	line := fmt.Sprintf("%s%s %s%s%s%s", timeStr, levelStyled, sourceStyled, componentStr, durationStr, message)
+	rows := []string{line}
+	indent := strings.Repeat(" ", prefixWidth+9)
+	for _, chunk := range continuation {
+		rows = append(rows, indent+chunk)
+	}
+	
+	for i, row := range rows {
+		switch {
+		case selected:
+			marker := "▶ "
+			if i > 0 {
+				marker = "  "
+			}
+			// Level is styled without the time column, so there's nothing safe to trim
+			if m.showTime {
+				row = row[2:]
+			}
+			rows[i] = marker + m.selectionStyle().Render(row)
+		case tintRow:
+			rows[i] = "  " + tint(m.levelStyles[entry.Level], row)
+		default:
+			rows[i] = "  " + row
+		}
+	}
+	return strings.Join(rows, "\n")
+}
+
+// toggleListWrap switches the list between one row per entry, cut to the
+// panel width, and whole messages wrapped over several rows
+func (m *UnifiedModel) toggleListWrap() {
+	m.wrapList = !m.wrapList
+	m.fitWrappedSelection()
+}
+
+// entryRows returns how many rows an entry takes in the list
+func (m *UnifiedModel) entryRows(entry LogEntry) int {
+	if !m.wrapList {
+		return 1
+	}
+	return strings.Count(m.formatColumnLogEntry(entry, false, false), "\n") + 1
+}
+
+// fitWrappedSelection scrolls the viewport forward when wrapped entries push
+// the selected one past the rows shown
+func (m *UnifiedModel) fitWrappedSelection() {
+	if !m.wrapList || m.selectedIdx >= len(m.visibleEntries) {
+		return
+	}
 	
-	if selected {
-		return "▶ " + m.selectedStyle.Render(line[2:])
+	rows := 0
+	for i := 0; i <= m.selectedIdx; i++ {
+		rows += m.entryRows(m.visibleEntries[i])
+	}
+	shift := 0
+	for rows > m.viewportHeight && shift < m.selectedIdx {
+		rows -= m.entryRows(m.visibleEntries[shift])
+		shift++
+	}
+	if shift > 0 {
+		m.viewportStart += shift
+		m.selectedIdx -= shift
+		m.loadVisibleLines()
 	}
-	return "  " + line
 }
 
 // Load visible lines from indexer
@@ -745,7 +1882,7 @@ func (m *UnifiedModel) loadVisibleLines() {
 	end := start + m.viewportHeight
 	
 	// Apply filters to get filtered indices
-	if len(m.filteredIndices) == 0 {
+	if m.filterStats == nil {
 		m.applyFilters()
 	}
 	
@@ -771,10 +1908,15 @@ func (m *UnifiedModel) loadVisibleLines() {
 	m.mutex.Lock()
 	m.visibleEntries = entries
 	m.mutex.Unlock()
+	
+	m.fitWrappedSelection()
 }
 
 // Apply filters and update filtered indices
 func (m *UnifiedModel) applyFilters() {
+	if len(m.entries) > 0 {
+		m.refilterEntries()
+	}
 	if m.indexer == nil {
 		return
 	}
@@ -782,57 +1924,25 @@ func (m *UnifiedModel) applyFilters() {
 	m.filteredIndices = []int{}
 	m.matchedIndices = []int{}
 	
-	includePatterns := strings.Split(m.includeInput.Value(), ",")
-	excludePatterns := strings.Split(m.excludeInput.Value(), ",")
+	// Filter through all lines (this is still fast with indexing)
+	stats := &filterStats{total: m.totalLines, counting: !m.config.NoStats}
+	m.filterLines(0, m.entryFilter(), stats)
 	
-	// Clean patterns
-	for i := range includePatterns {
-		includePatterns[i] = strings.TrimSpace(includePatterns[i])
+	m.filterStats = stats
+	if m.sortMode != sortNone {
+		m.sortFiltered()
 	}
-	for i := range excludePatterns {
-		excludePatterns[i] = strings.TrimSpace(excludePatterns[i])
+	m.collapseFiltered()
+	m.findSearchMatches()
+	if m.currentMatchIdx >= len(m.matchedIndices) {
+		m.currentMatchIdx = 0
 	}
 	
-	// Filter through all lines (this is still fast with indexing)
-	for i := 0; i < m.totalLines; i++ {
-		// Load entry to check level and patterns
-		if entries, err := m.indexer.GetLineRange(i, i+1); err == nil && len(entries) > 0 {
-			entry := entries[0]
-			
-			// Check log level filter
-			if !m.shouldShowLevel(entry.Level) {
-				continue
-			}
-			
-			// Check exclude patterns
-			excluded := false
-			for _, pattern := range excludePatterns {
-				if pattern != "" && m.matchesPattern(entry.Message, pattern) {
-					excluded = true
-					break
-				}
-			}
-			if excluded {
-				continue
-			}
-			
-			// Check include patterns
-			if m.includeInput.Value() != "" {
-				matched := false
-				for _, pattern := range includePatterns {
-					if pattern != "" && m.matchesPattern(entry.Message, pattern) {
-						matched = true
-						m.matchedIndices = append(m.matchedIndices, len(m.filteredIndices))
-						break
-					}
-				}
-				if !matched {
-					continue
-				}
-			}
-			
-			m.filteredIndices = append(m.filteredIndices, i)
-		}
+	// While tailing, stay on the newest line that passes the new filters,
+	// e.g. when the include pattern is refined as it's typed
+	if m.tailing && len(m.filteredIndices) > 0 {
+		m.scrollToBottom()
+		return
 	}
 	
 	// Reset viewport if needed
@@ -845,6 +1955,102 @@ func (m *UnifiedModel) applyFilters() {
 	m.loadVisibleLines()
 }
 
+// filterLines filters the lines from first on, adding the ones shown to
+// filteredIndices and counting every one in stats
+func (m *UnifiedModel) filterLines(first int, filter entryFilter, stats *filterStats) {
+	needsLine := m.needsLine(filter)
+	for i := first; i < m.totalLines; i++ {
+		entry, ok := m.filterEntry(i, needsLine)
+		if !ok {
+			continue
+		}
+		stage, matched := m.entryStage(entry, filter)
+		stats.count(stage, entry.Level, 1)
+		if stage != stageShown {
+			continue
+		}
+		if matched {
+			m.matchedIndices = append(m.matchedIndices, len(m.filteredIndices))
+		}
+		m.filteredIndices = append(m.filteredIndices, i)
+	}
+}
+
+// needsLine reports whether filtering needs the lines themselves. Only
+// patterns and the time window do, levels are known from indexing
+func (m *UnifiedModel) needsLine(filter entryFilter) bool {
+	return len(filter.includes) > 0 || len(filter.excludes) > 0 || !filter.window.isOpen() || len(m.suppressed) > 0 || len(filter.sources) > 0
+}
+
+// filterEntry returns line i as far as filtering needs it. The level found
+// while indexing spares reading the line when it decides alone
+func (m *UnifiedModel) filterEntry(i int, needsLine bool) (LogEntry, bool) {
+	if level, known := m.indexer.LineLevel(i); known && (!needsLine || !m.shouldShowLevel(level)) {
+		return LogEntry{Level: level}, true
+	}
+	if entries, err := m.indexer.GetLineRange(i, i+1); err == nil && len(entries) > 0 {
+		return entries[0], true
+	}
+	return LogEntry{}, false
+}
+
+// retractLastLine takes the last line out of the last filter pass before
+// the file grows and returns the first line to filter afterwards. A last
+// line without a newline was only partly written, so it's filtered again
+// together with the appended ones
+func (m *UnifiedModel) retractLastLine() int {
+	last := m.totalLines - 1
+	if last < 0 || m.filterStats == nil || m.sortMode != sortNone || m.collapse {
+		return m.totalLines // Filtered again as a whole anyway
+	}
+	filter := m.entryFilter()
+	entry, ok := m.filterEntry(last, m.needsLine(filter))
+	if !ok {
+		return m.totalLines
+	}
+	
+	stage, _ := m.entryStage(entry, filter)
+	m.filterStats.count(stage, entry.Level, -1)
+	if n := len(m.filteredIndices); n > 0 && m.filteredIndices[n-1] == last {
+		m.filteredIndices = m.filteredIndices[:n-1]
+		m.matchedIndices = dropPositions(m.matchedIndices, n-1, n)
+		m.searchMatches = dropPositions(m.searchMatches, n-1, n)
+	}
+	return last
+}
+
+// showNewLines shows the lines from first on, added since the last filter
+// pass, after the ones already shown. Only the new lines are filtered, a
+// sorted or collapsed list is filtered again as a whole
+func (m *UnifiedModel) showNewLines(first int) {
+	if m.filterStats == nil || m.sortMode != sortNone || m.collapse {
+		m.applyFilters()
+		return
+	}
+	
+	shown := len(m.filteredIndices)
+	m.filterStats.total = m.totalLines
+	m.filterLines(first, m.entryFilter(), m.filterStats)
+	m.addSearchMatches(shown)
+	if m.currentMatchIdx >= len(m.matchedIndices) {
+		m.currentMatchIdx = 0
+	}
+	m.loadVisibleLines()
+}
+
+// timeWindow resolves the since/until inputs. Invalid bounds are left open
+func (m *UnifiedModel) timeWindow() timeRange {
+	now := time.Now()
+	var window timeRange
+	if t, err := parseTimeBound(m.sinceInput.Value(), m.displayZone, now); err == nil {
+		window.since = t
+	}
+	if t, err := parseTimeBound(m.untilInput.Value(), m.displayZone, now); err == nil {
+		window.until = t
+	}
+	return window
+}
+
 func (m *UnifiedModel) shouldShowIndex(idx int) bool {
 	// For now, always return true since we'd need to load the entry to check level
 	// This could be optimized by storing level in the index
@@ -852,6 +2058,9 @@ func (m *UnifiedModel) shouldShowIndex(idx int) bool {
 }
 
 func (m *UnifiedModel) shouldShowLevel(level LogLevel) bool {
+	if m.levelThreshold && level >= DEBUG && level <= ERROR {
+		return level >= m.minLevel
+	}
 	switch level {
 	case ERROR:
 		return m.showError
@@ -867,6 +2076,9 @@ func (m *UnifiedModel) shouldShowLevel(level LogLevel) bool {
 }
 
 func (m *UnifiedModel) matchesPattern(text, pattern string) bool {
+	if m.fuzzy {
+		return fuzzyMatch(text, pattern, m.caseSensitive) != nil
+	}
 	if m.useRegex {
 		if m.caseSensitive {
 			if re, err := regexp.Compile(pattern); err == nil {
@@ -887,59 +2099,77 @@ func (m *UnifiedModel) matchesPattern(text, pattern string) bool {
 	return false
 }
 
-func (m *UnifiedModel) highlightMatches(message string) string {
+func (m *UnifiedModel) highlightMatches(message, source string) string {
 	if m.includeInput.Value() == "" {
 		return message
 	}
 	
+	for _, p := range m.parseFilterPatterns(m.includeInput.Value()) {
+		if !p.appliesTo(source) {
+			continue
+		}
+		if _, _, ok := metadataPredicate(p.pattern); ok {
+			continue // Nothing in the message to highlight
+		}
+		if _, ok := componentPattern(p.pattern); ok {
+			continue
+		}
+		if highlighted, ok := m.highlightPattern(message, p.pattern); ok {
+			return highlighted
+		}
+	}
+	
+	return message
+}
+
+// highlightPattern highlights the first match of pattern in message
+func (m *UnifiedModel) highlightPattern(message, pattern string) (string, bool) {
+	if pattern == "" {
+		return message, false
+	}
+	
 	// Simple highlighting with color
 	highlightStyle := lipgloss.NewStyle().
-		Background(lipgloss.Color("226")).
-		Foreground(lipgloss.Color("0")).
+		Background(lipgloss.AdaptiveColor{Light: "220", Dark: "226"}).
+		Foreground(lipgloss.AdaptiveColor{Light: "0", Dark: "0"}).
 		Bold(true)
 	
-	patterns := strings.Split(m.includeInput.Value(), ",")
-	for _, pattern := range patterns {
-		pattern = strings.TrimSpace(pattern)
-		if pattern == "" {
-			continue
+	if m.fuzzy {
+		offsets := fuzzyMatch(message, pattern, m.caseSensitive)
+		if offsets == nil {
+			return message, false
 		}
-		
-		if m.useRegex {
-			// For regex, just highlight the first match
-			var re *regexp.Regexp
-			if m.caseSensitive {
-				re, _ = regexp.Compile(pattern)
-			} else {
-				re, _ = regexp.Compile("(?i)" + pattern)
-			}
-			if re != nil {
-				loc := re.FindStringIndex(message)
-				if loc != nil {
-					before := message[:loc[0]]
-					match := message[loc[0]:loc[1]]
-					after := message[loc[1]:]
-					return before + highlightStyle.Render(match) + after
-				}
-			}
+		return highlightFuzzy(message, offsets, highlightStyle), true
+	}
+
+	start, end := -1, -1
+	if m.useRegex {
+		// For regex, just highlight the first match
+		var re *regexp.Regexp
+		if m.caseSensitive {
+			re, _ = regexp.Compile(pattern)
 		} else {
-			// Simple string highlighting
-			var idx int
-			if m.caseSensitive {
-				idx = strings.Index(message, pattern)
-			} else {
-				idx = strings.Index(strings.ToLower(message), strings.ToLower(pattern))
-			}
-			if idx >= 0 {
-				before := message[:idx]
-				match := message[idx : idx+len(pattern)]
-				after := message[idx+len(pattern):]
-				return before + highlightStyle.Render(match) + after
+			re, _ = regexp.Compile("(?i)" + pattern)
+		}
+		if re != nil {
+			if loc := re.FindStringIndex(message); loc != nil {
+				start, end = loc[0], loc[1]
 			}
 		}
+	} else {
+		// Simple string highlighting
+		if m.caseSensitive {
+			start = strings.Index(message, pattern)
+		} else {
+			start = strings.Index(strings.ToLower(message), strings.ToLower(pattern))
+		}
+		end = start + len(pattern)
+	}
+	if start < 0 {
+		return message, false
 	}
 	
-	return message
+	return message[:start] + highlightStyle.Render(message[start:end]) + message[end:], true
 }
 
 func (m *UnifiedModel) isEntryMatch(idx int) bool {
@@ -955,13 +2185,14 @@ func (m *UnifiedModel) isEntryMatch(idx int) bool {
 func (m *UnifiedModel) scrollDown() {
 	m.selectedIdx++
 	if m.selectedIdx >= m.viewportHeight || m.selectedIdx >= len(m.visibleEntries) {
-		m.selectedIdx = min(m.viewportHeight-1, len(m.visibleEntries)-1)
+		m.selectedIdx = max(0, min(m.viewportHeight-1, len(m.visibleEntries)-1))
 		m.viewportStart++
 		if m.viewportStart+m.viewportHeight > len(m.filteredIndices) {
 			m.viewportStart = max(0, len(m.filteredIndices)-m.viewportHeight)
 		}
 		m.loadVisibleLines()
 	}
+	m.fitWrappedSelection()
 }
 
 func (m *UnifiedModel) scrollUp() {
@@ -1075,10 +2306,7 @@ func (m *UnifiedModel) nextMatch() {
 	}
 	
 	// Jump to match
-	matchPos := m.matchedIndices[m.currentMatchIdx]
-	m.viewportStart = max(0, matchPos-m.viewportHeight/2)
-	m.selectedIdx = matchPos - m.viewportStart
-	m.loadVisibleLines()
+	m.jumpToPosition(m.matchedIndices[m.currentMatchIdx])
 }
 
 func (m *UnifiedModel) prevMatch() {
@@ -1092,74 +2320,238 @@ func (m *UnifiedModel) prevMatch() {
 	}
 	
 	// Jump to match
-	matchPos := m.matchedIndices[m.currentMatchIdx]
-	m.viewportStart = max(0, matchPos-m.viewportHeight/2)
-	m.selectedIdx = matchPos - m.viewportStart
+	m.jumpToPosition(m.matchedIndices[m.currentMatchIdx])
+}
+
+// jumpToPosition centers the viewport on a filtered position and selects it
+func (m *UnifiedModel) jumpToPosition(pos int) {
+	m.tailing = false
+	m.viewportStart = max(0, pos-m.viewportHeight/2)
+	m.selectedIdx = pos - m.viewportStart
 	m.loadVisibleLines()
 }
 
 // Set indexer after file is loaded
-func (m *UnifiedModel) SetIndexer(indexer *FastIndexer, filename string) {
+func (m *UnifiedModel) SetIndexer(indexer LineIndexer, filename string) {
 	m.indexer = indexer
 	m.loadingFile = filename
+	m.loadingIndexer = nil
+	m.loadError = ""
 	m.totalLines = indexer.GetLineCount()
 	m.indexing = false
+	initial := !m.reindexing
+	m.reindexing = false
 	
 	// Initial filter apply
 	m.applyFilters()
+	if initial {
+		m.startAtTail()
+	}
 }
 
-// AddLogEntry adds a log entry to the model (for testing)
-func (m *UnifiedModel) AddLogEntry(entry LogEntry) {
-	m.mutex.Lock()
-	defer m.mutex.Unlock()
-	
-	// If streaming mode, add to entries
-	if m.entries == nil {
-		m.entries = []LogEntry{}
+// startIndexing shows the progress of an indexer on filename until its
+// indexedMsg or loadFailedMsg arrives. Without one, merged files are being
+// ordered
+func (m *UnifiedModel) startIndexing(indexer *FastIndexer, filename string) {
+	m.indexing = true
+	m.loadingIndexer = indexer
+	if indexer != nil {
+		m.loadingFile = filename
+		m.indexStart = time.Now()
+		m.loadError = ""
+	}
+}
+
+// setIndexed shows the lines of an indexer built off the event loop. After a
+// re-index the old indexer is closed and a tailing view kept at the bottom
+func (m *UnifiedModel) setIndexed(msg indexedMsg) {
+	reindex := m.reindexing
+	m.indexTime = msg.took
+	
+	// Streamed lines stay, after the file's
+	if stream, ok := m.indexer.(*StreamIndexer); ok {
+		msg.replaced = stream.swapBase(msg.indexer)
+		msg.indexer = stream
+	}
+	m.SetIndexer(msg.indexer, msg.filename)
+	if msg.replaced != nil {
+		msg.replaced.Close()
+	}
+	if reindex && m.tailing && len(m.filteredIndices) > 0 {
+		m.scrollToBottom()
+	}
+}
+
+// SetLoadError ends indexing and reports why the file couldn't be read
+func (m *UnifiedModel) SetLoadError(filename string, err error) {
+	m.indexing = false
+	m.reindexing = false
+	m.loadingIndexer = nil
+	m.loadError = fmt.Sprintf("Failed to load %s: %v", filename, err)
+}
+
+// entryFilter holds the parsed filter inputs, so streamed entries are checked
+// without parsing them again for every entry
+type entryFilter struct {
+	includes []filterPattern
+	excludes []filterPattern
+	sources  []string
+	window   timeRange
+}
+
+// entryFilter parses the current filter inputs
+func (m *UnifiedModel) entryFilter() entryFilter {
+	return entryFilter{
+		includes: m.parseFilterPatterns(m.includeInput.Value()),
+		excludes: m.parseFilterPatterns(m.excludeInput.Value()),
+		sources:  splitSourceFilter(m.sourceInput.Value()),
+		window:   m.timeWindow(),
+	}
+}
+
+// passes reports whether a streamed entry is shown with the filter
+func (m *UnifiedModel) passes(entry LogEntry, filter entryFilter) bool {
+	stage, _ := m.entryStage(entry, filter)
+	return stage == stageShown
+}
+
+// entryStage runs the entry through the filters in order, returning the
+// stage that removed it and whether the include patterns matched it
+func (m *UnifiedModel) entryStage(entry LogEntry, filter entryFilter) (filterStage, bool) {
+	if !m.shouldShowLevel(entry.Level) {
+		return stageLevel, false
+	}
+	if !m.matchesSource(entry, filter.sources) {
+		return stageSource, false
+	}
+	if !filter.window.contains(entry.Time) {
+		return stageTime, false
 	}
-	m.entries = append(m.entries, entry)
-	
-	// Add to filtered entries if it passes filters
-	includePatterns := strings.Split(m.includeInput.Value(), ",")
-	excludePatterns := strings.Split(m.excludeInput.Value(), ",")
 	
-	// Clean patterns
-	for i := range includePatterns {
-		includePatterns[i] = strings.TrimSpace(includePatterns[i])
+	// Scoped include patterns only apply to their source
+	pass, matched := m.includes(entry, filter.includes)
+	if !pass {
+		return stageInclude, false
 	}
-	for i := range excludePatterns {
-		excludePatterns[i] = strings.TrimSpace(excludePatterns[i])
+	if m.excludes(entry, filter.excludes) {
+		return stageExclude, false
 	}
 	
-	// Check log level
-	if !m.shouldShowLevel(entry.Level) {
-		return
+	// The messages hidden with x
+	if m.isSuppressed(entry) {
+		return stageSuppressed, false
 	}
+	return stageShown, matched
+}
+
+// AddLogEntry adds a log entry to the model (for testing)
+func (m *UnifiedModel) AddLogEntry(entry LogEntry) {
+	m.AddLogBatch([]LogEntry{entry})
+}
+
+// AddLogBatch adds streamed entries, keeping at most MaxLines of them, and
+// shows them. Only the new entries are filtered, applyFilters rescans them
+// all when a filter changes
+func (m *UnifiedModel) AddLogBatch(entries []LogEntry) {
+	evicted := m.appendEntries(entries)
+	m.showStream(len(entries), evicted)
+}
+
+// appendEntries adds streamed entries to the kept ones, counting them, and
+// returns the oldest ones evicted to stay within MaxLines
+func (m *UnifiedModel) appendEntries(entries []LogEntry) []LogEntry {
+	filter := m.entryFilter()
+	
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	
-	// Check exclude patterns
-	for _, pattern := range excludePatterns {
-		if pattern != "" && m.matchesPattern(entry.Message, pattern) {
-			return
+	if m.paused {
+		m.pausedLines += len(entries)
+	}
+	m.lineRate.add(time.Now(), len(entries))
+	for _, entry := range entries {
+		m.entries = append(m.entries, entry)
+		m.countStream(&m.streamLevels, entry.Level, 1)
+		passes := m.passes(entry, filter)
+		if m.summary != nil {
+			m.summary.add(entry, passes)
+		}
+		if passes {
+			m.filteredEntries = append(m.filteredEntries, entry)
+			m.countStream(&m.streamShown, entry.Level, 1)
 		}
 	}
 	
-	// Check include patterns
-	if m.includeInput.Value() != "" {
-		matched := false
-		for _, pattern := range includePatterns {
-			if pattern != "" && m.matchesPattern(entry.Message, pattern) {
-				matched = true
-				break
+	// Remember how far the journal was read for the next session
+	if unit := m.config.JournalUnit; unit != "" && len(entries) > 0 {
+		if cursor, ok := entries[len(entries)-1].Metadata["cursor"].(string); ok {
+			if m.journalCursors == nil {
+				m.journalCursors = make(map[string]string)
 			}
+			m.journalCursors[unit] = cursor
 		}
-		if !matched {
-			return
+	}
+	
+	var evicted []LogEntry
+	if limit := m.config.MaxLines; limit > 0 {
+		if over := len(m.entries) - limit; over > 0 {
+			// The evicted entries that passed are the first filtered ones
+			evicted = m.entries[:over]
+			shown := 0
+			for _, entry := range evicted {
+				m.countStream(&m.streamLevels, entry.Level, -1)
+				if m.passes(entry, filter) {
+					shown++
+				}
+			}
+			shown = min(shown, len(m.filteredEntries))
+			for _, entry := range m.filteredEntries[:shown] {
+				m.countStream(&m.streamShown, entry.Level, -1)
+			}
+			m.entries = m.entries[over:]
+			m.filteredEntries = m.filteredEntries[shown:]
 		}
 	}
+	return evicted
+}
+
+// refilterEntries filters all streamed entries again after a filter change
+func (m *UnifiedModel) refilterEntries() {
+	filter := m.entryFilter()
+	
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	
-	// Add to filtered entries
-	m.filteredEntries = append(m.filteredEntries, entry)
+	m.filteredEntries = nil
+	m.streamShown = levelCounts{}
+	for _, entry := range m.entries {
+		if m.passes(entry, filter) {
+			m.filteredEntries = append(m.filteredEntries, entry)
+			m.countStream(&m.streamShown, entry.Level, 1)
+		}
+	}
+}
+
+// countStream adds delta to the streamed entries counted at level, unless
+// counting is off with --no-stats
+func (m *UnifiedModel) countStream(counts *levelCounts, level LogLevel, delta int) {
+	if !m.config.NoStats {
+		counts[level] += delta
+	}
+}
+
+// levelTotals returns how many entries of the file or stream are at each
+// level, regardless of the filters. It reports false before anything loaded
+func (m *UnifiedModel) levelTotals() (levelCounts, bool) {
+	if m.config.NoStats {
+		return levelCounts{}, false
+	}
+	if m.indexer != nil && m.filterStats != nil {
+		return m.filterStats.levels, true
+	}
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.streamLevels, len(m.entries) > 0
 }
 
 // Helper functions
@@ -1193,12 +2585,19 @@ func (m *UnifiedModel) renderLogHeader(width int) string {
 	// Calculate column widths
 	timeWidth := 19  // "2023-12-23 15:30:45"
 	levelWidth := 5  // "ERROR"
+	if !m.showTime {
+		timeWidth = 0
+	}
 	messageWidth := width - timeWidth - levelWidth - 4 // borders
 	
 	if messageWidth <= 0 {
 		return ""
 	}
 	
+	if !m.showTime {
+		return fmt.Sprintf("%-*s | %s", levelWidth, "LEVEL", "MESSAGE")
+	}
+	
 	header := fmt.Sprintf("%-*s | %-*s | %s", 
 		timeWidth, "TIME", 
 		levelWidth, "LEVEL", 
@@ -1216,36 +2615,65 @@ func (m *UnifiedModel) formatLogEntryColumns(entry LogEntry, width int) string {
 	// Calculate column widths
 	timeWidth := 19  // "2023-12-23 15:30:45"
 	levelWidth := 5  // "ERROR"
+	if !m.showTime {
+		timeWidth = 0
+	}
 	messageWidth := width - timeWidth - levelWidth - 4 // borders
 	
+	message := m.displayMessage(entry)
 	if messageWidth <= 0 {
-		return entry.Message[:min(len(entry.Message), width)]
+		return ansi.Truncate(message, max(width, 0), "")
 	}
 	
 	// Truncate message if too long
-	message := entry.Message
-	if len(message) > messageWidth {
-		message = message[:messageWidth-3] + "..."
-	}
+	message = ansi.Truncate(message, messageWidth, "...")
 	
-	formatted := fmt.Sprintf("%-*s | %-*s | %s",
-		timeWidth, entry.Timestamp,
-		levelWidth, entry.Level.String(),
-		message)
+	var formatted string
+	if !m.showTime {
+		formatted = fmt.Sprintf("%-*s | %s", levelWidth, entry.Level.String(), message)
+	} else {
+		formatted = fmt.Sprintf("%-*s | %-*s | %s",
+			timeWidth, m.columnTimestamp(entry),
+			levelWidth, entry.Level.String(),
+			message)
+	}
 	
+	if m.rowColorMode {
+		return tint(m.levelStyles[entry.Level], formatted)
+	}
 	return formatted
 }
 
+// tint renders row in style, starting the style again after each reset
+// inside the row so highlighted parts don't end it partway through
+func tint(style lipgloss.Style, row string) string {
+	open, _, _ := strings.Cut(style.Render("x"), "x")
+	if open == "" {
+		return style.Render(row)
+	}
+	return style.Render(strings.ReplaceAll(row, ansiReset, ansiReset+open))
+}
+
 // checkFileChanges monitors the file for changes and re-indexes if modified
-func (m *UnifiedModel) checkFileChanges() {
+func (m *UnifiedModel) checkFileChanges() tea.Cmd {
 	if m.config.Files == nil || len(m.config.Files) == 0 {
-		return
+		return nil
 	}
 	
-	filename := m.config.Files[0] // Check first file for now
+	// Check the first file that can be read again, not a named pipe
+	filename := ""
+	for _, file := range m.config.Files {
+		if !isNamedPipe(file) {
+			filename = file
+			break
+		}
+	}
+	if filename == "" {
+		return nil
+	}
 	stat, err := os.Stat(filename)
 	if err != nil {
-		return // File might not exist
+		return nil // File might not exist
 	}
 	
 	modTime := stat.ModTime()
@@ -1256,47 +2684,53 @@ func (m *UnifiedModel) checkFileChanges() {
 		
 		// Only re-index if this isn't the first check (avoid duplicate indexing on startup)
 		if !m.lastModTime.IsZero() && m.indexer != nil {
-			m.reindexFile(filename)
+			return m.reindexFile(filename)
 		}
 	}
+	return nil
 }
 
-// reindexFile re-indexes a file when it changes
-func (m *UnifiedModel) reindexFile(filename string) {
+// reindexFile re-indexes a file when it changes. The returned command
+// indexes it in the background and hands the result back as a message
+func (m *UnifiedModel) reindexFile(filename string) tea.Cmd {
 	if m.indexing {
-		return // Already indexing
+		return nil // Already indexing
 	}
 	
-	// Close old indexer
-	if m.indexer != nil {
-		m.indexer.Close()
-	}
-	
-	// Create new indexer
+	// Create new indexer, the old one keeps serving lines if that fails
 	indexer, err := NewFastIndexer(filename, m.parser)
 	if err != nil {
-		return
+		m.notice = fmt.Sprintf("Re-index %s: %v", m.sourceLabel(filename), err)
+		return nil
 	}
+	indexer.SetMemoryLimit(m.config.MaxIndexMemory)
 	
-	// Start indexing in background
-	go func() {
-		m.indexing = true
-		m.loadingFile = filename
-		
-		start := time.Now()
-		if err := indexer.IndexFileUltraFast(); err != nil {
-			indexer.Close()
-			m.indexing = false
-			return
+	// Stay tail-only if the full scan was already abandoned once
+	old := m.indexer
+	tailOnly := old != nil && old.TailOffset() > 0
+	
+	start := time.Now()
+	m.indexing = true
+	m.reindexing = true
+	m.loadingFile = filename
+	m.loadingIndexer = indexer
+	m.indexStart = start
+	
+	// Index in the background, the model only changes in Update
+	return func() tea.Msg {
+		var err error
+		if tailOnly {
+			err = indexer.IndexTail(tailFallbackBytes)
+		} else {
+			err = indexer.IndexFileUltraFast()
 		}
-		
-		// Update model with new indexer
-		m.indexTime = time.Since(start)
-		m.SetIndexer(indexer, filename)
-		
-		// Scroll to bottom if tailing is enabled
-		if m.tailing && len(m.filteredIndices) > 0 {
-			m.scrollToBottom()
+		if errors.Is(err, ErrIndexCancelled) {
+			err = indexer.IndexTail(tailFallbackBytes)
+		}
+		if err != nil {
+			indexer.Close()
+			return loadFailedMsg{filename: filename, err: err}
 		}
-	}()
+		return indexedMsg{indexer: indexer, filename: filename, took: time.Since(start), replaced: old}
+	}
 }
\ No newline at end of file
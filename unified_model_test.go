@@ -0,0 +1,1603 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+)
+
+// useANSIColors renders colors on a dark background for the rest of the test,
+// restoring lipgloss' settings afterwards
+func useANSIColors(t *testing.T) {
+	profile, dark := lipgloss.ColorProfile(), lipgloss.HasDarkBackground()
+	t.Cleanup(func() {
+		lipgloss.SetColorProfile(profile)
+		lipgloss.SetHasDarkBackground(dark)
+	})
+	lipgloss.SetColorProfile(termenv.ANSI256)
+	lipgloss.SetHasDarkBackground(true)
+}
+
+// newIndexedTestModel indexes the given lines from a temporary file and sizes
+// the model like a real terminal would
+func newIndexedTestModel(t *testing.T, lines []string, width, height int) *UnifiedModel {
+	t.Helper()
+
+	testFile := filepath.Join(t.TempDir(), "test.log")
+	if err := os.WriteFile(testFile, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	config := &Config{
+		MaxLines:    100,
+		Files:       []string{testFile},
+		RefreshRate: 1,
+		Include:     "",
+		Exclude:     "",
+		Timezone:    "UTC",
+		Tail:        defaultTail,
+	}
+
+	app := NewUnifiedApp(config)
+	app.indexFile(testFile)
+	app.model.Update(tea.WindowSizeMsg{Width: width, Height: height})
+	return app.model
+}
+
+func numberedLines(n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("2023-12-23 15:30:45 INFO: line %d", i+1)
+	}
+	return lines
+}
+
+func keyMsg(key string) tea.KeyMsg {
+	switch key {
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "up":
+		return tea.KeyMsg{Type: tea.KeyUp}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+}
+
+func TestSplitView_PreviewFollowsSelection(t *testing.T) {
+	model := newIndexedTestModel(t, numberedLines(50), 120, 40)
+
+	model.Update(keyMsg("v"))
+	if !model.isSplit() {
+		t.Fatal("Expected split view to be enabled")
+	}
+
+	model.Update(keyMsg("j"))
+	model.Update(keyMsg("j"))
+
+	view := model.View()
+	if !strings.Contains(view, "PREVIEW") {
+		t.Error("Expected preview pane to be rendered")
+	}
+	if !strings.Contains(view, "line 3") {
+		t.Error("Expected preview to show the selected entry")
+	}
+
+	// tab cycles list -> preview -> left panel
+	model.Update(keyMsg("tab"))
+	if model.focus != PreviewPanel {
+		t.Errorf("Expected focus on preview, got %v", model.focus)
+	}
+	model.Update(keyMsg("j"))
+	if model.previewScroll != 1 {
+		t.Errorf("Expected preview to scroll independently, got %d", model.previewScroll)
+	}
+	model.Update(keyMsg("tab"))
+	if model.focus != LeftPanel {
+		t.Errorf("Expected focus on left panel, got %v", model.focus)
+	}
+}
+
+func TestSplitView_RefusesSmallHeight(t *testing.T) {
+	model := newIndexedTestModel(t, numberedLines(10), 80, minSplitHeight-1)
+
+	model.Update(keyMsg("v"))
+	if model.splitView {
+		t.Error("Expected split view to be refused below the minimum height")
+	}
+
+	model.Update(tea.WindowSizeMsg{Width: 80, Height: minSplitHeight})
+	model.Update(keyMsg("v"))
+	model.Update(keyMsg("tab"))
+
+	// Shrinking the terminal falls back to the plain list
+	model.Update(tea.WindowSizeMsg{Width: 80, Height: 10})
+	if model.isSplit() {
+		t.Error("Expected split to be disabled when the terminal is too short")
+	}
+	if model.focus == PreviewPanel {
+		t.Error("Expected focus to leave the hidden preview")
+	}
+	if model.View() == "" {
+		t.Error("Expected view to render after shrinking")
+	}
+}
+
+func TestLevelColors_ConsistentAcrossRenderers(t *testing.T) {
+	useANSIColors(t)
+
+	model := NewUnifiedModel(&Config{MaxLines: 100, RefreshRate: 1, Timezone: "UTC"})
+	model.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	leftPanel := model.renderLeftPanel()
+
+	for _, level := range []LogLevel{DEBUG, INFO, WARN, ERROR} {
+		entry := LogEntry{Timestamp: "2023-12-23T15:30:45Z", Level: level, Message: "fixture", Source: "test.log"}
+		outputs := map[string]string{
+			"list":   model.formatColumnLogEntry(entry, false, false),
+			"left":   leftPanel,
+			"detail": model.renderEntryDetail(entry, 0, 10),
+		}
+
+		// Capture the escape sequence that colors the level name in each output
+		pattern := regexp.MustCompile(`((?:\x1b\[[0-9;]*m)+)\[?` + level.String() + `\b`)
+		colors := map[string]bool{}
+		for name, output := range outputs {
+			match := pattern.FindStringSubmatch(output)
+			if match == nil {
+				t.Errorf("%s: expected %s to be colored in %s renderer", level, level, name)
+				continue
+			}
+			colors[match[1]] = true
+		}
+
+		if len(colors) != 1 {
+			t.Errorf("%s: expected one color across renderers, got %v", level, colors)
+		}
+		reference := lipgloss.NewStyle().Foreground(level.Color()).Render(level.String())
+		for color := range colors {
+			if !strings.HasPrefix(reference, color) {
+				t.Errorf("%s: expected color from LogLevel.Color (%q), got %q", level, reference, color)
+			}
+		}
+	}
+}
+
+func TestTimeRangeFilter_SkipsEntriesOutsideWindow(t *testing.T) {
+	lines := []string{
+		"2023-12-23 15:20:00 INFO: before window",
+		"2023-12-23 15:35:00 INFO: inside window",
+		"2023-12-23 15:50:00 INFO: after window",
+		`127.0.0.1 - - [23/Dec/2023:15:00:00 +0000] "GET /health HTTP/1.1" 200 12`,
+	}
+	model := newIndexedTestModel(t, lines, 120, 40)
+
+	model.sinceInput.SetValue("2023-12-23 15:30:00")
+	model.untilInput.SetValue("2023-12-23 15:45:00")
+	model.applyFilters()
+
+	var messages []string
+	for _, entry := range model.visibleEntries {
+		messages = append(messages, entry.Message)
+	}
+	if len(messages) != 2 {
+		t.Fatalf("Expected 2 entries inside the window, got %d: %v", len(messages), messages)
+	}
+	if !strings.Contains(messages[0], "inside window") || !strings.Contains(messages[1], "/health") {
+		t.Errorf("Unexpected entries in window: %v", messages)
+	}
+}
+
+func TestDetailView_DescribesErrorCodes(t *testing.T) {
+	path := writeErrorCodes(t, `{"ERR_1042": "Connection pool exhausted"}`)
+	catalog, err := LoadErrorCatalog(path, "")
+	if err != nil {
+		t.Fatalf("Failed to load error codes: %v", err)
+	}
+
+	model := NewUnifiedModel(&Config{Timezone: "UTC", ErrorCatalog: catalog})
+	detail := model.renderEntryDetail(LogEntry{Level: ERROR, Message: "query failed: ERR_1042 then ERR_9999"}, 0, 10)
+
+	if !strings.Contains(detail, "ERR_1042: Connection pool exhausted") {
+		t.Errorf("Expected known code to be described, got:\n%s", detail)
+	}
+	if !strings.Contains(detail, "ERR_9999\n") {
+		t.Errorf("Expected unknown code to be shown raw, got:\n%s", detail)
+	}
+}
+
+func TestDetailView_MarksWrappedRows(t *testing.T) {
+	model := NewUnifiedModel(&Config{Timezone: "UTC", WrapMarkers: true})
+	model.rightWidth = 20
+	entry := LogEntry{Level: INFO, Message: "connection reset by peer while reading\nretrying"}
+
+	detail := model.renderEntryDetail(entry, 0, 10)
+	if !strings.Contains(detail, "\n↳ ") {
+		t.Errorf("Expected continuation rows to be marked, got:\n%s", detail)
+	}
+	if !strings.Contains(detail, "\nretrying\n") {
+		t.Errorf("Expected a new message line to stay unmarked, got:\n%s", detail)
+	}
+
+	model.wrapMarkers = false
+	if detail := model.renderEntryDetail(entry, 0, 10); strings.Contains(detail, "↳") {
+		t.Errorf("Expected no markers when disabled, got:\n%s", detail)
+	}
+}
+
+func TestDetailView_MetadataSortedAndNestedAsJSON(t *testing.T) {
+	model := NewUnifiedModel(&Config{Timezone: "UTC"})
+	model.rightWidth = 80
+	entry := LogEntry{Level: INFO, Message: "request", Metadata: map[string]interface{}{
+		"zone": "eu", "method": "GET", "status": 200, "attributes": map[string]interface{}{"http.route": "/users"},
+		"tags": []interface{}{"a", "b"}, "duration_ms": 12.5,
+	}}
+
+	detail := model.renderEntryDetail(entry, 0, 10)
+	for i := 0; i < 20; i++ {
+		if again := model.renderEntryDetail(entry, 0, 10); again != detail {
+			t.Fatalf("Expected the same output on every render, got:\n%s\nthen:\n%s", detail, again)
+		}
+	}
+
+	order := []string{"attributes:", "duration_ms:", "method:", "status:", "tags:", "zone:"}
+	last := -1
+	for _, key := range order {
+		at := strings.Index(detail, key)
+		if at <= last {
+			t.Fatalf("Expected %s after the keys before it, got:\n%s", key, detail)
+		}
+		last = at
+	}
+	if !strings.Contains(detail, "attributes: {\n  \"http.route\": \"/users\"\n}\n") {
+		t.Errorf("Expected nested attributes as indented JSON, got:\n%s", detail)
+	}
+	if !strings.Contains(detail, "tags: [\n  \"a\",\n  \"b\"\n]\n") {
+		t.Errorf("Expected a list as indented JSON, got:\n%s", detail)
+	}
+}
+
+func TestColumns_AlignedWithCombiningAndWideCharacters(t *testing.T) {
+	model := NewUnifiedModel(&Config{Timezone: "UTC"})
+	model.rightWidth = 80
+	model.showComponent = true
+
+	entries := []LogEntry{
+		{Level: INFO, Component: "api", Message: "MSG " + strings.Repeat("plain ", 20)},
+		{Level: INFO, Component: "cafe\u0301", Message: "MSG " + strings.Repeat("re\u0301sume\u0301 ", 20)},
+		{Level: INFO, Component: "שלום-עולם", Message: "MSG " + strings.Repeat("שָׁלוֹם ", 20)},
+		{Level: INFO, Component: "日本語のサービス名前", Message: "MSG " + strings.Repeat("日本語 ", 20)},
+	}
+
+	var prefixWidth, rowWidth int
+	for i, entry := range entries {
+		row := ansi.Strip(model.formatColumnLogEntry(entry, false, false))
+		prefix := ansi.StringWidth(row[:strings.Index(row, "MSG")])
+		width := ansi.StringWidth(row)
+		if i == 0 {
+			prefixWidth, rowWidth = prefix, width
+			continue
+		}
+		if prefix != prefixWidth {
+			t.Errorf("Expected the message column at %d cells, got %d for %q", prefixWidth, prefix, entry.Component)
+		}
+		if width > rowWidth {
+			t.Errorf("Expected at most %d cells, got %d for %q", rowWidth, width, entry.Component)
+		}
+	}
+}
+
+func TestHeader_ShowsIndexingProgressAndLoadError(t *testing.T) {
+	testFile := writeTestLog(t, numberedLines(10))
+	indexer, err := NewFastIndexer(testFile, NewLogParser("UTC"))
+	if err != nil {
+		t.Fatalf("Failed to create indexer: %v", err)
+	}
+	defer indexer.Close()
+
+	model := NewUnifiedModel(&Config{Timezone: "UTC", RefreshRate: 1})
+	model.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	model.indexing = true
+	model.loadingFile = testFile
+	model.loadingIndexer = indexer
+
+	if header := model.renderHeader(); !strings.Contains(header, "0%") || !strings.Contains(header, "MB/s") {
+		t.Errorf("Expected progress and throughput in header, got %q", header)
+	}
+
+	// Esc cancels the in-progress scan
+	model.Update(keyMsg("esc"))
+	if err := indexer.IndexFileUltraFast(); err != ErrIndexCancelled {
+		t.Errorf("Expected Esc to cancel indexing, got %v", err)
+	}
+
+	model.SetLoadError(testFile, fmt.Errorf("input/output error"))
+	if header := model.renderHeader(); !strings.Contains(header, "input/output error") {
+		t.Errorf("Expected load error in header, got %q", header)
+	}
+}
+
+func TestTimeColumn_Toggle(t *testing.T) {
+	model := newIndexedTestModel(t, numberedLines(5), 120, 40)
+
+	view := model.renderLogStream()
+	if !strings.Contains(view, "TIME") || !strings.Contains(view, "2023-12-23T15:30:45Z") {
+		t.Fatal("Expected TIME column to be shown by default")
+	}
+
+	// T shows relative times first, the detail view keeps the timestamp
+	model.Update(keyMsg("T"))
+	view = model.renderLogStream()
+	if !strings.Contains(view, "TIME") || strings.Contains(view, "2023-12-23T15:30:45Z") || !strings.Contains(view, "d ") {
+		t.Errorf("Expected relative times after pressing T, got:\n%s", view)
+	}
+	if detail := model.renderEntryDetail(model.visibleEntries[0], 0, 10); !strings.Contains(detail, "2023-12-23T15:30:45Z") {
+		t.Errorf("Expected the absolute time in the detail view, got:\n%s", detail)
+	}
+
+	model.Update(keyMsg("T"))
+	view = model.renderLogStream()
+	if strings.Contains(view, "TIME") || strings.Contains(view, "2023-12-23T15:30:45Z") {
+		t.Error("Expected TIME column to be hidden after pressing T again")
+	}
+	if !strings.Contains(view, "LEVEL    MESSAGE") {
+		t.Error("Expected LEVEL and MESSAGE headers to remain")
+	}
+
+	header := model.renderLogHeader(80)
+	if !strings.HasPrefix(header, "LEVEL | MESSAGE") {
+		t.Errorf("Expected column header without TIME, got %q", header)
+	}
+}
+
+func TestTimeColumn_NoTimeFlag(t *testing.T) {
+	model := NewUnifiedModel(&Config{Timezone: "UTC", NoTime: true})
+	entry := LogEntry{Timestamp: "2023-12-23 15:30:45", Level: INFO, Message: "hello"}
+
+	if line := model.formatLogEntryColumns(entry, 80); line != "INFO  | hello" {
+		t.Errorf("Expected message without time, got %q", line)
+	}
+}
+
+func TestDisplayMessage_EmptyMessages(t *testing.T) {
+	model := NewUnifiedModel(&Config{Timezone: "UTC", NoTime: true})
+	parser := NewLogParser("UTC")
+
+	otlp := parser.ParseLogLine(`{"severityText": "INFO", "body": "", "attributes": {"status": 200, "service": "api"}, "resource": {"host": {"name": "web-1"}}}`, "")
+	if message := model.displayMessage(otlp); message != "service=api status=200 resource.host.name=web-1" {
+		t.Errorf("Expected attributes then other metadata, got %q", message)
+	}
+	if line := model.formatLogEntryColumns(otlp, 80); line != "INFO  | service=api status=200 resource.host.name=web-1" {
+		t.Errorf("Expected the metadata in the row, got %q", line)
+	}
+
+	empty := LogEntry{Level: INFO}
+	if message := model.displayMessage(empty); message != noMessage {
+		t.Errorf("Expected %q, got %q", noMessage, message)
+	}
+	if row := model.formatColumnLogEntry(empty, false, false); !strings.Contains(row, noMessage) {
+		t.Errorf("Expected the placeholder in the row, got %q", row)
+	}
+}
+
+func TestComponentColumn_Toggle(t *testing.T) {
+	lines := []string{
+		`{"severityText": "INFO", "body": "listening", "logger": "http.server"}`,
+		`{"severityText": "INFO", "body": "connected", "logger": "storage.postgres.pool"}`,
+	}
+	model := newIndexedTestModel(t, lines, 120, 40)
+
+	if view := model.renderLogStream(); strings.Contains(view, "COMPONENT") {
+		t.Fatal("Expected COMPONENT column to be hidden by default")
+	}
+
+	model.Update(keyMsg("C"))
+	view := model.renderLogStream()
+	if !strings.Contains(view, "LEVEL    COMPONENT        MESSAGE") {
+		t.Errorf("Expected COMPONENT header, got:\n%s", view)
+	}
+	if !strings.Contains(view, "http.server      listening") || !strings.Contains(view, "storage.postgre~ connected") {
+		t.Errorf("Expected padded and truncated components, got:\n%s", view)
+	}
+}
+
+func TestRedact_AppliedAtRenderTime(t *testing.T) {
+	redactor, err := NewRedactor([]string{"email"})
+	if err != nil {
+		t.Fatalf("Failed to create redactor: %v", err)
+	}
+	model := NewUnifiedModel(&Config{Timezone: "UTC", Redactor: redactor})
+	model.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	entry := LogEntry{
+		Level:    INFO,
+		Message:  "password reset for jane@example.com",
+		Metadata: map[string]interface{}{"user": "jane@example.com"},
+	}
+
+	for name, text := range map[string]string{
+		"list":   model.formatColumnLogEntry(entry, false, false),
+		"detail": model.renderEntryDetail(entry, 0, 10),
+	} {
+		if strings.Contains(text, "jane@example.com") || !strings.Contains(text, "***") {
+			t.Errorf("Expected %s to be redacted, got %q", name, text)
+		}
+	}
+	if entry.Message != "password reset for jane@example.com" {
+		t.Error("Expected the underlying entry to stay intact")
+	}
+
+	// R reveals the original text for local viewing
+	model.Update(keyMsg("R"))
+	if text := model.formatColumnLogEntry(entry, false, false); !strings.Contains(text, "jane@example.com") {
+		t.Errorf("Expected unredacted message after R, got %q", text)
+	}
+}
+
+// chdirTemp runs the test from a temporary directory, where exports are written
+func chdirTemp(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	return dir
+}
+
+func TestExport_MarkedRangeAndTimeWindow(t *testing.T) {
+	lines := []string{
+		"2023-12-23 09:39:00 INFO: before",
+		"2023-12-23 09:40:00 ERROR: \x1b[31mfailed\x1b[0m",
+		"2023-12-23 09:42:00 DEBUG: retrying",
+		"2023-12-23 09:45:00 INFO: recovered",
+		"2023-12-23 09:50:00 INFO: after",
+	}
+	model := newIndexedTestModel(t, lines, 120, 40)
+	dir := chdirTemp(t)
+
+	// Visual selection: lines 2 through 4
+	model.scrollToTop()
+	model.Update(keyMsg("j"))
+	model.Update(keyMsg("V"))
+	model.Update(keyMsg("j"))
+	model.Update(keyMsg("j"))
+	if !strings.Contains(model.renderLogStream(), "┃") {
+		t.Error("Expected marked rows to be shown")
+	}
+	model.Update(keyMsg("E"))
+
+	exports, _ := filepath.Glob(filepath.Join(dir, "test.*-*.log"))
+	if len(exports) != 1 {
+		t.Fatalf("Expected one export, got %v (notice %q)", exports, model.notice)
+	}
+	data, _ := os.ReadFile(exports[0])
+	if string(data) != strings.Join(lines[1:4], "\n")+"\n" {
+		t.Errorf("Unexpected export content %q", data)
+	}
+	os.Remove(exports[0])
+
+	// Time window, with level filters not narrowing the byte range
+	model.showDebug = false
+	model.sinceInput.SetValue("2023-12-23 09:40:00")
+	model.untilInput.SetValue("2023-12-23 09:45:00")
+	model.applyFilters()
+	model.Update(keyMsg("E"))
+
+	exports, _ = filepath.Glob(filepath.Join(dir, "test.*-*.log"))
+	if len(exports) != 1 {
+		t.Fatalf("Expected one export, got %v (notice %q)", exports, model.notice)
+	}
+	data, _ = os.ReadFile(exports[0])
+	if string(data) != strings.Join(lines[1:4], "\n")+"\n" {
+		t.Errorf("Unexpected export content %q", data)
+	}
+}
+
+func TestExport_WindowLines(t *testing.T) {
+	ordered := []string{
+		"2023-12-23 09:30:00 INFO: before",
+		"2023-12-23 09:41:00 ERROR: first inside",
+		"    at handler.go:12",
+		"2023-12-23 09:44:00 INFO: last inside",
+		"    at worker.go:7",
+		"2023-12-23 09:50:00 INFO: after",
+	}
+	window := timeRange{since: time.Date(2023, 12, 23, 9, 40, 0, 0, time.UTC), until: time.Date(2023, 12, 23, 9, 45, 0, 0, time.UTC)}
+	for name, tc := range map[string]struct {
+		lines       []string
+		first, last int
+	}{
+		"ordered":      {ordered, 1, 3},
+		"out of order": {append([]string{"2023-12-23 09:42:00 WARN: late"}, ordered...), 0, 4},
+	} {
+		model := newIndexedTestModel(t, tc.lines, 120, 40)
+		first, last, found := model.windowLines(window)
+		if !found || first != tc.first || last != tc.last {
+			t.Errorf("%s: expected lines %d-%d, got %d-%d (%v)", name, tc.first, tc.last, first, last, found)
+		}
+	}
+
+	model := newIndexedTestModel(t, ordered, 120, 40)
+	if _, _, found := model.windowLines(timeRange{since: time.Date(2023, 12, 24, 0, 0, 0, 0, time.UTC)}); found {
+		t.Error("Expected nothing after the last line")
+	}
+}
+
+func TestExport_MarkdownTable(t *testing.T) {
+	lines := []string{
+		`{"time":"2023-12-23T15:30:45Z","level":"error","msg":"query a|b failed\nretrying"}`,
+		`{"time":"2023-12-23T15:30:46Z","level":"debug","msg":"skipped"}`,
+		`{"time":"2023-12-23T15:30:47Z","level":"info","msg":"` + strings.Repeat("x", markdownMessageLimit+10) + `"}`,
+	}
+	model := newIndexedTestModel(t, lines, 120, 40)
+	dir := chdirTemp(t)
+	model.showDebug = false
+	model.applyFilters()
+
+	model.Update(keyMsg("M"))
+	data, err := os.ReadFile(filepath.Join(dir, "test.filtered.md"))
+	if err != nil {
+		t.Fatalf("Expected a Markdown export (notice %q): %v", model.notice, err)
+	}
+	expected := "| TIME | LEVEL | MESSAGE |\n|---|---|---|\n" +
+		"| 2023-12-23T15:30:45Z | ERROR | query a\\|b failed<br>retrying |\n" +
+		"| 2023-12-23T15:30:47Z | INFO | " + strings.Repeat("x", markdownMessageLimit) + "… |\n" +
+		"\n_1 of 2 messages were cut to 300 characters._\n"
+	if string(data) != expected {
+		t.Errorf("Unexpected Markdown export:\n%s", data)
+	}
+}
+
+func TestExport_JSONKeepsNestedMetadata(t *testing.T) {
+	lines := []string{
+		`{"time":"2023-12-23T15:30:45Z","level":"error","msg":"query failed","user":"bob@example.com","db":{"host":"primary","owner":"ops@example.com","pool":{"size":5}},"tags":["a","dba@example.com"]}`,
+		`{"time":"2023-12-23T15:30:46Z","level":"debug","msg":"skipped"}`,
+	}
+	model := newIndexedTestModel(t, lines, 120, 40)
+	redactor, err := NewRedactor([]string{"email"})
+	if err != nil {
+		t.Fatalf("Failed to create redactor: %v", err)
+	}
+	model.config.Redactor = redactor
+	dir := chdirTemp(t)
+	model.showDebug = false
+	model.applyFilters()
+
+	model.Update(keyMsg("J"))
+	data, err := os.ReadFile(filepath.Join(dir, "test.filtered.json"))
+	if err != nil {
+		t.Fatalf("Expected a JSON export (notice %q): %v", model.notice, err)
+	}
+
+	var exported []jsonExportEntry
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatalf("Expected a JSON array: %v\n%s", err, data)
+	}
+	if len(exported) != 1 || exported[0].Level != "ERROR" || exported[0].Message != "query failed" || exported[0].Timestamp != "2023-12-23T15:30:45Z" {
+		t.Fatalf("Expected the ERROR entry only, got %+v", exported)
+	}
+	db, ok := exported[0].Metadata["db"].(map[string]interface{})
+	if !ok || db["host"] != "primary" {
+		t.Fatalf("Expected nested metadata to stay nested, got %v", exported[0].Metadata)
+	}
+	if pool, ok := db["pool"].(map[string]interface{}); !ok || pool["size"] != float64(5) {
+		t.Errorf("Expected the doubly nested pool size, got %v", db["pool"])
+	}
+
+	// Metadata is redacted like the detail view, at any depth
+	if strings.Contains(string(data), "@example.com") {
+		t.Errorf("Expected every email redacted, got:\n%s", data)
+	}
+	if user, _ := exported[0].Metadata["user"].(string); !strings.Contains(user, "***") {
+		t.Errorf("Expected the user masked, got %q", user)
+	}
+}
+
+func TestExportMatchesJSON_WithoutUI(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.json")
+	stdin := strings.NewReader("2023-12-23 15:30:45 ERROR: upstream timeout\n2023-12-23 15:30:46 INFO: served\n")
+	exported, err := exportMatchesJSON(&Config{Timezone: "UTC", Include: "timeout"}, stdin, path)
+	if err != nil || exported != 1 {
+		t.Fatalf("Expected 1 entry exported, got %d (%v)", exported, err)
+	}
+
+	data, _ := os.ReadFile(path)
+	var entries []jsonExportEntry
+	if err := json.Unmarshal(data, &entries); err != nil || len(entries) != 1 || entries[0].Source != "stdin" {
+		t.Errorf("Expected the stdin entry, got %s (%v)", data, err)
+	}
+}
+
+func TestListWrap_WrapsLongEntries(t *testing.T) {
+	long := "2023-12-23 15:30:45 INFO: " + strings.Repeat("payload ", 40) + "tail"
+	lines := append(numberedLines(20), long, long)
+	model := newIndexedTestModel(t, lines, 120, 30)
+	model.scrollToBottom()
+
+	if view := model.renderLogStream(); strings.Contains(view, "tail") {
+		t.Fatalf("Expected the message cut by default")
+	}
+
+	model.Update(keyMsg("W"))
+	view := model.renderLogStream()
+	if !strings.Contains(view, "tail") {
+		t.Errorf("Expected the whole message when wrapped, got:\n%s", view)
+	}
+	if rows := model.entryRows(model.visibleEntries[model.selectedIdx]); rows < 2 {
+		t.Errorf("Expected the long entry over several rows, got %d", rows)
+	}
+
+	// The selected last entry stays in view and rows never exceed the viewport
+	if last := model.filteredIndices[model.viewportStart+model.selectedIdx]; last != 21 {
+		t.Errorf("Expected the last entry selected, got %d", last)
+	}
+	listRows := strings.Count(view, "\n") - 4 // Title, position, header and rule rows
+	if listRows > model.viewportHeight {
+		t.Errorf("Expected at most %d rows, got %d", model.viewportHeight, listRows)
+	}
+	rows := strings.Split(view, "\n")
+	if !strings.Contains(rows[len(rows)-2], "tail") {
+		t.Errorf("Expected the selected entry's last row at the bottom, got %q", rows[len(rows)-2])
+	}
+
+	// Continuation rows start under the message column
+	for _, row := range rows {
+		if strings.Contains(row, "tail") && !strings.HasPrefix(row, strings.Repeat(" ", 20)) {
+			t.Errorf("Expected an indented continuation row, got %q", row)
+		}
+	}
+
+	// With --wrap-markers continuation rows start with the marker
+	if strings.Contains(view, wrapMarker) {
+		t.Errorf("Expected no markers by default, got:\n%s", view)
+	}
+	model.wrapMarkers = true
+	for _, row := range strings.Split(model.renderLogStream(), "\n") {
+		if strings.Contains(row, "tail") && !strings.Contains(row, " "+wrapMarker) {
+			t.Errorf("Expected a marked continuation row, got %q", row)
+		}
+	}
+}
+
+func TestDetailView_ShowsByteOffset(t *testing.T) {
+	model := newIndexedTestModel(t, numberedLines(3), 120, 40)
+	model.scrollToTop()
+	model.Update(keyMsg("j"))
+	model.Update(keyMsg("enter"))
+
+	if view := model.renderDetailPanel(); !strings.Contains(view, "Offset:    33 (33 bytes)") {
+		t.Errorf("Expected byte offset in detail view, got:\n%s", view)
+	}
+}
+
+func TestRowColorMode_TintsWholeRow(t *testing.T) {
+	useANSIColors(t)
+
+	model := NewUnifiedModel(&Config{MaxLines: 100, RefreshRate: 1, Timezone: "UTC"})
+	model.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	entry := LogEntry{Timestamp: "2023-12-23T15:30:45Z", Level: ERROR, Message: "disk full"}
+	tint := regexp.MustCompile(`^((?:\x1b\[[0-9;]*m)+)`).FindStringSubmatch(model.levelStyles[ERROR].Render("x"))[1]
+
+	if line := model.formatColumnLogEntry(entry, false, false); strings.HasPrefix(line, "  "+tint) {
+		t.Error("Expected rows not to be tinted by default")
+	}
+
+	// Toggle through the left panel checkbox
+	model.focus = LeftPanel
+	model.leftPanelItem = rowColorItem
+	model.Update(keyMsg(" "))
+	if !model.rowColorMode {
+		t.Fatal("Expected the checkbox to enable row coloring")
+	}
+
+	if line := model.formatColumnLogEntry(entry, false, false); !strings.HasPrefix(line, "  "+tint+"2023-12-23T15:30:45Z") {
+		t.Errorf("Expected the whole row to be tinted, got %q", line)
+	}
+	if line := model.formatLogEntryColumns(entry, 80); !strings.HasPrefix(line, tint+"2023-12-23T15:30:45Z") {
+		t.Errorf("Expected the column row to be tinted, got %q", line)
+	}
+
+	// A highlighted match doesn't end the tint for the rest of the row
+	model.searchQuery = "disk"
+	line := model.formatColumnLogEntry(LogEntry{Timestamp: "2023-12-23T15:30:45Z", Level: ERROR, Message: "disk full now"}, false, false)
+	if _, rest, _ := strings.Cut(line, "disk"+ansiReset); !strings.HasPrefix(rest, tint) {
+		t.Errorf("Expected the tint again after the highlight, got %q", line)
+	}
+	model.searchQuery = ""
+
+	// The selection highlight takes precedence
+	selected := model.formatColumnLogEntry(entry, true, false)
+	if !strings.HasPrefix(selected, "▶ "+regexp.MustCompile(`^((?:\x1b\[[0-9;]*m)+)`).FindString(model.selectedStyle.Render("x"))) {
+		t.Errorf("Expected the selected row to use the selection style, got %q", selected)
+	}
+}
+
+func TestLeftPanel_ScrollsOnShortTerminal(t *testing.T) {
+	model := NewUnifiedModel(&Config{MaxLines: 100, RefreshRate: 1, Timezone: "UTC"})
+	model.loadingFile = "app.log"
+	model.Update(tea.WindowSizeMsg{Width: 120, Height: 15})
+	model.focus = LeftPanel
+
+	if view := model.renderLeftPanel(); strings.Contains(view, "Files:") {
+		t.Fatal("Expected the files section to be cut off on a short terminal")
+	}
+
+	// Walking down to the last item brings the bottom sections into view
+	for i := 0; i < leftPanelItemCount-1; i++ {
+		model.Update(keyMsg("j"))
+	}
+	view := model.renderLeftPanel()
+	if !strings.Contains(view, "Files:") || !strings.Contains(view, "app.log") {
+		t.Errorf("Expected the files section after scrolling, got:\n%s", view)
+	}
+	if model.leftScrollOffset == 0 {
+		t.Error("Expected the left panel to be scrolled")
+	}
+
+	// And back up to the first item
+	for i := 0; i < leftPanelItemCount-1; i++ {
+		model.Update(keyMsg("k"))
+	}
+	if view := model.renderLeftPanel(); !strings.Contains(view, "▶ Include") {
+		t.Errorf("Expected the include filter back in view, got:\n%s", view)
+	}
+	if model.leftScrollOffset != 0 {
+		t.Errorf("Expected the scroll offset to reset, got %d", model.leftScrollOffset)
+	}
+}
+
+func TestCopyMenu_CopiesColumnOrKeyValues(t *testing.T) {
+	var copied string
+	defer func(orig func(string)) { writeClipboard = orig }(writeClipboard)
+	writeClipboard = func(text string) { copied = text }
+
+	model := newIndexedTestModel(t, []string{
+		`127.0.0.1 - - [23/Dec/2023:15:30:45 +0000] "GET /api/orders HTTP/1.1" 503 1234`,
+	}, 160, 40)
+
+	model.Update(keyMsg("y"))
+	model.Update(keyMsg("c"))
+	if model.viewMode != CopyView {
+		t.Fatal("Expected yc to open the copy menu")
+	}
+	if view := model.View(); !strings.Contains(view, "status_code") || !strings.Contains(view, "all as key=value") {
+		t.Errorf("Expected columns and metadata in the copy menu, got:\n%s", view)
+	}
+
+	// Walk down to the status code
+	for i := 0; i < len(model.copyOptions); i++ {
+		if model.copyOptions[model.copyIdx].label == "status_code" {
+			break
+		}
+		model.Update(keyMsg("j"))
+	}
+	model.Update(keyMsg("enter"))
+	if copied != "503" || model.viewMode != LogStreamView {
+		t.Errorf("Expected the status code to be copied, got %q", copied)
+	}
+
+	// The last option copies the whole entry on one line
+	model.Update(keyMsg("y"))
+	model.Update(keyMsg("c"))
+	model.copyIdx = len(model.copyOptions) - 1
+	model.Update(keyMsg("enter"))
+	if !strings.Contains(copied, `level=ERROR msg="127.0.0.1 GET`) || !strings.Contains(copied, `request="GET /api/orders HTTP/1.1" response_size=1234 status_code=503`) {
+		t.Errorf("Unexpected key=value line %q", copied)
+	}
+
+	// c on its own does nothing
+	model.Update(keyMsg("c"))
+	if model.viewMode != LogStreamView {
+		t.Error("Expected c without y to be ignored")
+	}
+}
+
+func TestSearch_JumpsBetweenMatchesWithoutFiltering(t *testing.T) {
+	model := newIndexedTestModel(t, numberedLines(50), 160, 30)
+	selected := func() int { return model.viewportStart + model.selectedIdx }
+
+	model.Update(keyMsg("?"))
+	for _, r := range "line 4" {
+		model.Update(keyMsg(string(r)))
+	}
+	if view := model.renderLogStream(); !strings.Contains(view, "Search:") || !strings.Contains(view, "(1/11 found)") {
+		t.Errorf("Expected the search prompt with a match count, got:\n%s", view)
+	}
+	model.Update(keyMsg("enter"))
+
+	// Every row stays, the selection lands on "line 4"
+	if len(model.filteredIndices) != 50 {
+		t.Errorf("Expected search not to filter, got %d rows", len(model.filteredIndices))
+	}
+	if selected() != 3 {
+		t.Errorf("Expected the first match to be selected, got position %d", selected())
+	}
+
+	// n/N move between matches and wrap
+	model.Update(keyMsg("n"))
+	if selected() != 39 {
+		t.Errorf("Expected n to jump to line 40, got position %d", selected())
+	}
+	model.Update(keyMsg("N"))
+	model.Update(keyMsg("N"))
+	if selected() != 48 {
+		t.Errorf("Expected N to wrap to line 49, got position %d", selected())
+	}
+	if view := model.renderLogStream(); !strings.Contains(view, "(11/11 found)") {
+		t.Errorf("Expected the match position in the log stream, got:\n%s", view)
+	}
+
+	// Esc clears the search so n/N go back to include matches
+	model.Update(keyMsg("esc"))
+	if model.searchQuery != "" || len(model.searchMatches) != 0 {
+		t.Errorf("Expected Esc to clear the search, got %q", model.searchQuery)
+	}
+}
+
+func TestJumpToLevelChange(t *testing.T) {
+	lines := []string{
+		"2023-12-23 15:30:45 INFO: start",
+		"2023-12-23 15:30:46 INFO: working",
+		`{"level":"info","msg":"parsed to know its level"}`,
+		"2023-12-23 15:30:48 ERROR: failed",
+		"2023-12-23 15:30:49 ERROR: failed again",
+		"2023-12-23 15:30:50 INFO: recovered",
+	}
+	model := newIndexedTestModel(t, lines, 120, 40)
+	model.scrollToTop()
+	selected := func() int {
+		return model.filteredIndices[model.viewportStart+model.selectedIdx]
+	}
+
+	model.Update(keyMsg("]"))
+	if selected() != 3 || model.notice != "INFO → ERROR" {
+		t.Errorf("Expected the first ERROR line, got %d (%q)", selected(), model.notice)
+	}
+	model.Update(keyMsg("]"))
+	if selected() != 5 {
+		t.Errorf("Expected the INFO line after the errors, got %d", selected())
+	}
+	model.Update(keyMsg("]"))
+	if selected() != 5 || model.notice != "Only INFO entries after this one" {
+		t.Errorf("Expected to stay on the last line, got %d (%q)", selected(), model.notice)
+	}
+
+	model.Update(keyMsg("["))
+	if selected() != 4 {
+		t.Errorf("Expected the last ERROR line going back, got %d", selected())
+	}
+	model.Update(keyMsg("["))
+	if selected() != 2 {
+		t.Errorf("Expected the JSON INFO line before the errors, got %d", selected())
+	}
+}
+
+func TestBookmarks_SurviveFilterChanges(t *testing.T) {
+	lines := numberedLines(30)
+	lines[19] = "2023-12-23 15:30:45 ERROR: line 20 failed"
+	model := newIndexedTestModel(t, lines, 120, 40)
+	model.scrollToTop()
+	selected := func() int {
+		return model.filteredIndices[model.viewportStart+model.selectedIdx]
+	}
+
+	for _, line := range []int{4, 9, 19} {
+		model.jumpToPosition(line)
+		model.Update(keyMsg("m"))
+	}
+	if len(model.bookmarks) != 3 {
+		t.Fatalf("Expected 3 bookmarks, got %d", len(model.bookmarks))
+	}
+	if !strings.Contains(model.renderHeader(), "★ 3") {
+		t.Error("Expected the header to count the bookmarks")
+	}
+
+	model.scrollToTop()
+	model.Update(keyMsg("b"))
+	if selected() != 4 {
+		t.Errorf("Expected the first bookmark, got line %d", selected())
+	}
+	if !strings.Contains(model.renderLogStream(), "★ ") {
+		t.Error("Expected bookmarked rows to show a star")
+	}
+
+	// Line 10 is hidden by the filter, so b skips to line 20
+	model.Update(keyMsg("/"))
+	model.includeInput.SetValue("line 5|line 20")
+	model.useRegex = true
+	model.Update(keyMsg("enter"))
+	model.Update(keyMsg("tab"))
+	model.scrollToTop()
+	model.Update(keyMsg("b"))
+	if selected() != 19 {
+		t.Errorf("Expected the bookmark on line 20, got line %d", selected())
+	}
+	model.Update(keyMsg("b"))
+	if selected() != 19 || model.notice != "No bookmarks after this one" {
+		t.Errorf("Expected to stay on the last bookmark, got line %d (%q)", selected(), model.notice)
+	}
+	model.Update(keyMsg("B"))
+	if selected() != 4 {
+		t.Errorf("Expected the bookmark on line 5 going back, got line %d", selected())
+	}
+
+	model.Update(keyMsg("m"))
+	if model.bookmarks[4] {
+		t.Error("Expected m to remove the bookmark")
+	}
+}
+
+func TestSuppress_HidesIdenticalMessages(t *testing.T) {
+	lines := numberedLines(10)
+	for _, i := range []int{2, 5, 8} {
+		lines[i] = "2023-12-23 15:30:45 WARN: cache miss"
+	}
+	lines[6] = "2023-12-23 15:30:45 WARN: cache miss for user 7"
+	model := newIndexedTestModel(t, lines, 120, 40)
+	model.scrollToTop()
+
+	model.jumpToPosition(2)
+	model.Update(keyMsg("x"))
+	if len(model.filteredIndices) != 7 {
+		t.Fatalf("Expected the 3 identical lines hidden, got %d shown", len(model.filteredIndices))
+	}
+	for _, line := range model.filteredIndices {
+		if line == 2 || line == 5 || line == 8 {
+			t.Errorf("Expected line %d to be hidden", line+1)
+		}
+	}
+	if selected := model.filteredIndices[model.viewportStart+model.selectedIdx]; selected != 3 {
+		t.Errorf("Expected the next line to take the selection, got line %d", selected+1)
+	}
+	if !strings.Contains(model.renderHeader(), "3 hidden") {
+		t.Error("Expected the header to count the hidden lines")
+	}
+
+	// dd hides like x, a single d doesn't
+	model.jumpToPosition(0)
+	model.Update(keyMsg("d"))
+	if len(model.filteredIndices) != 7 {
+		t.Fatalf("Expected a single d to hide nothing, got %d shown", len(model.filteredIndices))
+	}
+	model.Update(keyMsg("d"))
+	if len(model.filteredIndices) != 6 || model.filterStats.suppressed != 4 {
+		t.Fatalf("Expected dd to hide line 1, got %d shown", len(model.filteredIndices))
+	}
+
+	model.Update(keyMsg("X"))
+	if len(model.filteredIndices) != 10 || len(model.suppressed) != 0 {
+		t.Errorf("Expected X to show every line again, got %d", len(model.filteredIndices))
+	}
+}
+
+func TestGotoLine_SelectsAndCentersLine(t *testing.T) {
+	model := newIndexedTestModel(t, numberedLines(100), 160, 30)
+	selected := func() int { return model.viewportStart + model.selectedIdx }
+	gotoLine := func(keys string) {
+		model.Update(keyMsg(":"))
+		for _, r := range keys {
+			model.Update(keyMsg(string(r)))
+		}
+		model.Update(keyMsg("enter"))
+	}
+
+	model.Update(keyMsg(":"))
+	if view := model.renderLogStream(); !strings.Contains(view, "Go to line:") {
+		t.Errorf("Expected the line number prompt, got:\n%s", view)
+	}
+	model.Update(keyMsg("esc"))
+
+	// Letters are ignored, the line is centered and flashed
+	gotoLine("4x2")
+	if selected() != 41 || model.viewportStart != 41-model.viewportHeight/2 {
+		t.Errorf("Expected line 42 selected and centered, got position %d from %d", selected(), model.viewportStart)
+	}
+	if model.tailing {
+		t.Error("Expected the jump to stop tailing")
+	}
+	if model.selectionStyle().GetBackground() != model.flashStyle.GetBackground() {
+		t.Error("Expected the target row to be highlighted")
+	}
+	model.Update(keyMsg("j"))
+	if model.selectionStyle().GetBackground() != model.selectedStyle.GetBackground() {
+		t.Error("Expected the highlight to end when the selection moves")
+	}
+
+	// Past the end clamps to the last line
+	gotoLine("999")
+	if selected() != 99 {
+		t.Errorf("Expected the last line, got position %d", selected())
+	}
+
+	// A filtered-out line goes to the next line shown
+	model.includeInput.SetValue("line 5")
+	model.applyFilters()
+	gotoLine("42")
+	if line, _ := model.selectedLine(); line != 49 {
+		t.Errorf("Expected line 50 to be selected, got line %d", line+1)
+	}
+	if !strings.Contains(model.notice, "filtered out") {
+		t.Errorf("Expected a notice about the filtered line, got %q", model.notice)
+	}
+}
+
+func TestEmptyFile_NavigationIsSafe(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "empty.log")
+	if err := os.WriteFile(testFile, nil, 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	chdirTemp(t)
+
+	app := NewUnifiedApp(&Config{MaxLines: 100, Files: []string{testFile}, RefreshRate: 1, Timezone: "UTC"})
+	app.indexFile(testFile)
+	model := app.model
+	model.Update(tea.WindowSizeMsg{Width: 160, Height: 30})
+
+	if model.totalLines != 0 {
+		t.Fatalf("Expected no lines, got %d", model.totalLines)
+	}
+	if view := model.View(); !strings.Contains(view, "empty.log is empty") {
+		t.Errorf("Expected an empty file notice, got:\n%s", view)
+	}
+
+	keys := []string{"j", "k", "G", "g", "g", "ctrl+d", "ctrl+u", "n", "N", "t", "enter", "esc",
+		"V", "E", "y", "c", "?", "x", "enter", "n", ":", "5", "enter", "v", "j", "tab", "tab", "j", "k", "tab", "f", "f"}
+	for _, key := range keys {
+		msg := keyMsg(key)
+		switch key {
+		case "ctrl+d":
+			msg = tea.KeyMsg{Type: tea.KeyCtrlD}
+		case "ctrl+u":
+			msg = tea.KeyMsg{Type: tea.KeyCtrlU}
+		}
+		model.Update(msg)
+		model.View()
+	}
+
+	if model.viewportStart != 0 || model.selectedIdx != 0 {
+		t.Errorf("Expected the viewport to stay at the top, got start %d selected %d", model.viewportStart, model.selectedIdx)
+	}
+}
+
+func TestFollowMatches_JumpsToNewMatchingLines(t *testing.T) {
+	m := newIndexedTestModel(t, numberedLines(50), 120, 30)
+	m.Update(keyMsg("F"))
+	if !m.followMatches || m.tailing {
+		t.Fatalf("Expected F to replace tailing with follow matches")
+	}
+	m.setSearchQuery("timeout")
+	m.jumpToPosition(0)
+
+	appendLines := func(lines ...string) {
+		t.Helper()
+		f, err := os.OpenFile(m.config.Files[0], os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Fatalf("Failed to open test file: %v", err)
+		}
+		f.WriteString(strings.Join(lines, "\n") + "\n")
+		f.Close()
+		m.Update(fileChangedMsg{filename: m.config.Files[0]})
+	}
+	selected := func() int {
+		return m.filteredIndices[m.viewportStart+m.selectedIdx]
+	}
+
+	// Lines that don't match leave the selection alone
+	appendLines("2023-12-23 15:31:00 INFO: healthy")
+	if selected() != 0 {
+		t.Errorf("Expected selection to stay on line 0, got %d", selected())
+	}
+
+	// The newest matching line is selected, later non-matching ones are ignored
+	appendLines(
+		"2023-12-23 15:31:01 ERROR: upstream timeout",
+		"2023-12-23 15:31:02 ERROR: db timeout",
+		"2023-12-23 15:31:03 INFO: healthy",
+	)
+	if selected() != 52 {
+		t.Errorf("Expected selection on line 52, got %d", selected())
+	}
+	if m.totalLines != 54 {
+		t.Errorf("Expected 54 lines, got %d", m.totalLines)
+	}
+
+	m.Update(keyMsg("t"))
+	if m.followMatches || !m.tailing {
+		t.Errorf("Expected t to switch back to tailing")
+	}
+}
+
+func TestFollow_FiltersOnlyAppendedLines(t *testing.T) {
+	m := newIndexedTestModel(t, numberedLines(20), 120, 30)
+	m.includeInput.SetValue("served")
+	m.setSearchQuery("slow")
+	m.applyFilters()
+
+	appendText := func(text string) {
+		t.Helper()
+		f, err := os.OpenFile(m.config.Files[0], os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Fatalf("Failed to open test file: %v", err)
+		}
+		f.WriteString(text)
+		f.Close()
+		m.Update(fileChangedMsg{filename: m.config.Files[0]})
+
+		// The incremental pass ends where filtering everything again does
+		filtered := fmt.Sprint(m.filteredIndices, m.matchedIndices, m.searchMatches, *m.filterStats)
+		m.applyFilters()
+		if again := fmt.Sprint(m.filteredIndices, m.matchedIndices, m.searchMatches, *m.filterStats); filtered != again {
+			t.Fatalf("After %q: expected %s after a full pass, got %s", text, again, filtered)
+		}
+	}
+
+	appendText("2023-12-23 15:31:00 INFO: request served slow\n2023-12-23 15:31:01 ERROR: failed\n")
+	// A line written in two parts is filtered again once it's complete
+	appendText("2023-12-23 15:31:02 INFO: request")
+	appendText(" served\n")
+	if len(m.filteredIndices) != 2 || m.filteredIndices[1] != 22 {
+		t.Errorf("Expected the completed line shown, got %v", m.filteredIndices)
+	}
+}
+
+func TestPause_FreezesViewUntilResumed(t *testing.T) {
+	m := newIndexedTestModel(t, numberedLines(50), 120, 30)
+	m.Update(keyMsg("p"))
+	if !m.paused || m.tailing {
+		t.Fatalf("Expected p to pause tailing")
+	}
+	start, selected := m.viewportStart, m.selectedIdx
+
+	f, err := os.OpenFile(m.config.Files[0], os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open test file: %v", err)
+	}
+	f.WriteString("2023-12-23 15:31:00 INFO: new 1\n2023-12-23 15:31:01 INFO: new 2\n")
+	f.Close()
+	m.Update(fileChangedMsg{filename: m.config.Files[0]})
+
+	if m.totalLines != 52 {
+		t.Errorf("Expected the new lines to be indexed while paused, got %d lines", m.totalLines)
+	}
+	if m.viewportStart != start || m.selectedIdx != selected {
+		t.Errorf("Expected the view to stay put, got start %d selected %d", m.viewportStart, m.selectedIdx)
+	}
+	if header := m.renderHeader(); !strings.Contains(header, "PAUSED (2 new)") {
+		t.Errorf("Expected a paused badge counting new lines, got %q", header)
+	}
+
+	m.Update(keyMsg(" "))
+	if m.paused || !m.tailing || m.pausedLines != 0 {
+		t.Fatalf("Expected space to resume tailing")
+	}
+	if last := m.filteredIndices[m.viewportStart+m.selectedIdx]; last != 51 {
+		t.Errorf("Expected the newest line selected, got %d", last)
+	}
+}
+
+func TestPause_StreamKeepsBufferingWithinMaxLines(t *testing.T) {
+	model := NewUnifiedModel(&Config{MaxLines: 3, RefreshRate: 1, Timezone: "UTC"})
+	model.Update(keyMsg("p"))
+
+	for i := 0; i < 5; i++ {
+		model.AddLogEntry(LogEntry{Level: INFO, Message: fmt.Sprintf("line %d", i)})
+	}
+	if model.pausedLines != 5 {
+		t.Errorf("Expected 5 lines counted while paused, got %d", model.pausedLines)
+	}
+	if len(model.entries) != 3 || model.entries[0].Message != "line 2" {
+		t.Errorf("Expected the newest 3 entries kept, got %v", model.entries)
+	}
+}
+
+func TestTailing_KeptWhileEditingInclude(t *testing.T) {
+	lines := numberedLines(100)
+	for i := 0; i < len(lines); i += 10 {
+		lines[i] = fmt.Sprintf("2023-12-23 15:30:45 ERROR: line %d failed", i+1)
+	}
+	model := newIndexedTestModel(t, lines, 120, 20)
+	model.scrollToBottom()
+
+	// Refine the include filter while tailing, one key at a time
+	model.Update(keyMsg("/"))
+	for _, key := range "fail" {
+		model.Update(keyMsg(string(key)))
+	}
+	model.Update(keyMsg("enter"))
+
+	if !model.tailing {
+		t.Fatal("Expected editing the include filter to keep tailing")
+	}
+	if len(model.filteredIndices) != 10 {
+		t.Fatalf("Expected 10 matching lines, got %d", len(model.filteredIndices))
+	}
+	if selected := model.filteredIndices[model.viewportStart+model.selectedIdx]; selected != 90 {
+		t.Errorf("Expected the newest match (line 90) selected, got %d", selected)
+	}
+
+	// Widening the filter again re-pins to the bottom
+	model.Update(keyMsg("/"))
+	model.includeInput.SetValue("")
+	model.Update(keyMsg("enter"))
+	if selected := model.filteredIndices[model.viewportStart+model.selectedIdx]; selected != 99 {
+		t.Errorf("Expected the last line selected, got %d", selected)
+	}
+}
+
+func TestClearPatterns_UndoRestoresBoth(t *testing.T) {
+	model := newIndexedTestModel(t, numberedLines(20), 120, 20)
+	model.includeInput.SetValue("line 1")
+	model.excludeInput.SetValue("line 12")
+	model.applyFilters()
+	filtered := len(model.filteredIndices)
+
+	model.Update(keyMsg("c"))
+	if model.includeInput.Value() != "" || model.excludeInput.Value() != "" {
+		t.Fatal("Expected c to clear both patterns")
+	}
+	if len(model.filteredIndices) != 20 {
+		t.Fatalf("Expected all 20 lines after clearing, got %d", len(model.filteredIndices))
+	}
+
+	model.Update(keyMsg("u"))
+	if model.includeInput.Value() != "line 1" || model.excludeInput.Value() != "line 12" {
+		t.Errorf("Expected both patterns restored, got %q and %q", model.includeInput.Value(), model.excludeInput.Value())
+	}
+	if len(model.filteredIndices) != filtered {
+		t.Errorf("Expected %d lines after undo, got %d", filtered, len(model.filteredIndices))
+	}
+}
+
+func TestClearPatterns_UndoAfterEmptyingInput(t *testing.T) {
+	model := newIndexedTestModel(t, numberedLines(20), 120, 20)
+	model.includeInput.SetValue("line 1")
+	model.applyFilters()
+
+	model.Update(keyMsg("/"))
+	model.includeInput.SetValue("")
+	model.Update(keyMsg("enter"))
+	if model.clearedPatterns == nil {
+		t.Fatal("Expected emptying the include input to be undoable")
+	}
+
+	model.Update(keyMsg("u"))
+	if model.includeInput.Value() != "line 1" {
+		t.Errorf("Expected the include pattern restored, got %q", model.includeInput.Value())
+	}
+}
+
+func TestHelpOverlay_ListsEveryBinding(t *testing.T) {
+	model := newIndexedTestModel(t, numberedLines(10), 160, 60)
+
+	model.Update(keyMsg("h"))
+	view := ansi.Strip(model.View())
+	for _, binding := range keyBindings {
+		if !strings.Contains(view, binding.help) {
+			t.Errorf("Expected the help to list %q", binding.help)
+		}
+	}
+
+	// Keys don't reach the list while it's open
+	selected := model.viewportStart + model.selectedIdx
+	model.Update(keyMsg("k"))
+	if !model.showHelp || model.viewportStart+model.selectedIdx != selected {
+		t.Error("Expected the help to swallow keys")
+	}
+	model.Update(keyMsg("esc"))
+	if model.showHelp {
+		t.Error("Expected esc to close the help")
+	}
+
+	// Typed into a filter, h is just a letter
+	model.Update(keyMsg("/"))
+	model.Update(keyMsg("h"))
+	if model.showHelp || model.includeInput.Value() != "h" {
+		t.Errorf("Expected h typed into the include filter, got %q", model.includeInput.Value())
+	}
+}
+
+func TestMinLevel_OverridesToggles(t *testing.T) {
+	lines := []string{
+		"2023-12-23 15:30:45 DEBUG: one",
+		"2023-12-23 15:30:45 INFO: two",
+		"2023-12-23 15:30:45 WARN: three",
+		"2023-12-23 15:30:45 ERROR: four",
+	}
+	model := newIndexedTestModel(t, lines, 120, 40)
+
+	model.Update(keyMsg("+"))
+	model.Update(keyMsg("+"))
+	if !model.levelThreshold || model.minLevel != WARN || len(model.filteredIndices) != 2 {
+		t.Fatalf("Expected WARN and above, got %v with %d lines", model.minLevel, len(model.filteredIndices))
+	}
+	if model.showInfo || !model.showWarn {
+		t.Error("Expected the toggles to follow the minimum level")
+	}
+	if !strings.Contains(model.renderLeftPanel(), "Minimum: ") || !strings.Contains(ansi.Strip(model.renderLeftPanel()), "WARN and above") {
+		t.Error("Expected the left panel to show the minimum level")
+	}
+
+	// Toggling DEBUG hands control back to the toggles
+	model.focus = LeftPanel
+	model.leftPanelItem = debugItem
+	model.Update(keyMsg("enter"))
+	if model.levelThreshold || len(model.filteredIndices) != 3 {
+		t.Fatalf("Expected the toggles to take over, got %d lines", len(model.filteredIndices))
+	}
+	if !strings.Contains(model.renderLeftPanel(), "Minimum: off") {
+		t.Error("Expected the left panel to show the toggles are in charge")
+	}
+
+	// From the toggles, - starts below the least severe level shown
+	model.Update(keyMsg("-"))
+	if model.minLevel != DEBUG || len(model.filteredIndices) != 4 {
+		t.Errorf("Expected DEBUG and above, got %v with %d lines", model.minLevel, len(model.filteredIndices))
+	}
+
+	// Space on the minimum line cycles up to ERROR and then off
+	model.leftPanelItem = minLevelItem
+	for _, expected := range []LogLevel{INFO, WARN, ERROR} {
+		model.Update(keyMsg("enter"))
+		if model.minLevel != expected {
+			t.Errorf("Expected %v, got %v", expected, model.minLevel)
+		}
+	}
+	model.Update(keyMsg("enter"))
+	if model.levelThreshold {
+		t.Error("Expected the cycle to end with the minimum off")
+	}
+}
+
+func TestLineReference_CopiesFileAndLine(t *testing.T) {
+	var copied string
+	defer func(orig func(string)) { writeClipboard = orig }(writeClipboard)
+	writeClipboard = func(text string) { copied = text }
+
+	lines := numberedLines(30)
+	lines[23] = "2023-12-23 15:30:45 ERROR: line 24 failed"
+	model := newIndexedTestModel(t, lines, 160, 40)
+
+	// Only the error is shown, still referenced by its line in the file
+	model.Update(keyMsg("/"))
+	for _, r := range "failed" {
+		model.Update(keyMsg(string(r)))
+	}
+	model.Update(keyMsg("enter"))
+	model.Update(keyMsg("tab"))
+	model.scrollToTop()
+
+	model.Update(keyMsg("y"))
+	model.Update(keyMsg("l"))
+	expected := model.loadingFile + ":24"
+	if copied != expected {
+		t.Errorf("Expected %q to be copied, got %q", expected, copied)
+	}
+
+	model.Update(keyMsg("enter"))
+	if detail := model.renderDetailPanel(); !strings.Contains(detail, "Line:      "+expected) {
+		t.Errorf("Expected the detail view to show the reference, got:\n%s", detail)
+	}
+}
+
+func TestKeepColors_ShowsSourceColors(t *testing.T) {
+	lines := []string{
+		"2023-12-23 15:30:45 \x1b[31merror\x1b[0m: test \x1b[1mfailed\x1b[0m\x1b[K",
+		"2023-12-23 15:30:46 INFO: plain",
+	}
+	model := newIndexedTestModel(t, lines, 160, 40)
+	entry := model.visibleEntries[0]
+	red := "\x1b[31merror\x1b[0m"
+
+	if row := model.formatColumnLogEntry(entry, false, false); strings.Contains(row, red) {
+		t.Error("Expected the colors to be stripped without --keep-colors")
+	}
+
+	model.config.KeepColors = true
+	row := model.formatColumnLogEntry(entry, false, false)
+	if !strings.Contains(row, red) || !strings.HasSuffix(row, ansiReset) {
+		t.Errorf("Expected the source colors in the row, got %q", row)
+	}
+	if strings.Contains(row, "\x1b[K") {
+		t.Error("Expected escapes other than colors to be dropped")
+	}
+	if detail := model.renderEntryDetail(entry, 0, 10); !strings.Contains(detail, red) {
+		t.Errorf("Expected the source colors in the detail view, got %q", detail)
+	}
+
+	// Matching still sees the plain text, and the selection row stays plain
+	model.Update(keyMsg("/"))
+	for _, r := range "error: test" {
+		model.Update(keyMsg(string(r)))
+	}
+	model.Update(keyMsg("enter"))
+	if len(model.filteredIndices) != 1 {
+		t.Errorf("Expected the include filter to match the plain text, got %d lines", len(model.filteredIndices))
+	}
+	if row := model.formatColumnLogEntry(entry, true, false); strings.Contains(row, red) {
+		t.Error("Expected the selected row to use the plain text")
+	}
+}
+
+func TestFilterHistory_RecallsAppliedPatterns(t *testing.T) {
+	model := newIndexedTestModel(t, numberedLines(20), 120, 30)
+	apply := func(pattern string) {
+		model.Update(keyMsg("/"))
+		model.includeInput.SetValue(pattern)
+		model.Update(keyMsg("enter"))
+	}
+	apply("line 1")
+	apply("line 2")
+	apply("line 1") // Moves to the end instead of repeating
+	if strings.Join(model.includeHistory, "|") != "line 2|line 1" {
+		t.Fatalf("Unexpected history %q", model.includeHistory)
+	}
+
+	model.Update(keyMsg("/"))
+	model.includeInput.SetValue("draft")
+	for _, step := range []struct {
+		key      string
+		expected string
+	}{
+		{"up", "line 1"},
+		{"up", "line 2"},
+		{"up", "line 2"}, // Stays on the oldest
+		{"down", "line 1"},
+		{"down", "draft"},
+	} {
+		model.Update(keyMsg(step.key))
+		if got := model.includeInput.Value(); got != step.expected {
+			t.Errorf("%s: expected %q, got %q", step.key, step.expected, got)
+		}
+	}
+
+	// The history is saved with the filters
+	model.Update(keyMsg("esc"))
+	if state := model.filterState(); strings.Join(state.IncludeHistory, "|") != "line 2|line 1" || len(state.ExcludeHistory) != 0 {
+		t.Errorf("Expected the include history in the saved state, got %+v", state)
+	}
+}
+
+// screenPosition finds where text is drawn on screen, as mouse coordinates
+func screenPosition(t *testing.T, model *UnifiedModel, text string) (int, int) {
+	t.Helper()
+	for y, line := range strings.Split(model.View(), "\n") {
+		if x := strings.Index(ansi.Strip(line), text); x >= 0 {
+			return ansi.StringWidth(ansi.Strip(line)[:x]), y
+		}
+	}
+	t.Fatalf("%q is not on screen", text)
+	return 0, 0
+}
+
+func TestMouse_SelectsAndScrolls(t *testing.T) {
+	model := newIndexedTestModel(t, numberedLines(50), 120, 40)
+	model.Update(keyMsg("tab"))
+	model.scrollToTop()
+
+	click := func(text string) {
+		x, y := screenPosition(t, model, text)
+		model.Update(tea.MouseMsg{X: x, Y: y, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	}
+
+	click("line 5")
+	if model.selectedIdx != 4 || model.viewMode != LogStreamView {
+		t.Fatalf("Expected a click to select line 5, got index %d", model.selectedIdx)
+	}
+	click("line 5")
+	if model.viewMode != DetailView {
+		t.Fatal("Expected a double click to open the detail view")
+	}
+	model.Update(keyMsg("esc"))
+
+	model.Update(tea.MouseMsg{Button: tea.MouseButtonWheelDown, Action: tea.MouseActionPress})
+	if model.selectedIdx != 5 {
+		t.Errorf("Expected the wheel to move the selection down, got index %d", model.selectedIdx)
+	}
+
+	// A checkbox toggles and takes the focus
+	click("Use Regex")
+	if !model.useRegex || model.focus != LeftPanel || model.leftPanelItem != regexItem {
+		t.Errorf("Expected the click to toggle regex, got %v on item %d", model.useRegex, model.leftPanelItem)
+	}
+	click("Exclude Pattern")
+	if model.leftPanelItem != excludeItem || !model.useRegex {
+		t.Errorf("Expected the click to select the exclude pattern, got item %d", model.leftPanelItem)
+	}
+}
+
+func TestTail_StartsOnLastLines(t *testing.T) {
+	for _, tc := range []struct {
+		tail          int
+		tailing       bool
+		viewportStart int
+	}{
+		{defaultTail, true, 100 - 10}, // Fewer than a page: the bottom page
+		{40, true, 60},                // The first of the last 40
+		{0, false, 0},                 // From the first line
+	} {
+		config := &Config{Files: []string{writeTestLog(t, numberedLines(100))}, RefreshRate: 1, Timezone: "UTC", Tail: tc.tail}
+		app := NewUnifiedApp(config)
+		app.model.Update(tea.WindowSizeMsg{Width: 120, Height: 20})
+		app.indexFile(config.Files[0])
+		model := app.model
+
+		if model.tailing != tc.tailing || model.viewportStart != tc.viewportStart {
+			t.Errorf("--tail %d: expected tailing %v from %d, got %v from %d", tc.tail, tc.tailing, tc.viewportStart, model.tailing, model.viewportStart)
+		}
+	}
+
+	// Piped lines are all kept, the view starts on the last of them
+	app := NewUnifiedApp(&Config{MaxLines: 1000, RefreshRate: 1, Timezone: "UTC", Tail: 40})
+	app.model.Update(tea.WindowSizeMsg{Width: 120, Height: 20})
+	app.streamFrom(strings.NewReader(strings.Join(numberedLines(100), "\n")), "stdin")
+	app.model.Update(streamReadyMsg{})
+	if model := app.model; len(model.entries) != 100 || !model.tailing || model.viewportStart != 60 {
+		t.Errorf("Expected 100 entries shown from 60, got %d from %d", len(model.entries), model.viewportStart)
+	}
+}
+
+func TestLeftPanel_ResizeAndCollapse(t *testing.T) {
+	model := newIndexedTestModel(t, numberedLines(10), 120, 30)
+	if model.leftWidth != 36 || model.rightWidth != 84 {
+		t.Fatalf("Expected 30%% of the terminal by default, got %d and %d", model.leftWidth, model.rightWidth)
+	}
+
+	model.Update(keyMsg(">"))
+	if model.leftWidth != 40 || model.rightWidth != 80 || model.filterState().LeftWidth != 40 {
+		t.Errorf("Expected > to widen the panel and save the width, got %d", model.leftWidth)
+	}
+	for i := 0; i < 10; i++ {
+		model.Update(keyMsg("<"))
+	}
+	if model.leftWidth != minLeftWidth {
+		t.Errorf("Expected the panel no narrower than %d, got %d", minLeftWidth, model.leftWidth)
+	}
+
+	// The chosen width outlives a resize, within what the terminal allows
+	model.Update(tea.WindowSizeMsg{Width: 200, Height: 30})
+	if model.leftWidth != minLeftWidth || model.rightWidth != 180 {
+		t.Errorf("Expected the chosen width kept, got %d and %d", model.leftWidth, model.rightWidth)
+	}
+	model.leftPref = 70
+	model.Update(tea.WindowSizeMsg{Width: 90, Height: 30})
+	if model.leftWidth != 50 || model.rightWidth != 40 {
+		t.Errorf("Expected the log stream to keep %d columns, got %d and %d", minRightWidth, model.leftWidth, model.rightWidth)
+	}
+
+	model.Update(keyMsg("f"))
+	if model.rightWidth != 90 || strings.Contains(model.View(), "SEARCH & FILTERS") {
+		t.Error("Expected f to collapse the filters panel")
+	}
+	model.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	if model.rightWidth != 100 {
+		t.Errorf("Expected a collapsed panel to stay collapsed on resize, got %d", model.rightWidth)
+	}
+	model.Update(keyMsg(">"))
+	if model.fullscreen || model.leftWidth != 60 {
+		t.Errorf("Expected > to bring the panel back at its width, got %d", model.leftWidth)
+	}
+
+	// Saved with the filters
+	model.setFilterState(FilterState{LeftWidth: 30})
+	model.layoutPanels()
+	if model.leftWidth != 30 {
+		t.Errorf("Expected the saved width restored, got %d", model.leftWidth)
+	}
+}